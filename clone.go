@@ -3,12 +3,20 @@ package skillz
 import (
 	"context"
 	"time"
+
+	"github.com/volatiletech/null"
 )
 
 type CloneRepository interface {
 	CharacterImplants(ctx context.Context, characterID uint64) ([]*CharacterImplant, error)
 	CreateCharacterImplants(ctx context.Context, implants []*CharacterImplant) error
 	DeleteCharacterImplants(ctx context.Context, characterID uint64) error
+
+	CharacterJumpClones(ctx context.Context, characterID uint64) ([]*CharacterJumpClone, error)
+	CharacterJumpCloneImplants(ctx context.Context, characterID uint64) ([]*CharacterJumpCloneImplant, error)
+	CreateCharacterJumpClones(ctx context.Context, clones []*CharacterJumpClone) error
+	CreateCharacterJumpCloneImplants(ctx context.Context, implants []*CharacterJumpCloneImplant) error
+	DeleteCharacterJumpClones(ctx context.Context, characterID uint64) error
 }
 
 type CharacterImplant struct {
@@ -19,3 +27,45 @@ type CharacterImplant struct {
 
 	Type *Type `json:"info,omitempty"`
 }
+
+type LocationType string
+
+const (
+	StationLocationType   LocationType = "station"
+	StructureLocationType LocationType = "structure"
+)
+
+// CharacterJumpClone is a clone the character can jump to and the location it is stored at
+type CharacterJumpClone struct {
+	CharacterID  uint64       `db:"character_id" json:"character_id"`
+	JumpCloneID  uint         `db:"jump_clone_id" json:"jump_clone_id"`
+	Name         null.String  `db:"name,omitempty" json:"name,omitempty"`
+	LocationID   uint64       `db:"location_id" json:"location_id"`
+	LocationType LocationType `db:"location_type" json:"location_type"`
+	CreatedAt    time.Time    `db:"created_at" json:"-"`
+
+	Implants  []*CharacterJumpCloneImplant `json:"implants"`
+	Station   *Station                     `json:"station,omitempty"`
+	Structure *Structure                   `json:"structure,omitempty"`
+
+	// LocationName is the name of the station or structure the clone is stored at. It is empty
+	// when the location could not be resolved, i.e. the character does not have access to the structure
+	LocationName string `json:"location_name"`
+
+	// Bonuses are the attribute bonuses of the clone's implants and QueueDuration is how long the character's
+	// skill queue would take to train in this clone. BestForTraining is set on the clone that trains the queue
+	// the fastest or, when the queue is empty, has the largest attribute bonuses
+	Bonuses         *CharacterAttributes `json:"bonuses,omitempty"`
+	QueueDuration   time.Duration        `json:"queue_duration"`
+	BestForTraining bool                 `json:"best_for_training"`
+}
+
+type CharacterJumpCloneImplant struct {
+	CharacterID uint64    `db:"character_id" json:"character_id"`
+	JumpCloneID uint      `db:"jump_clone_id" json:"jump_clone_id"`
+	ImplantID   uint      `db:"implant_id" json:"implant_id"`
+	Slot        uint      `db:"slot" json:"slot"`
+	CreatedAt   time.Time `db:"created_at" json:"-"`
+
+	Type *Type `json:"info,omitempty"`
+}
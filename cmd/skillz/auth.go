@@ -1,6 +1,8 @@
 package main
 
 import (
+	"net/url"
+
 	"golang.org/x/oauth2"
 )
 
@@ -9,8 +11,8 @@ func oauth2Config() *oauth2.Config {
 		ClientID:     cfg.Eve.ClientID,
 		ClientSecret: cfg.Eve.ClientSecret,
 		Endpoint: oauth2.Endpoint{
-			AuthURL:  "https://login.eveonline.com/v2/oauth/authorize",
-			TokenURL: "https://login.eveonline.com/v2/oauth/token",
+			AuthURL:  cfg.Eve.SSOBaseURI.ResolveReference(&url.URL{Path: "/v2/oauth/authorize"}).String(),
+			TokenURL: cfg.Eve.SSOBaseURI.ResolveReference(&url.URL{Path: "/v2/oauth/token"}).String(),
 		},
 		RedirectURL: cfg.Eve.CallbackURI.String(),
 	}
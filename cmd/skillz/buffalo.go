@@ -7,9 +7,12 @@ import (
 	"github.com/eveisesi/skillz/internal/cache"
 	"github.com/eveisesi/skillz/internal/character"
 	"github.com/eveisesi/skillz/internal/clone"
+	"github.com/eveisesi/skillz/internal/contact"
 	"github.com/eveisesi/skillz/internal/corporation"
+	"github.com/eveisesi/skillz/internal/doctrine"
 	"github.com/eveisesi/skillz/internal/esi"
 	"github.com/eveisesi/skillz/internal/etag"
+	"github.com/eveisesi/skillz/internal/fitting"
 	"github.com/eveisesi/skillz/internal/mysql"
 	"github.com/eveisesi/skillz/internal/processor"
 	"github.com/eveisesi/skillz/internal/skill"
@@ -53,31 +56,38 @@ func buffaloCmd(c *cli.Context) error {
 	corporationRepo := mysql.NewCorporationRepository(mysqlClient)
 	etagRepo := mysql.NewETagRepository(mysqlClient)
 	cloneRepo := mysql.NewCloneRepository(mysqlClient)
+	contactRepo := mysql.NewContactRepository(mysqlClient)
 	skillzRepo := mysql.NewSkillRepository(mysqlClient)
+	doctrineRepo := mysql.NewDoctrineRepository(mysqlClient)
 	userRepo := mysql.NewUserRepository(mysqlClient)
 	universeRepo := mysql.NewUniverseRepository(mysqlClient)
 
 	cache := cache.New(redisClient, cfg.Redis.DisableCache == 1)
 	etag := etag.New(cache, etagRepo)
-	esi := esi.New(httpClient(), redisClient, logger, etag)
+	esi := esi.New(httpClient(), redisClient, logger, etag, cfg.Eve.ESIBaseURI)
 	character := character.New(logger, cache, esi, etag, characterRepo)
 	corporation := corporation.New(logger, cache, esi, etag, corporationRepo)
 	alliance := alliance.New(logger, cache, esi, etag, allianceRepo)
 	universe := universe.New(logger, cache, esi, universeRepo)
 	clone := clone.New(logger, cache, etag, esi, universe, cloneRepo)
-	skills := skill.New(logger, cache, esi, universe, skillzRepo)
+	contact := contact.New(logger, cache, etag, esi, character, corporation, alliance, contactRepo)
+	skills := skill.New(logger, cache, esi, universe, clone, skillzRepo)
+	fittings := fitting.New(logger, universe, skills)
+	doctrines := doctrine.New(logger, character, corporation, universe, fittings, skills, doctrineRepo, userRepo)
 
 	auth := auth.New(
 		skillz.EnvironmentFromString(cfg.Environment),
 		httpClient(),
 		cache,
 		oauth2Config(),
+		cfg.Eve.SSOBaseURI,
 	)
 
-	user := user.New(redisClient, logger, cache, auth, alliance, character, corporation, skills, clone, userRepo)
-	processor := processor.New(logger, redisClient, nr, user, skillz.ScopeProcessors{
+	user := user.New(redisClient, logger, cache, auth, alliance, character, corporation, skills, clone, contact, userRepo)
+	processor := processor.New(logger, redisClient, nr, esi, user, skills, skillz.ScopeProcessors{
 		clone,
 		skills,
+		contact,
 	})
 
 	return web.NewService(
@@ -86,6 +96,9 @@ func buffaloCmd(c *cli.Context) error {
 		logger,
 		auth,
 		user,
+		skills,
+		fittings,
+		doctrines,
 		processor,
 		renderer(),
 		nr,
@@ -23,6 +23,10 @@ type config struct {
 		DisableCache uint   `envconfig:"DISABLE_CACHE" required:"true"`
 	}
 
+	Processor struct {
+		Workers int `envconfig:"PROCESSOR_WORKERS" default:"4"`
+	}
+
 	Log struct {
 		Level string `envconfig:"LOG_LEVEL" required:"true"`
 	}
@@ -33,6 +37,13 @@ type config struct {
 		CallbackURIStr     string `envconfig:"EVE_CALLBACK_URI" required:"true"`
 		CallbackURI        *url.URL
 		InitializeUniverse uint `envconfig:"INITIALIZE_UNIVERSE" required:"true"`
+
+		// ESIBaseURI and SSOBaseURI point the application at ESI and the SSO. They only
+		// need to be changed to run the application against a fake ESI and SSO
+		ESIBaseURIStr string `envconfig:"EVE_ESI_BASE_URI" default:"https://esi.evetech.net"`
+		ESIBaseURI    *url.URL
+		SSOBaseURIStr string `envconfig:"EVE_SSO_BASE_URI" default:"https://login.eveonline.com"`
+		SSOBaseURI    *url.URL
 	}
 
 	SessionName string `envconfig:"SESSION_NAME" default:"__skillboard_session"`
@@ -58,4 +69,18 @@ func buildConfig() {
 
 	cfg.Eve.CallbackURI = callbackURI
 
+	esiBaseURI, err := url.Parse(cfg.Eve.ESIBaseURIStr)
+	if err != nil {
+		panic(errors.Wrap(err, "failed to parse EVE_ESI_BASE_URI as a valid URI"))
+	}
+
+	cfg.Eve.ESIBaseURI = esiBaseURI
+
+	ssoBaseURI, err := url.Parse(cfg.Eve.SSOBaseURIStr)
+	if err != nil {
+		panic(errors.Wrap(err, "failed to parse EVE_SSO_BASE_URI as a valid URI"))
+	}
+
+	cfg.Eve.SSOBaseURI = ssoBaseURI
+
 }
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v2"
+)
+
+func init() {
+	commands = append(
+		commands,
+		&cli.Command{
+			Name:        "deadletter",
+			Description: "Inspect and requeue users that have failed to be processed too many times",
+			Subcommands: []*cli.Command{
+				{
+					Name:   "list",
+					Usage:  "List the users on the dead letter queue",
+					Action: deadLetterListCommand,
+				},
+				{
+					Name:      "requeue",
+					Usage:     "Push users on the dead letter queue back onto the update queue",
+					ArgsUsage: "[userID...]",
+					Flags: []cli.Flag{
+						&cli.BoolFlag{
+							Name:  "all",
+							Usage: "requeue every user on the dead letter queue",
+						},
+					},
+					Action: deadLetterRequeueCommand,
+				},
+			},
+		},
+	)
+}
+
+func deadLetterListCommand(c *cli.Context) error {
+
+	letters, err := buildProcessor().DeadLetters(c.Context)
+	if err != nil {
+		return err
+	}
+
+	if len(letters) == 0 {
+		fmt.Println("dead letter queue is empty")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "USER ID\tATTEMPTS\tFAILED AT\tERROR")
+	for _, letter := range letters {
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", letter.UserID, letter.Attempts, letter.FailedAt.UTC().Format("2006-01-02 15:04:05"), letter.Error)
+	}
+
+	return w.Flush()
+
+}
+
+func deadLetterRequeueCommand(c *cli.Context) error {
+
+	processor := buildProcessor()
+
+	userIDs := c.Args().Slice()
+	if c.Bool("all") {
+		letters, err := processor.DeadLetters(c.Context)
+		if err != nil {
+			return err
+		}
+
+		userIDs = make([]string, 0, len(letters))
+		for _, letter := range letters {
+			userIDs = append(userIDs, letter.UserID)
+		}
+	}
+
+	if len(userIDs) == 0 {
+		return fmt.Errorf("provide the ids of the users to requeue or use --all")
+	}
+
+	requeued, err := processor.Requeue(c.Context, userIDs...)
+	if err != nil {
+		return err
+	}
+
+	logger.WithField("requeued", requeued).Info("requeued dead lettered users")
+
+	return nil
+
+}
@@ -3,10 +3,13 @@ package main
 import (
 	"context"
 
+	"github.com/eveisesi/skillz"
 	"github.com/eveisesi/skillz/internal/cache"
 	"github.com/eveisesi/skillz/internal/esi"
 	"github.com/eveisesi/skillz/internal/etag"
 	"github.com/eveisesi/skillz/internal/mysql"
+	"github.com/eveisesi/skillz/internal/skill"
+	"github.com/eveisesi/skillz/internal/universe"
 	"github.com/urfave/cli/v2"
 )
 
@@ -28,10 +31,18 @@ func importCmd(c *cli.Context) error {
 	etagRepo := mysql.NewETagRepository(mysqlClient)
 
 	etag := etag.New(cache, etagRepo)
-	esi := esi.New(httpClient(), redisClient, logger, etag)
+	esi := esi.New(httpClient(), redisClient, logger, etag, cfg.Eve.ESIBaseURI)
 
 	var ctx = context.Background()
-	for _, categoryID := range []uint{6, 16, 20} {
+	// Skills are imported ahead of ships since ship flight requirements reference the skill types.
+	// Modules, charges, drones, subsystems and fighters are imported so that fittings can be resolved
+	categoryIDs := []uint{
+		universe.CategorySkills, universe.CategoryShips, universe.CategoryImplants,
+		universe.CategoryModules, universe.CategoryCharges, universe.CategoryDrones,
+		universe.CategorySubsystems, universe.CategoryFighters,
+	}
+
+	for _, categoryID := range categoryIDs {
 
 		entry := logger.WithField("categoryID", categoryID)
 
@@ -84,6 +95,13 @@ func importCmd(c *cli.Context) error {
 					}
 				}
 
+				if categoryID == universe.CategoryShips {
+					err = importShipFlightRequirements(ctx, universeRepo, item)
+					if err != nil {
+						entry.WithError(err).Fatal("failed to create ship flight requirements in data store")
+					}
+				}
+
 				logger.Info("successfully processed type")
 
 			}
@@ -95,6 +113,34 @@ func importCmd(c *cli.Context) error {
 
 }
 
+// importShipFlightRequirements replaces the flight requirements of a ship with the
+// required skills found in the ship's dogma attributes
+func importShipFlightRequirements(ctx context.Context, universeRepo skillz.UniverseRepository, ship *skillz.Type) error {
+
+	err := universeRepo.DeleteShipFlightRequirements(ctx, ship.ID)
+	if err != nil {
+		return err
+	}
+
+	required := skill.RequiredSkills(ship)
+	if len(required) == 0 {
+		return nil
+	}
+
+	requirements := make([]*skillz.ShipFlightRequirement, 0, len(required))
+	for _, requirement := range required {
+		requirements = append(requirements, &skillz.ShipFlightRequirement{
+			GroupID:           ship.GroupID,
+			ShipID:            ship.ID,
+			SkillID:           requirement.SkillID,
+			MinimumSkillLevel: requirement.Level,
+		})
+	}
+
+	return universeRepo.CreateShipFlightRequirements(ctx, requirements)
+
+}
+
 // func importMap(_ *cli.Context) error {
 
 // 	universeRepo := mysql.NewUniverseRepository(mysqlClient)
@@ -104,7 +150,7 @@ func importCmd(c *cli.Context) error {
 
 // 	etag := etag.New(cache, etagRepo)
 
-// 	esi := esi.New(httpClient(), redisClient, logger, etag)
+// 	esi := esi.New(httpClient(), redisClient, logger, etag, cfg.Eve.ESIBaseURI)
 
 // 	var ctx = context.Background()
 
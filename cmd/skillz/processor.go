@@ -1,12 +1,17 @@
 package main
 
 import (
+	"context"
+	"os/signal"
+	"syscall"
+
 	"github.com/eveisesi/skillz"
 	"github.com/eveisesi/skillz/internal/alliance"
 	"github.com/eveisesi/skillz/internal/auth"
 	"github.com/eveisesi/skillz/internal/cache"
 	"github.com/eveisesi/skillz/internal/character"
 	"github.com/eveisesi/skillz/internal/clone"
+	"github.com/eveisesi/skillz/internal/contact"
 	"github.com/eveisesi/skillz/internal/corporation"
 	"github.com/eveisesi/skillz/internal/esi"
 	"github.com/eveisesi/skillz/internal/etag"
@@ -28,16 +33,27 @@ func init() {
 
 func processorCommand(c *cli.Context) error {
 
+	// Workers finish the user they are processing before the processor exits
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	return buildProcessor().Run(ctx, cfg.Processor.Workers)
+
+}
+
+func buildProcessor() *processor.Service {
+
 	etagRepo := mysql.NewETagRepository(mysqlClient)
 
 	cache := cache.New(redisClient, true)
 	etag := etag.New(cache, etagRepo)
-	esi := esi.New(httpClient(), redisClient, logger, etag)
+	esi := esi.New(httpClient(), redisClient, logger, etag, cfg.Eve.ESIBaseURI)
 
 	allianceRepo := mysql.NewAllianceRepository(mysqlClient)
 	corporationRepo := mysql.NewCorporationRepository(mysqlClient)
 	characterRepo := mysql.NewCharacterRepository(mysqlClient)
 	cloneRepo := mysql.NewCloneRepository(mysqlClient)
+	contactRepo := mysql.NewContactRepository(mysqlClient)
 	skillsRepo := mysql.NewSkillRepository(mysqlClient)
 	userRepo := mysql.NewUserRepository(mysqlClient)
 	universeRepo := mysql.NewUniverseRepository(mysqlClient)
@@ -51,18 +67,19 @@ func processorCommand(c *cli.Context) error {
 		httpClient(),
 		cache,
 		oauth2Config(),
+		cfg.Eve.SSOBaseURI,
 	)
 	universe := universe.New(logger, cache, esi, universeRepo)
 	clone := clone.New(logger, cache, etag, esi, universe, cloneRepo)
-	skills := skill.New(logger, cache, esi, universe, skillsRepo)
-	// contact := contact.New(logger, cache, etag, esi, character, corporation, alliance, contactRepo)
+	contact := contact.New(logger, cache, etag, esi, character, corporation, alliance, contactRepo)
+	skills := skill.New(logger, cache, esi, universe, clone, skillsRepo)
 
-	user := user.New(redisClient, logger, cache, auth, alliance, character, corporation, skills, clone, userRepo)
+	user := user.New(redisClient, logger, cache, auth, alliance, character, corporation, skills, clone, contact, userRepo)
 
-	return processor.New(logger, redisClient, nr, user, skillz.ScopeProcessors{
+	return processor.New(logger, redisClient, nr, esi, user, skills, skillz.ScopeProcessors{
 		clone,
 		skills,
-		// contact,
-	}).Run()
+		contact,
+	})
 
 }
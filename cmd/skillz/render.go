@@ -2,6 +2,8 @@ package main
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/eveisesi/skillz"
 	"github.com/eveisesi/skillz/internal/skill"
@@ -60,11 +62,15 @@ func renderer() *render.Engine {
 			"hasShipCount":           hasShipCount,
 			"percentageShipsTrained": percentageShipsTrained,
 			"notFlyable":             notFlyable,
+			"formatDuration":         formatDuration,
+			"timelinePoints":         timelinePoints,
+			"timelineSPGained":       timelineSPGained,
+			"standingClass":          standingClass,
 		},
 	})
 }
 
-var tabs = []string{"skills", "queue", "flyable", "implants"}
+var tabs = []string{"skills", "queue", "flyable", "implants", "timeline", "contacts"}
 
 const activeNavClass = "active"
 const activeTabPaneClass = "show active"
@@ -85,6 +91,12 @@ func activeNav(t string, settings *skillz.UserSettings) string {
 		} else if tab == "implants" && !settings.HideImplants {
 			activeTab = tab
 			break
+		} else if tab == "timeline" && !settings.HideSkills {
+			activeTab = tab
+			break
+		} else if tab == "contacts" && !settings.HideStandings {
+			activeTab = tab
+			break
 		}
 	}
 
@@ -111,6 +123,12 @@ func activeTabPane(t string, settings *skillz.UserSettings) string {
 		} else if tab == "implants" && !settings.HideImplants {
 			activeTab = tab
 			break
+		} else if tab == "timeline" && !settings.HideSkills {
+			activeTab = tab
+			break
+		} else if tab == "contacts" && !settings.HideStandings {
+			activeTab = tab
+			break
 		}
 	}
 
@@ -157,3 +175,106 @@ func notFlyable(b bool) string {
 	}
 	return "notFlyable"
 }
+
+// formatDuration renders a duration the way the game client does, i.e. 1d 4h 23m 12s
+func formatDuration(d time.Duration) string {
+	if d <= 0 {
+		return "N/A"
+	}
+
+	d = d.Round(time.Second)
+	days := d / (24 * time.Hour)
+	d -= days * 24 * time.Hour
+	hours := d / time.Hour
+	d -= hours * time.Hour
+	minutes := d / time.Minute
+	d -= minutes * time.Minute
+	seconds := d / time.Second
+
+	parts := make([]string, 0, 4)
+	if days > 0 {
+		parts = append(parts, fmt.Sprintf("%dd", days))
+	}
+	if hours > 0 {
+		parts = append(parts, fmt.Sprintf("%dh", hours))
+	}
+	if minutes > 0 {
+		parts = append(parts, fmt.Sprintf("%dm", minutes))
+	}
+	if seconds > 0 {
+		parts = append(parts, fmt.Sprintf("%ds", seconds))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+const (
+	timelineWidth  = 1000
+	timelineHeight = 200
+)
+
+// timelinePoints renders the total skillpoints of the snapshots as the points of an svg polyline
+// that is timelineWidth wide and timelineHeight tall
+func timelinePoints(snapshots []*skillz.CharacterSkillSnapshot) string {
+	if len(snapshots) == 0 {
+		return ""
+	}
+
+	first, last := snapshots[0], snapshots[len(snapshots)-1]
+	minSP, maxSP := first.TotalSP, first.TotalSP
+	for _, snapshot := range snapshots {
+		if snapshot.TotalSP < minSP {
+			minSP = snapshot.TotalSP
+		}
+		if snapshot.TotalSP > maxSP {
+			maxSP = snapshot.TotalSP
+		}
+	}
+
+	span := last.CreatedAt.Sub(first.CreatedAt).Seconds()
+	spSpan := float64(maxSP - minSP)
+
+	points := make([]string, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		x, y := float64(0), float64(timelineHeight)/2
+		if span > 0 {
+			x = snapshot.CreatedAt.Sub(first.CreatedAt).Seconds() / span * timelineWidth
+		}
+		if spSpan > 0 {
+			y = timelineHeight - float64(snapshot.TotalSP-minSP)/spSpan*timelineHeight
+		}
+
+		points = append(points, fmt.Sprintf("%.1f,%.1f", x, y))
+	}
+
+	return strings.Join(points, " ")
+}
+
+func timelineSPGained(snapshots []*skillz.CharacterSkillSnapshot) uint {
+	if len(snapshots) < 2 {
+		return 0
+	}
+
+	first, last := snapshots[0], snapshots[len(snapshots)-1]
+	if last.TotalSP < first.TotalSP {
+		return 0
+	}
+
+	return last.TotalSP - first.TotalSP
+}
+
+// standingClass returns the class used to colour a standing the way the game client does
+func standingClass(standing float64) string {
+	switch {
+	case standing > 5:
+		return "text-primary"
+	case standing > 0:
+		return "text-info"
+	case standing < -5:
+		return "text-danger"
+	case standing < 0:
+		return "text-warning"
+	}
+
+	return "text-white"
+}
@@ -13,9 +13,11 @@ import (
 	"github.com/eveisesi/skillz/internal/cache"
 	"github.com/eveisesi/skillz/internal/character"
 	"github.com/eveisesi/skillz/internal/clone"
+	"github.com/eveisesi/skillz/internal/contact"
 	"github.com/eveisesi/skillz/internal/corporation"
 	"github.com/eveisesi/skillz/internal/esi"
 	"github.com/eveisesi/skillz/internal/etag"
+	"github.com/eveisesi/skillz/internal/fitting"
 	"github.com/eveisesi/skillz/internal/mysql"
 	"github.com/eveisesi/skillz/internal/server"
 	"github.com/eveisesi/skillz/internal/skill"
@@ -58,30 +60,34 @@ func serverCmd(c *cli.Context) error {
 	corporationRepo := mysql.NewCorporationRepository(mysqlClient)
 	etagRepo := mysql.NewETagRepository(mysqlClient)
 	cloneRepo := mysql.NewCloneRepository(mysqlClient)
+	contactRepo := mysql.NewContactRepository(mysqlClient)
 	skillzRepo := mysql.NewSkillRepository(mysqlClient)
 	userRepo := mysql.NewUserRepository(mysqlClient)
 	universeRepo := mysql.NewUniverseRepository(mysqlClient)
 
 	cache := cache.New(redisClient, cfg.Redis.DisableCache == 1)
 	etag := etag.New(cache, etagRepo)
-	esi := esi.New(httpClient(), redisClient, logger, etag)
+	esi := esi.New(httpClient(), redisClient, logger, etag, cfg.Eve.ESIBaseURI)
 	character := character.New(logger, cache, esi, etag, characterRepo)
 	corporation := corporation.New(logger, cache, esi, etag, corporationRepo)
 	alliance := alliance.New(logger, cache, esi, etag, allianceRepo)
 	universe := universe.New(logger, cache, esi, universeRepo)
 	clone := clone.New(logger, cache, etag, esi, universe, cloneRepo)
-	skills := skill.New(logger, cache, esi, universe, skillzRepo)
+	contact := contact.New(logger, cache, etag, esi, character, corporation, alliance, contactRepo)
+	skills := skill.New(logger, cache, esi, universe, clone, skillzRepo)
+	fittings := fitting.New(logger, universe, skills)
 
 	auth := auth.New(
 		skillz.EnvironmentFromString(cfg.Environment),
 		httpClient(),
 		cache,
 		oauth2Config(),
+		cfg.Eve.SSOBaseURI,
 	)
 
-	user := user.New(redisClient, logger, cache, auth, alliance, character, corporation, skills, clone, userRepo)
+	user := user.New(redisClient, logger, cache, auth, alliance, character, corporation, skills, clone, contact, userRepo)
 
-	srv := server.New(logger, nr, auth, user)
+	srv := server.New(logger, nr, auth, esi, user, skills, fittings)
 
 	go func() {
 		if err := srv.Start(); err != nil {
@@ -0,0 +1,47 @@
+package skillz
+
+// UserComparison compares the skills, flyable ships and attributes of two or more users. The per user
+// slices of the comparison are in the same order as Users. Sections that a user has hidden through their
+// settings are flagged as hidden and are left out when looking for the skills and ships unique to a user
+type UserComparison struct {
+	Users            []*User                 `json:"users"`
+	SkillsHidden     []bool                  `json:"skills_hidden"`
+	FlyableHidden    []bool                  `json:"flyable_hidden"`
+	AttributesHidden []bool                  `json:"attributes_hidden"`
+	Groups           []*SkillGroupComparison `json:"groups"`
+	ShipGroups       []*ShipGroupComparison  `json:"ship_groups"`
+}
+
+// SkillGroupComparison is the skillpoints of each user in a skill group along
+// with the skills of the group that at least one of the users has trained
+type SkillGroupComparison struct {
+	GroupID     uint               `json:"group_id"`
+	Name        string             `json:"name"`
+	Skillpoints []uint             `json:"skillpoints"`
+	Skills      []*SkillComparison `json:"skills"`
+}
+
+// SkillComparison is the trained level and skillpoints of each user in a skill. Unique
+// is true when some of the users have trained the skill and the others have not
+type SkillComparison struct {
+	SkillID     uint   `json:"skill_id"`
+	Name        string `json:"name"`
+	Levels      []uint `json:"levels"`
+	Skillpoints []uint `json:"skillpoints"`
+	Unique      bool   `json:"unique"`
+}
+
+type ShipGroupComparison struct {
+	GroupID uint              `json:"group_id"`
+	Name    string            `json:"name"`
+	Ships   []*ShipComparison `json:"ships"`
+}
+
+// ShipComparison is whether each user is able to fly a ship. Unique is true
+// when some of the users are able to fly the ship and the others are not
+type ShipComparison struct {
+	ShipID  uint   `json:"ship_id"`
+	Name    string `json:"name"`
+	Flyable []bool `json:"flyable"`
+	Unique  bool   `json:"unique"`
+}
@@ -0,0 +1,95 @@
+package skillz
+
+import (
+	"context"
+	"time"
+
+	"github.com/volatiletech/null"
+)
+
+type DoctrineRepository interface {
+	Doctrine(ctx context.Context, id uint) (*Doctrine, error)
+	Doctrines(ctx context.Context, corporationID uint, allianceID null.Uint) ([]*Doctrine, error)
+	CreateDoctrine(ctx context.Context, doctrine *Doctrine) error
+	DeleteDoctrine(ctx context.Context, id uint) error
+
+	DoctrineFits(ctx context.Context, doctrineID uint) ([]*DoctrineFit, error)
+	// CreateDoctrineFit atomically creates the fit along with the doctrine fit skills of its requirements
+	CreateDoctrineFit(ctx context.Context, fit *DoctrineFit) error
+	DeleteDoctrineFit(ctx context.Context, doctrineID, fitID uint) error
+
+	DoctrineFitSkills(ctx context.Context, fitIDs ...uint) ([]*DoctrineFitSkill, error)
+}
+
+// Doctrine is a named set of fits that a corporation expects its members to be able to fly. Doctrines
+// with an AllianceID are reported over the members of every corporation in the alliance
+type Doctrine struct {
+	ID            uint      `db:"id" json:"id"`
+	CorporationID uint      `db:"corporation_id" json:"corporation_id"`
+	AllianceID    null.Uint `db:"alliance_id,omitempty" json:"alliance_id,omitempty"`
+	Name          string    `db:"name" json:"name"`
+	CreatedBy     string    `db:"created_by" json:"created_by"`
+	CreatedAt     time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt     time.Time `db:"updated_at" json:"updated_at"`
+
+	Corporation *Corporation   `json:"corporation,omitempty"`
+	Fits        []*DoctrineFit `json:"fits,omitempty"`
+}
+
+// DoctrineFit is a ship, optionally fitted, of a doctrine. Requirements are the skill levels
+// that the ship and its modules directly require, excluding nested prerequisites
+type DoctrineFit struct {
+	ID         uint      `db:"id" json:"id"`
+	DoctrineID uint      `db:"doctrine_id" json:"doctrine_id"`
+	Name       string    `db:"name" json:"name"`
+	ShipTypeID uint      `db:"ship_type_id" json:"ship_type_id"`
+	CreatedAt  time.Time `db:"created_at" json:"created_at"`
+
+	Ship         *Type               `json:"ship,omitempty"`
+	Requirements []*SkillRequirement `json:"requirements,omitempty"`
+}
+
+type DoctrineFitSkill struct {
+	FitID   uint `db:"fit_id" json:"fit_id"`
+	SkillID uint `db:"skill_id" json:"skill_id"`
+	Level   uint `db:"level" json:"level"`
+}
+
+// DoctrineReport reports which of the members that have opted in to sharing their
+// skills with their corporation are able to fly the fits of a doctrine
+type DoctrineReport struct {
+	Doctrine  *Doctrine               `json:"doctrine"`
+	Members   []*DoctrineMemberReport `json:"members"`
+	CreatedAt time.Time               `json:"created_at"`
+}
+
+// DoctrineMemberReport is the status of a member against every fit of a doctrine. Fits
+// are in the same order as the fits of the doctrine. A member meets the doctrine when they
+// are able to fly at least one of its fits
+type DoctrineMemberReport struct {
+	User  *User                `json:"user"`
+	Meets bool                 `json:"meets"`
+	Fits  []*DoctrineFitStatus `json:"fits"`
+}
+
+// Closest returns the status of the fit that the member is closest to being able to fly
+func (r *DoctrineMemberReport) Closest() *DoctrineFitStatus {
+
+	var closest *DoctrineFitStatus
+	for _, fit := range r.Fits {
+		if closest == nil || fit.Duration < closest.Duration {
+			closest = fit
+		}
+	}
+
+	return closest
+
+}
+
+// DoctrineFitStatus is the skillpoints and training time a member needs to be able to fly a fit
+type DoctrineFitStatus struct {
+	FitID       uint          `json:"fit_id"`
+	Meets       bool          `json:"meets"`
+	Skillpoints uint          `json:"skillpoints"`
+	Duration    time.Duration `json:"duration"`
+}
@@ -0,0 +1,44 @@
+package skillz
+
+// Fitting is a ship and the items fitted to it, parsed from either the EFT format
+// or the ESI fittings format
+type Fitting struct {
+	Name       string         `json:"name"`
+	ShipTypeID uint           `json:"ship_type_id"`
+	ShipName   string         `json:"ship_name"`
+	Items      []*FittingItem `json:"items"`
+}
+
+// FittingItem is an item in a fitting. Items parsed from the EFT format are identified by
+// their Name while items parsed from the ESI format are identified by their TypeID
+type FittingItem struct {
+	TypeID   uint   `json:"type_id"`
+	Name     string `json:"name"`
+	Flag     string `json:"flag"`
+	Quantity uint   `json:"quantity"`
+}
+
+// FittingReport reports whether a character is able to use a fitting and, when they
+// are not, the skill plan that needs to be trained to be able to use it
+type FittingReport struct {
+	Name   string               `json:"name"`
+	Usable bool                 `json:"usable"`
+	Ship   *FittingItemReport   `json:"ship"`
+	Items  []*FittingItemReport `json:"items"`
+	Plan   *SkillPlan           `json:"plan"`
+}
+
+// FittingItemReport is the result of checking the required skills of a single
+// item of a fitting against a character's skills
+type FittingItemReport struct {
+	TypeID   uint   `json:"type_id"`
+	Name     string `json:"name"`
+	Quantity uint   `json:"quantity"`
+	Type     *Type  `json:"info,omitempty"`
+
+	// Resolved is false when the item's name or type could not be found. Unresolved
+	// items are not usable since their requirements are unknown
+	Resolved bool                `json:"resolved"`
+	Usable   bool                `json:"usable"`
+	Missing  []*SkillRequirement `json:"missing"`
+}
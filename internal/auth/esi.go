@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
@@ -108,7 +109,7 @@ func (s *Service) ParseAndVerifyESIToken(ctx context.Context, t string) (jwt.Tok
 		return nil, fmt.Errorf("failed to parse token: %w", err)
 	}
 
-	err = jwt.Validate(token, jwt.WithIssuer("login.eveonline.com"), jwt.WithClaimValue("azp", s.esiAuth.oauthConfig.ClientID))
+	err = jwt.Validate(token, jwt.WithIssuer(s.ssoURI.Host), jwt.WithClaimValue("azp", s.esiAuth.oauthConfig.ClientID))
 	if err != nil {
 		return token, fmt.Errorf("failed to validate token: %w", err)
 	}
@@ -118,7 +119,7 @@ func (s *Service) ParseAndVerifyESIToken(ctx context.Context, t string) (jwt.Tok
 }
 
 func (s *Service) initializeESIJWKSet() error {
-	res, err := s.client.Get(jwksURIStr)
+	res, err := s.client.Get(s.ssoURI.ResolveReference(&url.URL{Path: "/oauth/jwks"}).String())
 	if err != nil {
 		return fmt.Errorf("unable to retrieve jwks from sso: %w", err)
 	}
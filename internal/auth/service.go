@@ -3,6 +3,7 @@ package auth
 import (
 	"fmt"
 	"net/http"
+	"net/url"
 
 	"github.com/eveisesi/skillz"
 	"github.com/eveisesi/skillz/internal/cache"
@@ -24,20 +25,29 @@ type Service struct {
 	esiAuth esiAuth
 	client  *http.Client
 	cache   cache.AuthAPI
+	ssoURI  *url.URL
 }
 
-const jwksURIStr = "https://login.eveonline.com/oauth/jwks"
+const ssoHost = "login.eveonline.com"
 
+// New returns the auth service for the SSO at the base URI, or for login.eveonline.com when the base URI is nil.
+// The SSO's JWKS is fetched on creation, so the SSO must be reachable
 func New(
 	env skillz.Environment,
 	client *http.Client,
 	cache cache.AuthAPI,
 	esiOAuth *oauth2.Config,
+	ssoURI *url.URL,
 ) *Service {
+	if ssoURI == nil {
+		ssoURI = &url.URL{Scheme: "https", Host: ssoHost}
+	}
+
 	s := &Service{
 		env:    env,
 		client: client,
 		cache:  cache,
+		ssoURI: ssoURI,
 		esiAuth: esiAuth{
 			oauthConfig: esiOAuth,
 		},
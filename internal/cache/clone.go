@@ -14,10 +14,13 @@ import (
 type CloneAPI interface {
 	CharacterImplants(ctx context.Context, characterID uint64) ([]*skillz.CharacterImplant, error)
 	SetCharacterImplants(ctx context.Context, characterID uint64, implants []*skillz.CharacterImplant, expires time.Duration) error
+	CharacterJumpClones(ctx context.Context, characterID uint64) ([]*skillz.CharacterJumpClone, error)
+	SetCharacterJumpClones(ctx context.Context, characterID uint64, clones []*skillz.CharacterJumpClone, expires time.Duration) error
 }
 
 const (
-	characterImplantsKeyPrefix = "character::implants"
+	characterImplantsKeyPrefix   = "character::implants"
+	characterJumpClonesKeyPrefix = "character::jumpclones"
 )
 
 func (s *Service) CharacterImplants(ctx context.Context, characterID uint64) ([]*skillz.CharacterImplant, error) {
@@ -55,3 +58,39 @@ func (s *Service) SetCharacterImplants(ctx context.Context, characterID uint64,
 	return errors.Wrapf(err, errorFFormat, cloneAPI, "SetCharacterImplants", "failed to write cache")
 
 }
+
+func (s *Service) CharacterJumpClones(ctx context.Context, characterID uint64) ([]*skillz.CharacterJumpClone, error) {
+	if s.disabled {
+		return nil, nil
+	}
+	var clones = make([]*skillz.CharacterJumpClone, 0)
+
+	key := generateKey(characterJumpClonesKeyPrefix, strconv.FormatUint(characterID, 10))
+	result, err := s.redis.Get(ctx, key).Bytes()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return clones, errors.Wrapf(err, errorFFormat, cloneAPI, "CharacterJumpClones", "failed to fetch results from cache")
+	}
+
+	if errors.Is(err, redis.Nil) {
+		return clones, nil
+	}
+
+	err = json.Unmarshal(result, &clones)
+	return clones, errors.Wrapf(err, errorFFormat, cloneAPI, "CharacterJumpClones", "failed to decode json to structure")
+
+}
+
+func (s *Service) SetCharacterJumpClones(ctx context.Context, characterID uint64, clones []*skillz.CharacterJumpClone, expires time.Duration) error {
+	if s.disabled {
+		return nil
+	}
+	data, err := json.Marshal(clones)
+	if err != nil {
+		return errors.Wrapf(err, errorFFormat, cloneAPI, "SetCharacterJumpClones", "failed to encode struct as json")
+	}
+
+	key := generateKey(characterJumpClonesKeyPrefix, strconv.FormatUint(characterID, 10))
+	err = s.redis.Set(ctx, key, data, expires).Err()
+	return errors.Wrapf(err, errorFFormat, cloneAPI, "SetCharacterJumpClones", "failed to write cache")
+
+}
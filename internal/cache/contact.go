@@ -0,0 +1,57 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/eveisesi/skillz"
+	"github.com/go-redis/redis/v8"
+	"github.com/pkg/errors"
+)
+
+type ContactAPI interface {
+	CharacterContacts(ctx context.Context, characterID uint64) ([]*skillz.CharacterContact, error)
+	SetCharacterContacts(ctx context.Context, characterID uint64, contacts []*skillz.CharacterContact, expires time.Duration) error
+}
+
+const (
+	characterContactsKeyPrefix = "character::contacts"
+)
+
+func (s *Service) CharacterContacts(ctx context.Context, characterID uint64) ([]*skillz.CharacterContact, error) {
+	if s.disabled {
+		return nil, nil
+	}
+	var contacts = make([]*skillz.CharacterContact, 0)
+
+	key := generateKey(characterContactsKeyPrefix, strconv.FormatUint(characterID, 10))
+	result, err := s.redis.Get(ctx, key).Bytes()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return contacts, errors.Wrapf(err, errorFFormat, contactAPI, "CharacterContacts", "failed to fetch results from cache")
+	}
+
+	if errors.Is(err, redis.Nil) {
+		return contacts, nil
+	}
+
+	err = json.Unmarshal(result, &contacts)
+	return contacts, errors.Wrapf(err, errorFFormat, contactAPI, "CharacterContacts", "failed to decode json to structure")
+
+}
+
+func (s *Service) SetCharacterContacts(ctx context.Context, characterID uint64, contacts []*skillz.CharacterContact, expires time.Duration) error {
+	if s.disabled {
+		return nil
+	}
+	data, err := json.Marshal(contacts)
+	if err != nil {
+		return errors.Wrapf(err, errorFFormat, contactAPI, "SetCharacterContacts", "failed to encode struct as json")
+	}
+
+	key := generateKey(characterContactsKeyPrefix, strconv.FormatUint(characterID, 10))
+	err = s.redis.Set(ctx, key, data, expires).Err()
+	return errors.Wrapf(err, errorFFormat, contactAPI, "SetCharacterContacts", "failed to write cache")
+
+}
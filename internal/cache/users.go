@@ -60,6 +60,9 @@ func (s *Service) ResetUserCache(ctx context.Context, user *skillz.User) error {
 		generateKey(characterFlyableKeyPrefix, strconv.FormatUint(user.CharacterID, 10)),
 		generateKey(characterSkillQueueKeySummaryPrefix, strconv.FormatUint(user.CharacterID, 10)),
 		generateKey(characterAttributesKeyPrefix, strconv.FormatUint(user.CharacterID, 10)),
+		generateKey(characterImplantsKeyPrefix, strconv.FormatUint(user.CharacterID, 10)),
+		generateKey(characterJumpClonesKeyPrefix, strconv.FormatUint(user.CharacterID, 10)),
+		generateKey(characterContactsKeyPrefix, strconv.FormatUint(user.CharacterID, 10)),
 	}
 
 	for _, key := range keys {
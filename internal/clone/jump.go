@@ -0,0 +1,188 @@
+package clone
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/eveisesi/skillz"
+	"github.com/eveisesi/skillz/internal"
+	"github.com/eveisesi/skillz/internal/esi"
+	"github.com/go-redis/redis/v8"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/volatiletech/null"
+)
+
+// JumpClones returns the character's jump clones with their implants and the station or structure they are stored at
+func (s *Service) JumpClones(ctx context.Context, characterID uint64) ([]*skillz.CharacterJumpClone, error) {
+
+	clones, err := s.cache.CharacterJumpClones(ctx, characterID)
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	if len(clones) > 0 {
+		return clones, nil
+	}
+
+	clones, err = s.clones.CharacterJumpClones(ctx, characterID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.Wrap(err, "failed to fetch character jump clones from data store")
+	}
+
+	if len(clones) == 0 {
+		return clones, nil
+	}
+
+	implants, err := s.clones.CharacterJumpCloneImplants(ctx, characterID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.Wrap(err, "failed to fetch character jump clone implants from data store")
+	}
+
+	clonesByID := make(map[uint]*skillz.CharacterJumpClone, len(clones))
+	for _, clone := range clones {
+		clone.Implants = make([]*skillz.CharacterJumpCloneImplant, 0, 5)
+		clonesByID[clone.JumpCloneID] = clone
+	}
+
+	for _, implant := range implants {
+		clone, ok := clonesByID[implant.JumpCloneID]
+		if !ok {
+			continue
+		}
+
+		implant.Type, err = s.universe.Type(ctx, implant.ImplantID)
+		if err != nil {
+			return nil, err
+		}
+
+		clone.Implants = append(clone.Implants, implant)
+	}
+
+	for _, clone := range clones {
+		err = s.resolveJumpCloneLocation(ctx, clone)
+		if err != nil {
+			s.logger.WithError(err).WithFields(logrus.Fields{
+				"characterID": characterID,
+				"locationID":  clone.LocationID,
+			}).Warn("failed to resolve jump clone location")
+		}
+	}
+
+	defer func() {
+		err = s.cache.SetCharacterJumpClones(ctx, characterID, clones, time.Hour)
+		if err != nil {
+			s.logger.WithError(err).Error("failed to cache character jump clones")
+		}
+	}()
+
+	return clones, nil
+
+}
+
+// resolveJumpCloneLocation hydrates the clone with the station or structure it is stored at. Structures that have
+// not been seen before are fetched from ESI with the token of the user in the context, if the user has granted the
+// structures scope
+func (s *Service) resolveJumpCloneLocation(ctx context.Context, clone *skillz.CharacterJumpClone) error {
+
+	switch clone.LocationType {
+	case skillz.StationLocationType:
+		station, err := s.universe.Station(ctx, uint(clone.LocationID))
+		if err != nil {
+			return err
+		}
+
+		if station != nil && station.ID != 0 {
+			clone.Station, clone.LocationName = station, station.Name
+		}
+	case skillz.StructureLocationType:
+		structure, err := s.universe.Structure(ctx, clone.LocationID)
+		if err != nil {
+			return err
+		}
+
+		if structure != nil && structure.ID != 0 {
+			clone.Structure, clone.LocationName = structure, structure.Name
+		}
+	}
+
+	return nil
+
+}
+
+func (s *Service) updateJumpClones(ctx context.Context, user *skillz.User) error {
+
+	s.logger.WithFields(logrus.Fields{
+		"service": "clone",
+		"userID":  user.ID,
+	}).Info("updating jump clones")
+
+	etagID, _, err := s.esi.Etag(ctx, esi.GetCharacterClones, &esi.Params{CharacterID: null.Uint64From(user.CharacterID)})
+	if err != nil {
+		return errors.Wrap(err, "failed to fetch etag for expiry check")
+	}
+
+	mods := s.esi.BaseCharacterModifiers(ctx, user, etagID, nil)
+
+	clones, err := s.esi.GetCharacterClones(ctx, user.CharacterID, mods...)
+	if err != nil {
+		return errors.Wrap(err, "failed to fetch character clones from ESI")
+	}
+
+	if clones == nil {
+		return nil
+	}
+
+	err = s.clones.DeleteCharacterJumpClones(ctx, user.CharacterID)
+	if err != nil {
+		return errors.Wrap(err, "failed to update character jump clones")
+	}
+
+	if len(clones) == 0 {
+		return nil
+	}
+
+	implants := make([]*skillz.CharacterJumpCloneImplant, 0)
+	for _, clone := range clones {
+		for _, implant := range clone.Implants {
+			implantType, err := s.universe.Type(ctx, implant.ImplantID)
+			if err != nil {
+				return err
+			}
+
+			attribute := implantType.GetAttribute(skillz.ImplantSlotAttributeID)
+			if attribute == nil {
+				return errors.Errorf("failed to fetch implant slot attribute from implant type %d", implant.ImplantID)
+			}
+
+			implant.Slot = uint(attribute.Value)
+			implants = append(implants, implant)
+		}
+
+		// Resolving the location here, while the user's token is available, stores structures the
+		// character has access to so that they can be resolved when the clones are viewed
+		err = s.resolveJumpCloneLocation(internal.ContextWithUser(ctx, user), clone)
+		if err != nil {
+			s.logger.WithError(err).WithFields(logrus.Fields{
+				"userID":     user.ID,
+				"locationID": clone.LocationID,
+			}).Warn("failed to resolve jump clone location")
+		}
+	}
+
+	err = s.clones.CreateCharacterJumpClones(ctx, clones)
+	if err != nil {
+		return errors.Wrap(err, "failed to update character jump clones")
+	}
+
+	if len(implants) > 0 {
+		err = s.clones.CreateCharacterJumpCloneImplants(ctx, implants)
+		if err != nil {
+			return errors.Wrap(err, "failed to update character jump clone implants")
+		}
+	}
+
+	return nil
+
+}
@@ -19,6 +19,7 @@ import (
 type API interface {
 	skillz.Processor
 	Implants(ctx context.Context, characterID uint64) ([]*skillz.CharacterImplant, error)
+	JumpClones(ctx context.Context, characterID uint64) ([]*skillz.CharacterJumpClone, error)
 }
 
 type Service struct {
@@ -53,6 +54,8 @@ func (s *Service) Process(ctx context.Context, user *skillz.User) error {
 		switch scope {
 		case skillz.ReadImplantsV1:
 			funcs = append(funcs, s.updateImplants)
+		case skillz.ReadClonesV1:
+			funcs = append(funcs, s.updateJumpClones)
 		}
 	}
 
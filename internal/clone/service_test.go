@@ -0,0 +1,204 @@
+package clone_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/eveisesi/skillz"
+	"github.com/eveisesi/skillz/internal/clone"
+	"github.com/eveisesi/skillz/internal/esi"
+	"github.com/eveisesi/skillz/internal/esitest"
+	"github.com/eveisesi/skillz/internal/memory"
+	"github.com/eveisesi/skillz/internal/universe"
+)
+
+type harness struct {
+	server  *esitest.Server
+	service *clone.Service
+	clones  skillz.CloneRepository
+}
+
+// newHarness wires the clone service against the fake ESI and the in-memory repositories
+func newHarness(t *testing.T) *harness {
+	t.Helper()
+
+	env := esitest.NewHarness(t)
+
+	clonesRepo := memory.NewCloneRepository(env.DB)
+	universeRepo := memory.NewUniverseRepository(env.DB)
+	universe := universe.New(env.Logger, env.Cache, env.ESI, universeRepo)
+
+	importUniverse(t, env.ESI, universeRepo)
+
+	return &harness{
+		server:  env.Server,
+		service: clone.New(env.Logger, env.Cache, env.ETag, env.ESI, universe, clonesRepo),
+		clones:  clonesRepo,
+	}
+
+}
+
+// importUniverse stores the implant type and the station of the fixture character's clones. The universe
+// is not fetched on demand, it is imported ahead of time by the import command
+func importUniverse(t *testing.T, esi *esi.Service, universe skillz.UniverseRepository) {
+	t.Helper()
+
+	var ctx = context.Background()
+
+	item, err := esi.GetType(ctx, 9899)
+	if err != nil {
+		t.Fatalf("failed to fetch type: %s", err)
+	}
+
+	err = universe.CreateType(ctx, item)
+	if err != nil {
+		t.Fatalf("failed to import type: %s", err)
+	}
+
+	err = universe.CreateTypeDogmaAttributes(ctx, item.Attributes)
+	if err != nil {
+		t.Fatalf("failed to import type attributes: %s", err)
+	}
+
+	station, err := esi.GetStation(ctx, 60003760)
+	if err != nil {
+		t.Fatalf("failed to fetch station: %s", err)
+	}
+
+	err = universe.CreateStation(ctx, station)
+	if err != nil {
+		t.Fatalf("failed to import station: %s", err)
+	}
+
+}
+
+func (h *harness) user(t *testing.T, scopes ...skillz.Scope) *skillz.User {
+	t.Helper()
+
+	token, err := h.server.Token(esitest.CharacterID, scopes...)
+	if err != nil {
+		t.Fatalf("failed to issue token: %s", err)
+	}
+
+	return &skillz.User{
+		ID:          "user-1",
+		CharacterID: esitest.CharacterID,
+		AccessToken: token,
+		Scopes:      scopes,
+	}
+}
+
+func TestProcess(t *testing.T) {
+
+	var ctx = context.Background()
+
+	h := newHarness(t)
+
+	err := h.service.Process(ctx, h.user(t, skillz.ReadImplantsV1, skillz.ReadClonesV1))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	implants, err := h.service.Implants(ctx, esitest.CharacterID)
+	if err != nil {
+		t.Fatalf("failed to fetch implants: %s", err)
+	}
+	if len(implants) != 1 || implants[0].ImplantID != 9899 || implants[0].Slot != 1 {
+		t.Fatalf("expected implant 9899 in slot 1, got %d implants", len(implants))
+	}
+	if implants[0].Type == nil || implants[0].Type.Name != "Ocular Filter - Basic" {
+		t.Errorf("expected the implant to be hydrated with its type")
+	}
+
+	clones, err := h.service.JumpClones(ctx, esitest.CharacterID)
+	if err != nil {
+		t.Fatalf("failed to fetch jump clones: %s", err)
+	}
+	if len(clones) != 1 || clones[0].JumpCloneID != 12345 {
+		t.Fatalf("expected jump clone 12345, got %d jump clones", len(clones))
+	}
+	if clones[0].LocationName != "Jita IV - Moon 4 - Caldari Navy Assembly Plant" {
+		t.Errorf("got location name %q, want the station's name", clones[0].LocationName)
+	}
+	if len(clones[0].Implants) != 1 || clones[0].Implants[0].Slot != 1 {
+		t.Errorf("expected the jump clone to carry implant 9899 in slot 1")
+	}
+
+}
+
+func TestProcessEmptyClones(t *testing.T) {
+
+	var ctx = context.Background()
+
+	h := newHarness(t)
+	user := h.user(t, skillz.ReadClonesV1)
+
+	err := h.service.Process(ctx, user)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	h.server.SetFixture("/v4/characters/90000001/clones/", []byte(`{"home_location":{"location_id":60003760,"location_type":"station"},"jump_clones":[]}`))
+
+	err = h.service.Process(ctx, user)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	clones, err := h.clones.CharacterJumpClones(ctx, esitest.CharacterID)
+	if err != nil {
+		t.Fatalf("failed to fetch jump clones: %s", err)
+	}
+	if len(clones) != 0 {
+		t.Errorf("expected jump clones to be removed once esi reports none, got %d", len(clones))
+	}
+
+}
+
+func TestProcessStructureClone(t *testing.T) {
+
+	var ctx = context.Background()
+
+	const structurePath = "/v2/universe/structures/1030000000001/"
+
+	tests := []struct {
+		name     string
+		scopes   []skillz.Scope
+		location string
+	}{
+		{"WithStructuresScope", []skillz.Scope{skillz.ReadClonesV1, skillz.ReadStructuresV1}, "Perimeter - Keepstar"},
+		// Without the scope the structure is never requested, as ESI would reject the token
+		{"WithoutStructuresScope", []skillz.Scope{skillz.ReadClonesV1}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHarness(t)
+			h.server.SetFixture("/v4/characters/90000001/clones/", []byte(`{"home_location":{"location_id":60003760,"location_type":"station"},"jump_clones":[{"implants":[],"jump_clone_id":12346,"location_id":1030000000001,"location_type":"structure"}]}`))
+			h.server.SetFixture(structurePath, []byte(`{"name":"Perimeter - Keepstar","owner_id":98000001,"solar_system_id":30000144,"type_id":35834}`))
+
+			err := h.service.Process(ctx, h.user(t, tt.scopes...))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			clones, err := h.service.JumpClones(ctx, esitest.CharacterID)
+			if err != nil {
+				t.Fatalf("failed to fetch jump clones: %s", err)
+			}
+			if len(clones) != 1 || clones[0].LocationName != tt.location {
+				t.Fatalf("expected a single jump clone located at %q", tt.location)
+			}
+
+			requested := false
+			for _, request := range h.server.Requests() {
+				requested = requested || strings.HasSuffix(request, structurePath)
+			}
+			if requested != (tt.location != "") {
+				t.Errorf("got structure requested %t, want %t", requested, tt.location != "")
+			}
+		})
+	}
+
+}
@@ -0,0 +1,166 @@
+package contact
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/eveisesi/skillz"
+	"github.com/eveisesi/skillz/internal/alliance"
+	"github.com/eveisesi/skillz/internal/cache"
+	"github.com/eveisesi/skillz/internal/character"
+	"github.com/eveisesi/skillz/internal/corporation"
+	"github.com/eveisesi/skillz/internal/esi"
+	"github.com/eveisesi/skillz/internal/etag"
+	"github.com/go-redis/redis/v8"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+type API interface {
+	skillz.Processor
+	Contacts(ctx context.Context, characterID uint64) ([]*skillz.CharacterContact, error)
+}
+
+type Service struct {
+	logger      *logrus.Logger
+	cache       cache.ContactAPI
+	etag        etag.API
+	esi         esi.ContactAPI
+	character   character.API
+	corporation corporation.API
+	alliance    alliance.API
+
+	contacts skillz.ContactRepository
+}
+
+var _ API = (*Service)(nil)
+
+func New(logger *logrus.Logger, cache cache.ContactAPI, etag etag.API, esi esi.ContactAPI, character character.API, corporation corporation.API, alliance alliance.API, contacts skillz.ContactRepository) *Service {
+	return &Service{
+		logger:      logger,
+		cache:       cache,
+		etag:        etag,
+		esi:         esi,
+		character:   character,
+		corporation: corporation,
+		alliance:    alliance,
+
+		contacts: contacts,
+	}
+}
+
+func (s *Service) Process(ctx context.Context, user *skillz.User) error {
+
+	var err error
+	var funcs = []func(context.Context, *skillz.User) error{}
+	for _, scope := range user.Scopes {
+		switch scope {
+		case skillz.ReadContactsV1:
+			funcs = append(funcs, s.updateContacts)
+		}
+	}
+
+	for _, f := range funcs {
+		err = f(ctx, user)
+		if err != nil {
+			s.logger.WithError(err).Error("processor func returned an error")
+		}
+	}
+
+	return err
+
+}
+
+// Contacts returns the character's contacts hydrated with the character, corporation or alliance that
+// the contact is for, sorted from the highest standing to the lowest
+func (s *Service) Contacts(ctx context.Context, characterID uint64) ([]*skillz.CharacterContact, error) {
+
+	contacts, err := s.cache.CharacterContacts(ctx, characterID)
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	if len(contacts) > 0 {
+		return contacts, nil
+	}
+
+	contacts, err = s.contacts.CharacterContacts(ctx, characterID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.Wrap(err, "failed to fetch character contacts from data store")
+	}
+
+	for _, contact := range contacts {
+		err = s.hydrateContact(ctx, contact)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sort.SliceStable(contacts, func(i, j int) bool {
+		return contacts[i].Standing > contacts[j].Standing
+	})
+
+	defer func() {
+		err = s.cache.SetCharacterContacts(ctx, characterID, contacts, time.Hour)
+		if err != nil {
+			s.logger.WithError(err).Error("failed to cache character contacts")
+		}
+	}()
+
+	return contacts, nil
+
+}
+
+func (s *Service) hydrateContact(ctx context.Context, contact *skillz.CharacterContact) error {
+
+	var err error
+	switch contact.ContactType {
+	case skillz.CharacterContactType:
+		contact.Character, err = s.character.Character(ctx, uint64(contact.ContactID))
+		return errors.Wrapf(err, "failed to fetch character %d for contact", contact.ContactID)
+	case skillz.CorporationContactType:
+		contact.Corporation, err = s.corporation.Corporation(ctx, contact.ContactID)
+		return errors.Wrapf(err, "failed to fetch corporation %d for contact", contact.ContactID)
+	case skillz.AllianceContactType:
+		contact.Alliance, err = s.alliance.Alliance(ctx, contact.ContactID)
+		return errors.Wrapf(err, "failed to fetch alliance %d for contact", contact.ContactID)
+	}
+
+	return nil
+
+}
+
+func (s *Service) updateContacts(ctx context.Context, user *skillz.User) error {
+
+	s.logger.WithFields(logrus.Fields{
+		"service": "contact",
+		"userID":  user.ID,
+	}).Info("updating contacts")
+
+	// Contacts are paged, the etag of each page is handled by the esi service
+	contacts, err := s.esi.GetCharacterContacts(ctx, user.CharacterID, s.esi.AddAuthorizationHeader(ctx, user.AccessToken))
+	if err != nil {
+		return errors.Wrap(err, "failed to fetch character contacts from ESI")
+	}
+
+	if contacts == nil {
+		return nil
+	}
+
+	err = s.contacts.DeleteCharacterContacts(ctx, user.CharacterID)
+	if err != nil {
+		return errors.Wrap(err, "failed to update character contacts")
+	}
+
+	if len(contacts) > 0 {
+		err = s.contacts.CreateCharacterContacts(ctx, contacts)
+		if err != nil {
+			return errors.Wrap(err, "failed to update character contacts")
+		}
+	}
+
+	return nil
+
+}
@@ -0,0 +1,75 @@
+package doctrine
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/eveisesi/skillz"
+	"github.com/pkg/errors"
+)
+
+// WriteReportCSV writes the report as CSV with a row for each member. Every fit of the doctrine gets
+// a column for whether the member meets it, the skillpoints they are missing and the hours of training left
+func WriteReportCSV(w io.Writer, report *skillz.DoctrineReport) error {
+
+	header := []string{"Character", "Corporation", "Meets Doctrine"}
+	for _, fit := range report.Doctrine.Fits {
+		header = append(header,
+			fmt.Sprintf("%s Meets", fit.Name),
+			fmt.Sprintf("%s Missing SP", fit.Name),
+			fmt.Sprintf("%s Training Hours", fit.Name),
+		)
+	}
+
+	cw := csv.NewWriter(w)
+	err := cw.Write(escapeCSVRecord(header))
+	if err != nil {
+		return errors.Wrap(err, "failed to write report header")
+	}
+
+	for _, member := range report.Members {
+		var corporation string
+		if member.User.Character != nil && member.User.Character.Corporation != nil {
+			corporation = member.User.Character.Corporation.Name
+		}
+
+		record := []string{memberName(member.User), corporation, strconv.FormatBool(member.Meets)}
+		for _, fit := range member.Fits {
+			record = append(record,
+				strconv.FormatBool(fit.Meets),
+				strconv.FormatUint(uint64(fit.Skillpoints), 10),
+				strconv.FormatFloat(fit.Duration.Hours(), 'f', 2, 64),
+			)
+		}
+
+		err = cw.Write(escapeCSVRecord(record))
+		if err != nil {
+			return errors.Wrapf(err, "failed to write report row for user %s", member.User.ID)
+		}
+	}
+
+	cw.Flush()
+
+	return errors.Wrap(cw.Error(), "failed to flush report")
+
+}
+
+// escapeCSVRecord prefixes the cells that a spreadsheet would evaluate as a formula with a quote, so that
+// names chosen by players, such as fit and character names, are displayed as text when the report is opened
+func escapeCSVRecord(record []string) []string {
+
+	escaped := make([]string, len(record))
+	for i, cell := range record {
+		if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+			cell = "'" + cell
+		}
+
+		escaped[i] = cell
+	}
+
+	return escaped
+
+}
@@ -0,0 +1,54 @@
+package doctrine
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/eveisesi/skillz"
+)
+
+func TestWriteReportCSVEscapesFormulas(t *testing.T) {
+
+	report := &skillz.DoctrineReport{
+		Doctrine: &skillz.Doctrine{
+			Fits: []*skillz.DoctrineFit{{ID: 1, Name: "=HYPERLINK(\"http://example.com\")"}},
+		},
+		Members: []*skillz.DoctrineMemberReport{
+			{
+				User: &skillz.User{
+					ID: "user",
+					Character: &skillz.Character{
+						Name:        "@SUM(A1)",
+						Corporation: &skillz.Corporation{Name: "-Corp"},
+					},
+				},
+				Fits: []*skillz.DoctrineFitStatus{{FitID: 1, Skillpoints: 1000, Duration: 90 * time.Minute}},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	err := WriteReportCSV(&buf, report)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to read report: %s", err)
+	}
+
+	if got, want := records[0][3], "'=HYPERLINK(\"http://example.com\") Meets"; got != want {
+		t.Errorf("got header %q, want %q", got, want)
+	}
+
+	want := []string{"'@SUM(A1)", "'-Corp", "false", "false", "1000", "1.50"}
+	for i, cell := range want {
+		if records[1][i] != cell {
+			t.Errorf("got cell %q in column %d, want %q", records[1][i], i, cell)
+		}
+	}
+
+}
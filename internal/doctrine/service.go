@@ -0,0 +1,442 @@
+package doctrine
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/eveisesi/skillz"
+	"github.com/eveisesi/skillz/internal/character"
+	"github.com/eveisesi/skillz/internal/corporation"
+	"github.com/eveisesi/skillz/internal/fitting"
+	"github.com/eveisesi/skillz/internal/skill"
+	"github.com/eveisesi/skillz/internal/universe"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrDoctrineNotFound = errors.New("doctrine does not exist")
+	ErrInvalidDoctrine  = errors.New("doctrine is invalid")
+	ErrNotSharing       = errors.New("doctrines are only available to members that share their skills with their corporation")
+	ErrNotPermitted     = errors.New("only the creator of a doctrine or the CEO of its corporation can change it")
+)
+
+const maxDoctrineNameLength = 255
+
+type API interface {
+	Doctrines(ctx context.Context, user *skillz.User) ([]*skillz.Doctrine, error)
+	Doctrine(ctx context.Context, user *skillz.User, doctrineID uint) (*skillz.Doctrine, error)
+	CreateDoctrine(ctx context.Context, user *skillz.User, name string, allianceWide bool) (*skillz.Doctrine, error)
+	DeleteDoctrine(ctx context.Context, user *skillz.User, doctrineID uint) error
+	CanManage(ctx context.Context, user *skillz.User, doctrine *skillz.Doctrine) (bool, error)
+
+	CreateDoctrineFit(ctx context.Context, user *skillz.User, doctrineID uint, eft string) (*skillz.DoctrineFit, error)
+	DeleteDoctrineFit(ctx context.Context, user *skillz.User, doctrineID, fitID uint) error
+
+	Report(ctx context.Context, user *skillz.User, doctrineID uint) (*skillz.DoctrineReport, error)
+}
+
+type Service struct {
+	logger      *logrus.Logger
+	character   character.API
+	corporation corporation.API
+	universe    universe.API
+	fittings    fitting.API
+	skills      skill.API
+
+	doctrines skillz.DoctrineRepository
+	users     skillz.UserRepository
+}
+
+var _ API = (*Service)(nil)
+
+func New(logger *logrus.Logger, character character.API, corporation corporation.API, universe universe.API, fittings fitting.API, skills skill.API, doctrines skillz.DoctrineRepository, users skillz.UserRepository) *Service {
+	return &Service{
+		logger:      logger,
+		character:   character,
+		corporation: corporation,
+		universe:    universe,
+		fittings:    fittings,
+		skills:      skills,
+
+		doctrines: doctrines,
+		users:     users,
+	}
+}
+
+// Doctrines returns the doctrines of the user's corporation along with the alliance wide doctrines of their alliance
+func (s *Service) Doctrines(ctx context.Context, user *skillz.User) ([]*skillz.Doctrine, error) {
+
+	err := checkSharing(user)
+	if err != nil {
+		return nil, err
+	}
+
+	doctrines, err := s.doctrines.Doctrines(ctx, user.Character.CorporationID, user.Character.AllianceID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.Wrap(err, "failed to fetch doctrines")
+	}
+
+	for _, doctrine := range doctrines {
+		err = s.hydrateDoctrine(ctx, doctrine)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return doctrines, nil
+
+}
+
+// Doctrine returns the doctrine along with its fits when the doctrine belongs to the user's corporation or alliance
+func (s *Service) Doctrine(ctx context.Context, user *skillz.User, doctrineID uint) (*skillz.Doctrine, error) {
+
+	err := checkSharing(user)
+	if err != nil {
+		return nil, err
+	}
+
+	doctrine, err := s.doctrines.Doctrine(ctx, doctrineID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.Wrap(err, "failed to fetch doctrine")
+	}
+
+	if errors.Is(err, sql.ErrNoRows) || !affiliated(user.Character, doctrine) {
+		return nil, ErrDoctrineNotFound
+	}
+
+	err = s.hydrateDoctrine(ctx, doctrine)
+	if err != nil {
+		return nil, err
+	}
+
+	doctrine.Fits, err = s.doctrineFits(ctx, doctrine.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return doctrine, nil
+
+}
+
+// CreateDoctrine creates a doctrine for the user's corporation. Alliance wide doctrines are
+// reported over the members of every corporation in the user's alliance
+func (s *Service) CreateDoctrine(ctx context.Context, user *skillz.User, name string, allianceWide bool) (*skillz.Doctrine, error) {
+
+	err := checkSharing(user)
+	if err != nil {
+		return nil, err
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.Wrap(ErrInvalidDoctrine, "name is required")
+	}
+
+	if len(name) > maxDoctrineNameLength {
+		return nil, errors.Wrapf(ErrInvalidDoctrine, "name must be %d characters or less", maxDoctrineNameLength)
+	}
+
+	doctrine := &skillz.Doctrine{
+		CorporationID: user.Character.CorporationID,
+		Name:          name,
+		CreatedBy:     user.ID,
+	}
+
+	if allianceWide {
+		if !user.Character.AllianceID.Valid {
+			return nil, errors.Wrap(ErrInvalidDoctrine, "alliance wide doctrines require your corporation to be in an alliance")
+		}
+
+		doctrine.AllianceID = user.Character.AllianceID
+	}
+
+	err = s.doctrines.CreateDoctrine(ctx, doctrine)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create doctrine")
+	}
+
+	return doctrine, nil
+
+}
+
+func (s *Service) DeleteDoctrine(ctx context.Context, user *skillz.User, doctrineID uint) error {
+
+	doctrine, err := s.manageableDoctrine(ctx, user, doctrineID)
+	if err != nil {
+		return err
+	}
+
+	err = s.doctrines.DeleteDoctrine(ctx, doctrine.ID)
+	return errors.Wrap(err, "failed to delete doctrine")
+
+}
+
+// CanManage reports whether the user is allowed to change the doctrine. Doctrines can be changed by the
+// member that created them and by the CEO of the corporation the doctrine belongs to
+func (s *Service) CanManage(ctx context.Context, user *skillz.User, doctrine *skillz.Doctrine) (bool, error) {
+
+	if doctrine.CreatedBy == user.ID {
+		return true, nil
+	}
+
+	if user.Character == nil || user.Character.CorporationID != doctrine.CorporationID {
+		return false, nil
+	}
+
+	corporation, err := s.corporation.Corporation(ctx, doctrine.CorporationID)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to fetch corporation %d", doctrine.CorporationID)
+	}
+
+	return corporation != nil && uint64(corporation.CeoID) == user.CharacterID, nil
+
+}
+
+// CreateDoctrineFit parses the fitting in the EFT format and adds it to the doctrine. A fitting without
+// any modules, i.e. just the [Ship, Name] header, only requires the skills to fly the ship
+func (s *Service) CreateDoctrineFit(ctx context.Context, user *skillz.User, doctrineID uint, eft string) (*skillz.DoctrineFit, error) {
+
+	doctrine, err := s.manageableDoctrine(ctx, user, doctrineID)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := fitting.ParseEFT(eft)
+	if err != nil {
+		return nil, err
+	}
+
+	ship, requirements, err := s.fittings.Requirements(ctx, parsed)
+	if err != nil {
+		return nil, err
+	}
+
+	name := parsed.Name
+	if name == "" {
+		name = ship.Name
+	}
+
+	if len(name) > maxDoctrineNameLength {
+		name = name[:maxDoctrineNameLength]
+	}
+
+	fit := &skillz.DoctrineFit{
+		DoctrineID:   doctrine.ID,
+		Name:         name,
+		ShipTypeID:   ship.ID,
+		Ship:         ship,
+		Requirements: requirements,
+	}
+
+	err = s.doctrines.CreateDoctrineFit(ctx, fit)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create doctrine fit")
+	}
+
+	return fit, nil
+
+}
+
+func (s *Service) DeleteDoctrineFit(ctx context.Context, user *skillz.User, doctrineID, fitID uint) error {
+
+	doctrine, err := s.manageableDoctrine(ctx, user, doctrineID)
+	if err != nil {
+		return err
+	}
+
+	err = s.doctrines.DeleteDoctrineFit(ctx, doctrine.ID, fitID)
+	return errors.Wrap(err, "failed to delete doctrine fit")
+
+}
+
+// Report checks every fit of the doctrine against the skills of each member of the doctrine's corporation, or
+// alliance for alliance wide doctrines, that shares their skills with their corporation. Members that meet the
+// doctrine are listed first, followed by the remaining members ordered by how soon they can meet it
+func (s *Service) Report(ctx context.Context, user *skillz.User, doctrineID uint) (*skillz.DoctrineReport, error) {
+
+	doctrine, err := s.Doctrine(ctx, user, doctrineID)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := s.users.UsersSharingWithCorporation(ctx, doctrine.CorporationID, doctrine.AllianceID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.Wrap(err, "failed to fetch members sharing with their corporation")
+	}
+
+	report := &skillz.DoctrineReport{
+		Doctrine:  doctrine,
+		Members:   make([]*skillz.DoctrineMemberReport, 0, len(users)),
+		CreatedAt: time.Now(),
+	}
+
+	for _, member := range users {
+		member.Character, err = s.character.Character(ctx, member.CharacterID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to fetch character %d", member.CharacterID)
+		}
+
+		if member.Character != nil {
+			member.Character.Corporation, err = s.corporation.Corporation(ctx, member.Character.CorporationID)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to fetch corporation %d", member.Character.CorporationID)
+			}
+		}
+
+		memberReport := &skillz.DoctrineMemberReport{
+			User: member,
+			Fits: make([]*skillz.DoctrineFitStatus, 0, len(doctrine.Fits)),
+		}
+
+		for _, fit := range doctrine.Fits {
+			plan, err := s.skills.RequirementsPlan(ctx, member.CharacterID, fit.Requirements)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to check fit %d for character %d", fit.ID, member.CharacterID)
+			}
+
+			status := &skillz.DoctrineFitStatus{
+				FitID:       fit.ID,
+				Meets:       len(plan.Entries) == 0,
+				Skillpoints: plan.Skillpoints,
+				Duration:    plan.Duration,
+			}
+
+			memberReport.Meets = memberReport.Meets || status.Meets
+			memberReport.Fits = append(memberReport.Fits, status)
+		}
+
+		report.Members = append(report.Members, memberReport)
+	}
+
+	sort.SliceStable(report.Members, func(i, j int) bool {
+		a, b := report.Members[i], report.Members[j]
+		if a.Meets != b.Meets {
+			return a.Meets
+		}
+
+		ac, bc := a.Closest(), b.Closest()
+		if ac != nil && bc != nil && ac.Duration != bc.Duration {
+			return ac.Duration < bc.Duration
+		}
+
+		return memberName(a.User) < memberName(b.User)
+	})
+
+	return report, nil
+
+}
+
+func (s *Service) manageableDoctrine(ctx context.Context, user *skillz.User, doctrineID uint) (*skillz.Doctrine, error) {
+
+	doctrine, err := s.Doctrine(ctx, user, doctrineID)
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := s.CanManage(ctx, user, doctrine)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return nil, ErrNotPermitted
+	}
+
+	return doctrine, nil
+
+}
+
+func (s *Service) hydrateDoctrine(ctx context.Context, doctrine *skillz.Doctrine) error {
+
+	corporation, err := s.corporation.Corporation(ctx, doctrine.CorporationID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to fetch corporation %d", doctrine.CorporationID)
+	}
+
+	doctrine.Corporation = corporation
+
+	return nil
+
+}
+
+// doctrineFits returns the fits of the doctrine hydrated with their ships and required skills
+func (s *Service) doctrineFits(ctx context.Context, doctrineID uint) ([]*skillz.DoctrineFit, error) {
+
+	fits, err := s.doctrines.DoctrineFits(ctx, doctrineID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.Wrap(err, "failed to fetch doctrine fits")
+	}
+
+	if len(fits) == 0 {
+		return fits, nil
+	}
+
+	fitIDs := make([]uint, 0, len(fits))
+	fitsByID := make(map[uint]*skillz.DoctrineFit, len(fits))
+	for _, fit := range fits {
+		fit.Requirements = make([]*skillz.SkillRequirement, 0)
+		fitIDs = append(fitIDs, fit.ID)
+		fitsByID[fit.ID] = fit
+
+		fit.Ship, err = s.universe.Type(ctx, fit.ShipTypeID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to fetch type %d", fit.ShipTypeID)
+		}
+	}
+
+	skills, err := s.doctrines.DoctrineFitSkills(ctx, fitIDs...)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.Wrap(err, "failed to fetch doctrine fit skills")
+	}
+
+	for _, skill := range skills {
+		fit, ok := fitsByID[skill.FitID]
+		if !ok {
+			continue
+		}
+
+		fit.Requirements = append(fit.Requirements, &skillz.SkillRequirement{
+			SkillID: skill.SkillID,
+			Level:   skill.Level,
+		})
+	}
+
+	return fits, nil
+
+}
+
+func checkSharing(user *skillz.User) error {
+
+	if user.Character == nil || user.Settings == nil || !user.Settings.ShareWithCorporation {
+		return ErrNotSharing
+	}
+
+	return nil
+
+}
+
+// affiliated reports whether the character is a member of the doctrine's corporation
+// or, for alliance wide doctrines, a member of the doctrine's alliance
+func affiliated(character *skillz.Character, doctrine *skillz.Doctrine) bool {
+
+	if character.CorporationID == doctrine.CorporationID {
+		return true
+	}
+
+	return doctrine.AllianceID.Valid && character.AllianceID.Valid && character.AllianceID.Uint == doctrine.AllianceID.Uint
+
+}
+
+func memberName(user *skillz.User) string {
+
+	if user.Character == nil {
+		return ""
+	}
+
+	return user.Character.Name
+
+}
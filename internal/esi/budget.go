@@ -0,0 +1,148 @@
+package esi
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/eveisesi/skillz/internal"
+	"github.com/go-redis/redis/v8"
+	"github.com/pkg/errors"
+)
+
+type budget interface {
+	ErrorBudget(ctx context.Context) (*ErrorBudget, error)
+}
+
+type BudgetAPI interface {
+	budget
+}
+
+const (
+	headerErrorLimitRemain = "X-Esi-Error-Limit-Remain"
+	headerErrorLimitReset  = "X-Esi-Error-Limit-Reset"
+
+	// errorBudgetThreshold is the number of errors remaining in the current window below which
+	// all ESI traffic is paused until the window resets. ESI bans the IP once no errors remain
+	errorBudgetThreshold = 10
+)
+
+// ErrorBudget is ESI's error limit as last reported by ESI. The budget is shared by every
+// process making requests to ESI from the same IP, so it is stored in Redis
+type ErrorBudget struct {
+	Remain  int       `json:"remain"`
+	ResetAt time.Time `json:"reset_at"`
+	// Paused reports whether ESI traffic is paused until the window resets
+	Paused bool `json:"paused"`
+}
+
+// ErrorBudget returns the current error budget. Nil is returned when ESI has not reported a
+// budget for the current window, i.e. no requests have been made since the last window reset
+func (s *Service) ErrorBudget(ctx context.Context) (*ErrorBudget, error) {
+
+	results, err := s.redis.HGetAll(ctx, internal.ESIErrorBudget).Result()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch esi error budget")
+	}
+
+	if len(results) == 0 {
+		return nil, nil
+	}
+
+	remain, err := strconv.Atoi(results["remain"])
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse remaining esi error budget")
+	}
+
+	resetAt, err := strconv.ParseInt(results["reset_at"], 10, 64)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse esi error budget reset")
+	}
+
+	budget := &ErrorBudget{
+		Remain:  remain,
+		ResetAt: time.Unix(resetAt, 0),
+	}
+	budget.Paused = budget.Remain < errorBudgetThreshold && time.Now().Before(budget.ResetAt)
+
+	return budget, nil
+
+}
+
+// errorBudgetWindowSlack is how much later than the stored reset the reset reported by a response may
+// be while still belonging to the same window. The reset header only has a resolution of a second
+const errorBudgetWindowSlack = 2 * time.Second
+
+// storeErrorBudget keeps the lowest remaining budget reported within the same window, since responses
+// from concurrent requests are recorded in no particular order. A reset later than the stored reset and
+// its slack starts a new window. The budget expires with its window
+var storeErrorBudget = redis.NewScript(`
+local resetAt = tonumber(redis.call("HGET", KEYS[1], "reset_at"))
+if resetAt and tonumber(ARGV[2]) <= resetAt + tonumber(ARGV[3]) then
+	local remain = tonumber(redis.call("HGET", KEYS[1], "remain"))
+	if remain and remain <= tonumber(ARGV[1]) then
+		return 0
+	end
+	redis.call("HSET", KEYS[1], "remain", ARGV[1])
+	return 1
+end
+redis.call("HSET", KEYS[1], "remain", ARGV[1], "reset_at", ARGV[2])
+redis.call("EXPIREAT", KEYS[1], ARGV[2])
+return 1
+`)
+
+// recordErrorBudget stores the error budget reported by the response headers. The budget
+// expires with the window so that a stale budget never pauses traffic
+func (s *Service) recordErrorBudget(ctx context.Context, header http.Header) error {
+
+	remainStr, resetStr := header.Get(headerErrorLimitRemain), header.Get(headerErrorLimitReset)
+	if remainStr == "" || resetStr == "" {
+		return nil
+	}
+
+	remain, err := strconv.Atoi(remainStr)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse %s header", headerErrorLimitRemain)
+	}
+
+	reset, err := strconv.Atoi(resetStr)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse %s header", headerErrorLimitReset)
+	}
+
+	resetAt := time.Now().Add(time.Second * time.Duration(reset))
+
+	err = storeErrorBudget.Run(ctx, s.redis, []string{internal.ESIErrorBudget}, remain, resetAt.Unix(), int64(errorBudgetWindowSlack.Seconds())).Err()
+	return errors.Wrap(err, "failed to store esi error budget")
+
+}
+
+// waitForErrorBudget blocks until the error budget allows requests to be made to ESI again. Requests
+// are not held up when the budget cannot be read, since ESI will report the budget again on the next response
+func (s *Service) waitForErrorBudget(ctx context.Context) error {
+
+	budget, err := s.ErrorBudget(ctx)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to check esi error budget")
+		return nil
+	}
+
+	if budget == nil || !budget.Paused {
+		return nil
+	}
+
+	wait := time.Until(budget.ResetAt)
+	s.logger.WithField("remain", budget.Remain).WithField("wait", wait).Warn("esi error budget exhausted, pausing requests")
+
+	t := time.NewTimer(wait)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+
+}
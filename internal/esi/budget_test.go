@@ -0,0 +1,83 @@
+package esi
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/eveisesi/skillz/internal"
+	"github.com/go-redis/redis/v8"
+	"github.com/sirupsen/logrus"
+)
+
+func TestRecordErrorBudget(t *testing.T) {
+
+	type response struct {
+		remain, reset int
+	}
+
+	tests := []struct {
+		name      string
+		responses []response
+		remain    int
+	}{
+		{name: "single response", responses: []response{{remain: 80, reset: 30}}, remain: 80},
+		{name: "lower budget within the window", responses: []response{{remain: 80, reset: 30}, {remain: 70, reset: 30}}, remain: 70},
+		// A response to an earlier request may be recorded after a response to a later request
+		{name: "higher budget within the window", responses: []response{{remain: 70, reset: 30}, {remain: 80, reset: 30}}, remain: 70},
+		{name: "higher budget within the slack of the window", responses: []response{{remain: 70, reset: 30}, {remain: 80, reset: 31}}, remain: 70},
+		{name: "higher budget in the next window", responses: []response{{remain: 5, reset: 1}, {remain: 100, reset: 60}}, remain: 100},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			var ctx = context.Background()
+
+			mr, err := miniredis.Run()
+			if err != nil {
+				t.Fatalf("failed to start redis: %s", err)
+			}
+			defer mr.Close()
+
+			redisClient := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+			defer redisClient.Close()
+
+			logger := logrus.New()
+			logger.SetOutput(io.Discard)
+
+			s := New(http.DefaultClient, redisClient, logger, nil, nil)
+
+			for _, r := range test.responses {
+				header := make(http.Header)
+				header.Set(headerErrorLimitRemain, strconv.Itoa(r.remain))
+				header.Set(headerErrorLimitReset, strconv.Itoa(r.reset))
+
+				err = s.recordErrorBudget(ctx, header)
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+			}
+
+			budget, err := s.ErrorBudget(ctx)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if budget == nil || budget.Remain != test.remain {
+				t.Fatalf("got budget %+v, want %d remaining", budget, test.remain)
+			}
+
+			last := test.responses[len(test.responses)-1]
+			if ttl := mr.TTL(internal.ESIErrorBudget); ttl <= 0 || ttl > time.Duration(last.reset+1)*time.Second {
+				t.Errorf("got ttl %s, want the budget to expire with its window", ttl)
+			}
+
+		})
+	}
+
+}
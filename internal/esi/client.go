@@ -4,11 +4,9 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"fmt"
 	"io"
 	"net/http"
 	"net/url"
-	"strconv"
 	"time"
 
 	"github.com/eveisesi/skillz/internal/etag"
@@ -21,12 +19,14 @@ type API interface {
 	characters
 	corporations
 	alliance
+	budget
 }
 
 type Service struct {
-	client *http.Client
-	redis  *redis.Client
-	logger *logrus.Logger
+	client  *http.Client
+	redis   *redis.Client
+	logger  *logrus.Logger
+	baseURI *url.URL
 
 	etag etag.API
 }
@@ -39,9 +39,14 @@ const (
 	headerTimestampFormat = "Mon, 02 Jan 2006 15:04:05 MST"
 )
 
-func New(client *http.Client, redis *redis.Client, logger *logrus.Logger, etag etag.API) *Service {
+// New returns a client for ESI at the base URI, or for esi.evetech.net when the base URI is nil
+func New(client *http.Client, redis *redis.Client, logger *logrus.Logger, etag etag.API, baseURI *url.URL) *Service {
+	if baseURI == nil {
+		baseURI = &url.URL{Scheme: "https", Host: esiHost}
+	}
+
 	return &Service{
-		client, redis, logger, etag,
+		client, redis, logger, baseURI, etag,
 	}
 }
 
@@ -51,15 +56,31 @@ type out struct {
 	Status  int         `json:"status"`
 }
 
+// request executes the request against ESI. Requests that fail with a retryable status or a network error
+// are retried a bounded number of times with a backoff. Error responses are returned as an *Error
 func (s *Service) request(ctx context.Context, method, path string, body []byte, expected int, out *out, mods ...ModifierFunc) error {
 
 	uri, _ := url.ParseRequestURI(path)
-	uri.Scheme = "https"
-	uri.Host = esiHost
+	uri.Scheme = s.baseURI.Scheme
+	uri.Host = s.baseURI.Host
+
+	var lastErr error
+	var delay time.Duration
+	for attempt := 0; attempt < maxRequestAttempts; attempt++ {
 
-	for {
+		if delay > 0 {
+			err := waitForRetry(ctx, delay)
+			if err != nil {
+				return &RetryError{Err: err, LastErr: lastErr}
+			}
+		}
+
+		err := s.waitForErrorBudget(ctx)
+		if err != nil {
+			return errors.Wrap(err, "failed to wait for esi error budget")
+		}
 
-		req, err := http.NewRequest(method, uri.String(), bytes.NewReader(body))
+		req, err := http.NewRequestWithContext(ctx, method, uri.String(), bytes.NewReader(body))
 		if err != nil {
 			return errors.Wrap(err, "failed to build request")
 		}
@@ -73,58 +94,40 @@ func (s *Service) request(ctx context.Context, method, path string, body []byte,
 
 		res, err := s.client.Do(req)
 		if err != nil {
-			return errors.Wrap(err, "failed to execute request")
-		}
-
-		fmt.Printf("%s %s (%d)\n", method, path, res.StatusCode)
-
-		out.Status = res.StatusCode
-		out.Headers = res.Header
-
-		if res.StatusCode >= http.StatusBadRequest {
-			if res.StatusCode == http.StatusBadRequest {
-				return errors.Wrap(err, "404 Bad Request")
+			if ctx.Err() != nil {
+				return errors.Wrap(err, "failed to execute request")
 			}
 
-			if res.StatusCode == http.StatusUnauthorized {
-				return errors.Wrap(err, "403 Unauthorized")
-			}
-
-			if res.StatusCode == http.StatusBadGateway {
-				time.Sleep(time.Second)
-				continue
-			}
-
-			if res.StatusCode == http.StatusTooManyRequests {
-				waitStr := out.Headers.Get("x-esi-error-limit-reset")
-				if waitStr == "" {
-					time.Sleep(time.Second * 10)
-					continue
-				}
-
-				wait, err := strconv.ParseUint(waitStr, 10, 32)
-				if err != nil {
-					fmt.Println(errors.Wrap(err, "failed to parse value in expires header to uint"))
-					time.Sleep(time.Second * 10)
-					continue
-				}
-
-				fmt.Printf("http 429, sleeping %d seconds", wait)
-				time.Sleep(time.Second * time.Duration(wait))
+			lastErr = errors.Wrap(err, "failed to execute request")
+			delay = retryDelay(attempt, nil)
+			continue
+		}
 
-			}
+		err = s.recordErrorBudget(ctx, res.Header)
+		if err != nil {
+			s.logger.WithError(err).Error("failed to record esi error budget")
 		}
 
 		data, err := io.ReadAll(res.Body)
+		_ = res.Body.Close()
 		if err != nil {
-			return errors.Wrapf(err, "expected status %d, got %d: unable to parse request body", expected, res.StatusCode)
+			return errors.Wrapf(err, "expected status %d, got %d: unable to read response body", expected, res.StatusCode)
 		}
 
-		err = res.Body.Close()
-		if err != nil {
-			if err.Error() != "context cancelled" {
-				fmt.Printf("\n\nfailed to close requst body for %s (%d): %s %s\n\n", fmt.Sprintf("%s %s", method, path), res.StatusCode, err, string(data))
+		s.logger.WithField("method", method).WithField("path", path).WithField("status", res.StatusCode).Debug("esi request")
+
+		out.Status = res.StatusCode
+		out.Headers = res.Header
+
+		if res.StatusCode >= http.StatusBadRequest {
+			esiErr := newError(method, path, res, data)
+			if !esiErr.Retryable {
+				return esiErr
 			}
+
+			lastErr = esiErr
+			delay = retryDelay(attempt, res)
+			continue
 		}
 
 		for _, mod := range mods {
@@ -147,6 +150,8 @@ func (s *Service) request(ctx context.Context, method, path string, body []byte,
 
 	}
 
+	return lastErr
+
 }
 
 func hash(s string) string {
@@ -0,0 +1,42 @@
+package esi_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/eveisesi/skillz/internal/esi"
+	"github.com/eveisesi/skillz/internal/esitest"
+	"github.com/pkg/errors"
+)
+
+func TestRequestGivesUpRetryingWhenContextEnds(t *testing.T) {
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := newServer(t)
+	server.SetStatus("/characters/90000001/", http.StatusServiceUnavailable)
+
+	// The context is cancelled while the request waits to be retried
+	s := newService(t, server, func(r *http.Request) { cancel() })
+
+	_, err := s.GetCharacter(ctx, esitest.CharacterID)
+	if err == nil {
+		t.Fatal("expected an error, got nil")
+	}
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the error to match context.Canceled, got %s", err)
+	}
+
+	var esiErr *esi.Error
+	if !errors.As(err, &esiErr) || esiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected the error to expose the error of the last attempt, got %s", err)
+	}
+
+	if got := len(server.Requests()); got != 1 {
+		t.Errorf("got %d requests, want the request not to be retried", got)
+	}
+
+}
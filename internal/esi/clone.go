@@ -7,6 +7,7 @@ import (
 
 	"github.com/eveisesi/skillz"
 	"github.com/pkg/errors"
+	"github.com/volatiletech/null"
 )
 
 type CloneAPI interface {
@@ -16,9 +17,62 @@ type CloneAPI interface {
 }
 
 type clones interface {
+	GetCharacterClones(ctx context.Context, characterID uint64, mods ...ModifierFunc) ([]*skillz.CharacterJumpClone, error)
 	GetCharacterImplants(ctx context.Context, characterID uint64, mods ...ModifierFunc) ([]*skillz.CharacterImplant, error)
 }
 
+// characterClones is the structure of the response of the ESI character clones endpoint
+type characterClones struct {
+	JumpClones []*struct {
+		JumpCloneID  uint                `json:"jump_clone_id"`
+		Name         null.String         `json:"name"`
+		LocationID   uint64              `json:"location_id"`
+		LocationType skillz.LocationType `json:"location_type"`
+		Implants     []uint              `json:"implants"`
+	} `json:"jump_clones"`
+}
+
+func (s *Service) GetCharacterClones(ctx context.Context, characterID uint64, mods ...ModifierFunc) ([]*skillz.CharacterJumpClone, error) {
+
+	var clones = new(characterClones)
+	var out = new(out)
+	out.Data = clones
+	endpoint := fmt.Sprintf(endpoints[GetCharacterClones], characterID)
+	err := s.request(ctx, http.MethodGet, endpoint, nil, http.StatusOK, out, mods...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to exec request to ESI for character clones")
+	}
+
+	if out.Status == http.StatusNotModified {
+		return nil, nil
+	}
+
+	jumpClones := make([]*skillz.CharacterJumpClone, 0, len(clones.JumpClones))
+	for _, clone := range clones.JumpClones {
+		jumpClone := &skillz.CharacterJumpClone{
+			CharacterID:  characterID,
+			JumpCloneID:  clone.JumpCloneID,
+			Name:         clone.Name,
+			LocationID:   clone.LocationID,
+			LocationType: clone.LocationType,
+			Implants:     make([]*skillz.CharacterJumpCloneImplant, 0, len(clone.Implants)),
+		}
+
+		for _, id := range clone.Implants {
+			jumpClone.Implants = append(jumpClone.Implants, &skillz.CharacterJumpCloneImplant{
+				CharacterID: characterID,
+				JumpCloneID: clone.JumpCloneID,
+				ImplantID:   id,
+			})
+		}
+
+		jumpClones = append(jumpClones, jumpClone)
+	}
+
+	return jumpClones, nil
+
+}
+
 func (s *Service) GetCharacterImplants(ctx context.Context, characterID uint64, mods ...ModifierFunc) ([]*skillz.CharacterImplant, error) {
 
 	// var implants = make([]*skillz.CharacterImplant, 0, 10)
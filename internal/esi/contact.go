@@ -3,10 +3,10 @@ package esi
 import (
 	"context"
 	"fmt"
-	"net/http"
 
 	"github.com/eveisesi/skillz"
 	"github.com/pkg/errors"
+	"github.com/volatiletech/null"
 )
 
 type ContactAPI interface {
@@ -19,24 +19,25 @@ type contacts interface {
 	GetCharacterContacts(ctx context.Context, characterID uint64, mods ...ModifierFunc) ([]*skillz.CharacterContact, error)
 }
 
+// GetCharacterContacts fetches every page of the character's contacts. Each page is cached with its own etag,
+// so mods must not add an etag of their own. Nil is returned when none of the pages have changed
 func (s *Service) GetCharacterContacts(ctx context.Context, characterID uint64, mods ...ModifierFunc) ([]*skillz.CharacterContact, error) {
 
 	var contacts = make([]*skillz.CharacterContact, 0)
-	var out = new(out)
-	out.Data = &contacts
 	endpoint := fmt.Sprintf(endpoints[GetCharacterContacts], characterID)
-	err := s.request(ctx, http.MethodGet, endpoint, nil, http.StatusOK, out, mods...)
-
-	if err == nil {
-		for _, contact := range contacts {
-			contact.CharacterID = characterID
-		}
+	modified, err := s.requestPaged(ctx, GetCharacterContacts, Params{CharacterID: null.Uint64From(characterID)}, endpoint, &contacts, mods...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute request to ESI for Character data")
 	}
 
-	if out.Status == http.StatusNotModified {
+	if !modified {
 		return nil, nil
 	}
 
-	return contacts, errors.Wrap(err, "failed to execute request to ESI for Character data")
+	for _, contact := range contacts {
+		contact.CharacterID = characterID
+	}
+
+	return contacts, nil
 
 }
@@ -88,13 +88,17 @@ var Resolvers = endpointResolvers{
 
 	GetCharacter:                   resolverFuncs["characterID"](GetCharacter),
 	GetCharacterCorporationHistory: resolverFuncs["characterID"](GetCharacterCorporationHistory),
-	GetCharacterContacts:           resolverFuncs["characterID"](GetCharacterContacts),
 	GetCharacterClones:             resolverFuncs["characterID"](GetCharacterClones),
 	GetCharacterImplants:           resolverFuncs["characterID"](GetCharacterImplants),
 	GetCharacterSkills:             resolverFuncs["characterID"](GetCharacterSkills),
 	GetCharacterSkillQueue:         resolverFuncs["characterID"](GetCharacterSkillQueue),
 	GetCharacterAttributes:         resolverFuncs["characterID"](GetCharacterAttributes),
 
+	GetCharacterAssets:        resolverFuncs["characterIDPage"](GetCharacterAssets),
+	GetCharacterContacts:      resolverFuncs["characterIDPage"](GetCharacterContacts),
+	GetCharacterContracts:     resolverFuncs["characterIDPage"](GetCharacterContracts),
+	GetCharacterWalletJournal: resolverFuncs["characterIDPage"](GetCharacterWalletJournal),
+
 	GetCorporation:                resolverFuncs["corporationID"](GetCorporation),
 	GetCorporationAllianceHistory: resolverFuncs["corporationID"](GetCorporationAllianceHistory),
 
@@ -129,6 +133,9 @@ var endpoints = endpointMap{
 	GetCharacterSkillQueue:         "/v2/characters/%d/skillqueue/",
 	GetCharacterAttributes:         "/v1/characters/%d/attributes/",
 	GetCharacterCorporationHistory: "/v1/characters/%d/corporationhistory/",
+	GetCharacterAssets:             "/v5/characters/%d/assets/",
+	GetCharacterContracts:          "/v1/characters/%d/contracts/",
+	GetCharacterWalletJournal:      "/v6/characters/%d/wallet/journal/",
 	GetCorporation:                 "/v5/corporations/%d/",
 	GetCorporationAllianceHistory:  "/v3/corporations/%d/alliancehistory/",
 
@@ -165,6 +172,24 @@ var resolverFuncs = map[string]func(endpoint EndpointID) resolverFunc{
 			return hash(fmt.Sprintf(path, params.CharacterID.Uint64)), nil
 		}
 	},
+	// characterIDPage resolves a page of a paged character endpoint, so that each page is cached with its own etag
+	"characterIDPage": func(endpoint EndpointID) resolverFunc {
+		return func(params *Params) (string, error) {
+			if params == nil {
+				return "", ErrNilParams
+			}
+			if !params.CharacterID.Valid {
+				return "", ErrInvalidParameter{"characterID"}
+			}
+			if !params.Page.Valid {
+				return "", ErrInvalidParameter{"page"}
+			}
+
+			path := endpoints[endpoint]
+
+			return hash(pagePath(fmt.Sprintf(path, params.CharacterID.Uint64), params.Page.Uint)), nil
+		}
+	},
 	"corporationID": func(endpoint EndpointID) resolverFunc {
 		return func(params *Params) (string, error) {
 			if params == nil {
@@ -0,0 +1,152 @@
+package esi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrMissingScope matches an Error returned when ESI rejects the request because the
+// token used to authorize it has not been granted the scope the endpoint requires
+var ErrMissingScope = errors.New("token is not valid for scope")
+
+// statusErrorLimited is the status ESI responds with once the error limit has been reached
+const statusErrorLimited = 420
+
+const (
+	// maxRequestAttempts bounds the number of times a request is attempted before the last error is returned
+	maxRequestAttempts = 4
+	baseRetryDelay     = time.Millisecond * 500
+	maxRetryDelay      = time.Second * 30
+)
+
+// Error is an error response from ESI
+type Error struct {
+	Method     string
+	Endpoint   string
+	StatusCode int
+	// Message is the error reported by ESI in the body of the response
+	Message string
+	// Retryable reports whether the request may succeed if it is attempted again
+	Retryable bool
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("esi responded to %s %s with %d: %s", e.Method, e.Endpoint, e.StatusCode, e.Message)
+}
+
+func (e *Error) Is(target error) bool {
+	return target == ErrMissingScope && e.StatusCode == http.StatusForbidden && strings.Contains(e.Message, "scope")
+}
+
+// TokenRejected reports whether ESI rejected the token used to authorize the request,
+// either because the token is invalid or it lacks the scope required by the endpoint
+func (e *Error) TokenRejected() bool {
+	return e.StatusCode == http.StatusUnauthorized || (e.StatusCode == http.StatusForbidden && strings.Contains(e.Message, "token"))
+}
+
+// Unavailable reports whether the request failed because ESI, or the service behind it, is down or overloaded
+func (e *Error) Unavailable() bool {
+	return e.StatusCode >= http.StatusInternalServerError || e.StatusCode == statusErrorLimited || e.StatusCode == http.StatusTooManyRequests
+}
+
+// RetryError is returned when the context ends while a failed request is waiting to be retried. It unwraps
+// to the error of the context, so that errors.Is matches context.Canceled and context.DeadlineExceeded, and
+// errors.As matches the error of the last attempt, e.g. an *Error
+type RetryError struct {
+	// Err is the reason the request was given up on
+	Err error
+	// LastErr is the error of the last attempt
+	LastErr error
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("gave up retrying request: %s: %s", e.Err, e.LastErr)
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+func (e *RetryError) As(target interface{}) bool {
+	return errors.As(e.LastErr, target)
+}
+
+// newError builds the error for the response. ESI reports errors as a json object with an error property
+func newError(method, endpoint string, res *http.Response, body []byte) *Error {
+
+	var payload struct {
+		Error string `json:"error"`
+	}
+
+	message := http.StatusText(res.StatusCode)
+	if err := json.Unmarshal(body, &payload); err == nil && payload.Error != "" {
+		message = payload.Error
+	}
+
+	return &Error{
+		Method:     method,
+		Endpoint:   endpoint,
+		StatusCode: res.StatusCode,
+		Message:    message,
+		Retryable:  retryableStatus(res.StatusCode),
+	}
+
+}
+
+func retryableStatus(status int) bool {
+	switch status {
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout,
+		http.StatusTooManyRequests, statusErrorLimited:
+		return true
+	}
+
+	return false
+}
+
+// retryDelay returns how long to wait before the next attempt of a request. ESI tells us how long to
+// wait when the request was limited, otherwise the delay backs off exponentially with full jitter
+func retryDelay(attempt int, res *http.Response) time.Duration {
+
+	if res != nil && (res.StatusCode == statusErrorLimited || res.StatusCode == http.StatusTooManyRequests) {
+		if reset, err := strconv.Atoi(res.Header.Get(headerErrorLimitReset)); err == nil && reset > 0 {
+			return time.Second * time.Duration(reset)
+		}
+	}
+
+	delay := baseRetryDelay << uint(attempt)
+	if delay <= 0 || delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+
+	return time.Duration(rand.Int63n(int64(delay))) + time.Millisecond
+
+}
+
+// waitForRetry blocks for the delay, returning early with the context's error if the context is cancelled.
+// When the deadline of the context would pass before the delay has elapsed, context.DeadlineExceeded is
+// returned straight away, i.e. before the deadline has actually passed and while ctx.Err() is still nil
+func waitForRetry(ctx context.Context, delay time.Duration) error {
+
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+		return context.DeadlineExceeded
+	}
+
+	t := time.NewTimer(delay)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+
+}
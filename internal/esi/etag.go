@@ -22,3 +22,7 @@ func (s *Service) Etag(ctx context.Context, endpointID EndpointID, params *Param
 	return etagID, etag, err
 
 }
+
+type EtagAPI interface {
+	etags
+}
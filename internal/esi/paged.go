@@ -0,0 +1,256 @@
+package esi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/volatiletech/null"
+)
+
+const (
+	headerPages        = "X-Pages"
+	headerLastModified = "Last-Modified"
+
+	// maxConcurrentPages caps the number of pages of a paged endpoint fetched at once
+	maxConcurrentPages = 5
+	// maxPagedAttempts bounds the number of times a paged fetch is started over because the pages changed
+	maxPagedAttempts = 3
+)
+
+var errPagesChanged = errors.New("pages changed while being fetched")
+
+// page is a single page of a paged endpoint. Data is a pointer to the slice the page was decoded into
+type page struct {
+	data         reflect.Value
+	status       int
+	pages        int
+	lastModified string
+
+	// The etag of the page is only cached once the pages have been fetched without changing
+	etagID  string
+	headers http.Header
+}
+
+func pagePath(path string, page uint) string {
+	return fmt.Sprintf("%s?page=%d", path, page)
+}
+
+// requestPaged fetches every page of a paged endpoint and appends the items of the pages, in page order, to
+// the slice that out points to. Each page is cached with its own etag, resolved from the params with the page set.
+// False is returned, and out is left untouched, when none of the pages have changed since they were last fetched.
+// ESI updates every page of an endpoint at once, so the fetch is started over when the pages report a different
+// Last-Modified or page count, i.e. the pages changed while they were being fetched. The etags of the pages are
+// only cached once the pages are returned, so that pages of an abandoned fetch are not reported as unchanged later
+func (s *Service) requestPaged(ctx context.Context, endpointID EndpointID, params Params, path string, out interface{}, mods ...ModifierFunc) (bool, error) {
+
+	target := reflect.ValueOf(out)
+	if target.Kind() != reflect.Ptr || target.Elem().Kind() != reflect.Slice {
+		return false, errors.Errorf("expected a pointer to a slice for out, got %T", out)
+	}
+
+	for attempt := 1; attempt <= maxPagedAttempts; attempt++ {
+		pages, err := s.fetchPages(ctx, endpointID, params, path, target.Elem().Type(), mods)
+		if errors.Is(err, errPagesChanged) {
+			s.logger.WithField("path", path).WithField("attempt", attempt).Warn("pages changed while being fetched, starting over")
+			continue
+		}
+
+		if err != nil {
+			return false, err
+		}
+
+		err = s.cachePageEtags(ctx, pages)
+		if err != nil {
+			return false, err
+		}
+
+		// Unmodified pages are fetched again when any page has been modified, so either all pages are unmodified or none are
+		if pages[0].status == http.StatusNotModified {
+			return false, nil
+		}
+
+		items := target.Elem()
+		for _, page := range pages {
+			items = reflect.AppendSlice(items, page.data.Elem())
+		}
+		target.Elem().Set(items)
+
+		return true, nil
+	}
+
+	return false, errors.Wrapf(errPagesChanged, "gave up on %s after %d attempts", path, maxPagedAttempts)
+
+}
+
+// fetchPages fetches the first page to learn the number of pages and then the remaining pages concurrently.
+// Pages that have not been modified are fetched again without their etag when any other page has been
+// modified, since their items are needed for the merged result
+func (s *Service) fetchPages(ctx context.Context, endpointID EndpointID, params Params, path string, sliceType reflect.Type, mods []ModifierFunc) ([]*page, error) {
+
+	first, err := s.fetchPage(ctx, endpointID, params, path, 1, sliceType, true, mods)
+	if err != nil {
+		return nil, err
+	}
+
+	pages := make([]*page, first.pages)
+	pages[0] = first
+
+	remaining := make([]uint, 0, first.pages-1)
+	for i := 2; i <= first.pages; i++ {
+		remaining = append(remaining, uint(i))
+	}
+
+	err = s.fetchPagesConcurrently(ctx, endpointID, params, path, remaining, sliceType, true, mods, pages)
+	if err != nil {
+		return nil, err
+	}
+
+	var modified bool
+	var unmodified = make([]uint, 0, len(pages))
+	for i, page := range pages {
+		if page.status == http.StatusNotModified {
+			unmodified = append(unmodified, uint(i+1))
+			continue
+		}
+		modified = true
+	}
+
+	if !modified {
+		return pages, nil
+	}
+
+	err = s.fetchPagesConcurrently(ctx, endpointID, params, path, unmodified, sliceType, false, mods, pages)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, page := range pages {
+		if page.pages != first.pages {
+			return nil, errPagesChanged
+		}
+
+		if page.lastModified != "" && first.lastModified != "" && page.lastModified != first.lastModified {
+			return nil, errPagesChanged
+		}
+	}
+
+	return pages, nil
+
+}
+
+// fetchPagesConcurrently fetches the pages, at most maxConcurrentPages at a time, storing each page at its index in results
+func (s *Service) fetchPagesConcurrently(ctx context.Context, endpointID EndpointID, params Params, path string, numbers []uint, sliceType reflect.Type, conditional bool, mods []ModifierFunc, results []*page) error {
+
+	if len(numbers) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg = new(sync.WaitGroup)
+	var mx = new(sync.Mutex)
+	var sem = make(chan struct{}, maxConcurrentPages)
+	var firstErr error
+
+	for _, number := range numbers {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(number uint) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			page, err := s.fetchPage(ctx, endpointID, params, path, number, sliceType, conditional, mods)
+
+			mx.Lock()
+			defer mx.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				return
+			}
+
+			if int(number) > len(results) {
+				// The page count grew after the first page was fetched
+				if firstErr == nil {
+					firstErr = errPagesChanged
+					cancel()
+				}
+				return
+			}
+
+			results[number-1] = page
+		}(number)
+	}
+
+	wg.Wait()
+
+	return firstErr
+
+}
+
+// cachePageEtags caches the etag of each page
+func (s *Service) cachePageEtags(ctx context.Context, pages []*page) error {
+
+	for i, page := range pages {
+		err := s.CacheEtag(ctx, page.etagID, nil)(nil, &http.Response{Header: page.headers})
+		if err != nil {
+			return errors.Wrapf(err, "failed to cache etag of page %d", i+1)
+		}
+	}
+
+	return nil
+
+}
+
+// fetchPage fetches a single page. When conditional, the page is requested with the etag cached for the page
+func (s *Service) fetchPage(ctx context.Context, endpointID EndpointID, params Params, path string, number uint, sliceType reflect.Type, conditional bool, mods []ModifierFunc) (*page, error) {
+
+	params.Page = null.UintFrom(number)
+
+	etagID, etag, err := s.Etag(ctx, endpointID, &params)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to fetch etag for page %d", number)
+	}
+
+	pageMods := append(make([]ModifierFunc, 0, len(mods)+1), mods...)
+	if conditional && etag != nil && etag.Etag != "" {
+		pageMods = append(pageMods, s.AddIfNoneMatchHeader(ctx, etag.Etag))
+	}
+
+	data := reflect.New(sliceType)
+	out := &out{Data: data.Interface()}
+	err = s.request(ctx, http.MethodGet, pagePath(path, number), nil, http.StatusOK, out, pageMods...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to fetch page %d", number)
+	}
+
+	pages := 1
+	if header := out.Headers.Get(headerPages); header != "" {
+		pages, err = strconv.Atoi(header)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse %s header", headerPages)
+		}
+	}
+
+	if pages < 1 {
+		pages = 1
+	}
+
+	return &page{
+		data:         data,
+		status:       out.Status,
+		pages:        pages,
+		lastModified: out.Headers.Get(headerLastModified),
+		etagID:       etagID,
+		headers:      out.Headers,
+	}, nil
+
+}
@@ -0,0 +1,248 @@
+package esi_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/eveisesi/skillz"
+	"github.com/eveisesi/skillz/internal/cache"
+	"github.com/eveisesi/skillz/internal/esi"
+	"github.com/eveisesi/skillz/internal/esitest"
+	"github.com/eveisesi/skillz/internal/etag"
+	"github.com/eveisesi/skillz/internal/memory"
+	"github.com/go-redis/redis/v8"
+	"github.com/sirupsen/logrus"
+)
+
+const contactsPath = "/v2/characters/90000001/contacts/"
+
+// roundTripperFunc adapts a function to an http.RoundTripper
+type roundTripperFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// newService returns the esi service for the fake ESI. afterRequest, if any, is called after each response is received
+func newService(t *testing.T, server *esitest.Server, afterRequest func(r *http.Request)) *esi.Service {
+	t.Helper()
+
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("failed to start redis: %s", err)
+	}
+	t.Cleanup(mr.Close)
+
+	redisClient := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = redisClient.Close() })
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	client := server.Client()
+	transport := client.Transport
+	client.Transport = roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		res, err := transport.RoundTrip(r)
+		if err == nil && afterRequest != nil {
+			afterRequest(r)
+		}
+		return res, err
+	})
+
+	cache := cache.New(redisClient, true)
+
+	return esi.New(client, redisClient, logger, etag.New(cache, memory.NewETagRepository(memory.NewDB())), server.BaseURI())
+
+}
+
+func newServer(t *testing.T) *esitest.Server {
+	t.Helper()
+
+	server, err := esitest.NewServer()
+	if err != nil {
+		t.Fatalf("failed to start fake esi: %s", err)
+	}
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func contactsPage(contactIDs ...uint) []byte {
+	contacts := make([]string, 0, len(contactIDs))
+	for _, id := range contactIDs {
+		contacts = append(contacts, fmt.Sprintf(`{"contact_id":%d,"contact_type":"character","standing":5}`, id))
+	}
+	return []byte("[" + strings.Join(contacts, ",") + "]")
+}
+
+func contactIDs(contacts []*skillz.CharacterContact) string {
+	ids := make([]string, 0, len(contacts))
+	for _, contact := range contacts {
+		ids = append(ids, fmt.Sprint(contact.ContactID))
+	}
+	return strings.Join(ids, ",")
+}
+
+// contactRequests counts the requests to the contacts since the previous count
+type contactRequests struct {
+	server *esitest.Server
+	seen   int
+}
+
+func (c *contactRequests) since() int {
+	var count int
+	for _, request := range c.server.Requests() {
+		if strings.Contains(request, contactsPath) {
+			count++
+		}
+	}
+
+	since := count - c.seen
+	c.seen = count
+	return since
+}
+
+func token(t *testing.T, server *esitest.Server) string {
+	t.Helper()
+
+	token, err := server.Token(esitest.CharacterID, skillz.ReadContactsV1)
+	if err != nil {
+		t.Fatalf("failed to issue token: %s", err)
+	}
+
+	return token
+}
+
+func TestPagedMergesPages(t *testing.T) {
+
+	var ctx = context.Background()
+
+	server := newServer(t)
+	server.SetPages(contactsPath, contactsPage(1, 2), contactsPage(3, 4), contactsPage(5))
+
+	s := newService(t, server, nil)
+	requests := &contactRequests{server: server}
+	auth := s.AddAuthorizationHeader(ctx, token(t, server))
+
+	contacts, err := s.GetCharacterContacts(ctx, esitest.CharacterID, auth)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got := contactIDs(contacts); got != "1,2,3,4,5" {
+		t.Errorf("got contacts %s, want the contacts of every page in page order", got)
+	}
+	if contacts[0].CharacterID != esitest.CharacterID {
+		t.Error("expected the contacts to carry the character id")
+	}
+	if got := requests.since(); got != 3 {
+		t.Errorf("got %d requests, want one per page", got)
+	}
+
+	// Every page responds with 304 now, so nothing has changed
+	contacts, err = s.GetCharacterContacts(ctx, esitest.CharacterID, auth)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if contacts != nil {
+		t.Errorf("expected nil when no page has been modified, got %d contacts", len(contacts))
+	}
+	if got := requests.since(); got != 3 {
+		t.Errorf("got %d requests, want one conditional request per page", got)
+	}
+
+	// A single modified page requires the unmodified pages to be fetched again for the merged result
+	server.SetPages(contactsPath, contactsPage(1, 2), contactsPage(3, 6), contactsPage(5))
+
+	contacts, err = s.GetCharacterContacts(ctx, esitest.CharacterID, auth)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got := contactIDs(contacts); got != "1,2,3,6,5" {
+		t.Errorf("got contacts %s, want the modified page merged with the unmodified pages", got)
+	}
+	if got := requests.since(); got != 5 {
+		t.Errorf("got %d requests, want a conditional request per page and the two unmodified pages again", got)
+	}
+
+}
+
+func TestPagedRestartsWhenPagesChange(t *testing.T) {
+
+	var ctx = context.Background()
+	var once sync.Once
+
+	server := newServer(t)
+	server.SetPages(contactsPath, contactsPage(1), contactsPage(2), contactsPage(3))
+	server.SetLastModified(contactsPath, time.Now().Add(-time.Hour))
+
+	// ESI publishes new pages right after the first page has been fetched
+	s := newService(t, server, func(r *http.Request) {
+		if strings.Contains(r.URL.Path, "/contacts/") && r.URL.Query().Get("page") == "1" {
+			once.Do(func() {
+				server.SetPages(contactsPath, contactsPage(4), contactsPage(5), contactsPage(6))
+				server.SetLastModified(contactsPath, time.Now())
+			})
+		}
+	})
+	requests := &contactRequests{server: server}
+
+	contacts, err := s.GetCharacterContacts(ctx, esitest.CharacterID, s.AddAuthorizationHeader(ctx, token(t, server)))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got := contactIDs(contacts); got != "4,5,6" {
+		t.Errorf("got contacts %s, want the contacts of the new pages only", got)
+	}
+	if got := requests.since(); got != 6 {
+		t.Errorf("got %d requests, want every page fetched by both attempts", got)
+	}
+
+}
+
+func TestPagedGivesUpWhenPagesKeepChanging(t *testing.T) {
+
+	var ctx = context.Background()
+	var mx = new(sync.Mutex)
+	var changing = true
+	var lastModified = time.Now().Add(-time.Hour)
+
+	server := newServer(t)
+	server.SetPages(contactsPath, contactsPage(1), contactsPage(2))
+
+	s := newService(t, server, func(r *http.Request) {
+		mx.Lock()
+		defer mx.Unlock()
+
+		if changing && strings.Contains(r.URL.Path, "/contacts/") && r.URL.Query().Get("page") == "1" {
+			lastModified = lastModified.Add(time.Minute)
+			server.SetLastModified(contactsPath, lastModified)
+		}
+	})
+	auth := s.AddAuthorizationHeader(ctx, token(t, server))
+
+	_, err := s.GetCharacterContacts(ctx, esitest.CharacterID, auth)
+	if err == nil {
+		t.Fatal("expected an error when the pages change on every attempt, got nil")
+	}
+
+	mx.Lock()
+	changing = false
+	mx.Unlock()
+
+	// The pages of the abandoned fetch were never returned, so they must not be reported as unchanged
+	contacts, err := s.GetCharacterContacts(ctx, esitest.CharacterID, auth)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got := contactIDs(contacts); got != "1,2" {
+		t.Errorf("got contacts %s, want the contacts of both pages", got)
+	}
+
+}
@@ -0,0 +1,67 @@
+package esitest
+
+import (
+	"embed"
+	"io/fs"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// The character that the fixtures are recorded for, along with their corporation and alliance
+const (
+	CharacterID   uint64 = 90000001
+	CharacterName        = "Fixture Pilot"
+	CorporationID uint   = 98000001
+	AllianceID    uint   = 99000001
+	OwnerHash            = "f1xtUreOwn3rHash="
+)
+
+//go:embed fixtures
+var files embed.FS
+
+var versionPrefix = regexp.MustCompile(`^/(v\d+|latest|legacy|dev)/`)
+
+// fixtureKey normalizes an ESI path into the key that its fixture is stored under by dropping the version
+// prefix and the trailing slash, so that /v4/characters/90000001/skills/ is stored as characters/90000001/skills
+func fixtureKey(p string) string {
+
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p
+	}
+
+	p = versionPrefix.ReplaceAllString(p, "/")
+
+	return strings.Trim(p, "/")
+
+}
+
+// loadFixtures reads the recorded responses, keyed by the path they are served at. The response for
+// /v4/characters/90000001/skills/ is recorded at fixtures/characters/90000001/skills.json
+func loadFixtures() (map[string][]byte, error) {
+
+	fixtures := make(map[string][]byte)
+	err := fs.WalkDir(files, "fixtures", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() || path.Ext(p) != ".json" {
+			return nil
+		}
+
+		data, err := files.ReadFile(p)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read fixture %s", p)
+		}
+
+		fixtures[fixtureKey(strings.TrimSuffix(strings.TrimPrefix(p, "fixtures"), ".json"))] = data
+
+		return nil
+	})
+
+	return fixtures, errors.Wrap(err, "failed to load fixtures")
+
+}
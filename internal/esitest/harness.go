@@ -0,0 +1,65 @@
+package esitest
+
+import (
+	"io"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/eveisesi/skillz/internal/cache"
+	"github.com/eveisesi/skillz/internal/esi"
+	"github.com/eveisesi/skillz/internal/etag"
+	"github.com/eveisesi/skillz/internal/memory"
+	"github.com/go-redis/redis/v8"
+	"github.com/sirupsen/logrus"
+)
+
+// Harness is the infrastructure shared by the service tests: a fake ESI, a redis backed by miniredis,
+// an in-memory database and the ESI client wired to all of them. Tests wire their own services on top
+type Harness struct {
+	Server *Server
+	Redis  *redis.Client
+	Logger *logrus.Logger
+	DB     *memory.DB
+	Cache  *cache.Service
+	ETag   *etag.Service
+	ESI    *esi.Service
+}
+
+// NewHarness starts the fake ESI and miniredis. Both are closed when the test and its subtests complete
+func NewHarness(t *testing.T) *Harness {
+	t.Helper()
+
+	server, err := NewServer()
+	if err != nil {
+		t.Fatalf("failed to start fake esi: %s", err)
+	}
+	t.Cleanup(server.Close)
+
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("failed to start redis: %s", err)
+	}
+	t.Cleanup(mr.Close)
+
+	redisClient := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = redisClient.Close() })
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	db := memory.NewDB()
+
+	cache := cache.New(redisClient, true)
+	etag := etag.New(cache, memory.NewETagRepository(db))
+
+	return &Harness{
+		Server: server,
+		Redis:  redisClient,
+		Logger: logger,
+		DB:     db,
+		Cache:  cache,
+		ETag:   etag,
+		ESI:    esi.New(server.Client(), redisClient, logger, etag, server.BaseURI()),
+	}
+
+}
@@ -0,0 +1,229 @@
+// Package esitest provides a fake ESI and SSO that serves recorded fixtures, so that the
+// services that talk to ESI can be tested end to end without a network
+package esitest
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-http-utils/headers"
+	"github.com/lestrrat-go/jwx/jwk"
+	"github.com/pkg/errors"
+)
+
+// cacheDuration is how long ESI responses are reported as cached for through the Expires header
+const cacheDuration = 5 * time.Minute
+
+// Server is a fake ESI and SSO. ESI paths are served from the recorded fixtures and the SSO issues
+// tokens signed with a key that is generated when the server is started and published at /oauth/jwks
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	fixtures map[string][]byte
+	pages    map[string][][]byte
+	modified map[string]time.Time
+	statuses map[string]int
+	requests []string
+
+	key     jwk.Key
+	jwks    jwk.Set
+	codes   map[string]*grant
+	refresh map[string]*grant
+	owners  map[uint64]string
+}
+
+// NewServer starts a fake ESI and SSO. The server should be closed once the test is done with it
+func NewServer() (*Server, error) {
+
+	fixtures, err := loadFixtures()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		fixtures: fixtures,
+		pages:    make(map[string][][]byte),
+		modified: make(map[string]time.Time),
+		statuses: make(map[string]int),
+		codes:    make(map[string]*grant),
+		refresh:  make(map[string]*grant),
+		owners:   make(map[uint64]string),
+	}
+
+	err = s.generateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/oauth/jwks", s.handleJWKS)
+	mux.HandleFunc("/v2/oauth/authorize", s.handleAuthorize)
+	mux.HandleFunc("/v2/oauth/token", s.handleToken)
+	mux.HandleFunc("/", s.handleESI)
+
+	s.Server = httptest.NewServer(mux)
+
+	return s, nil
+
+}
+
+// BaseURI is the base URI of the server, for use as both the ESI and the SSO base URI
+func (s *Server) BaseURI() *url.URL {
+	uri, _ := url.Parse(s.URL)
+	return uri
+}
+
+// SetFixture overrides the response served for the ESI path, with or without its version prefix
+func (s *Server) SetFixture(path string, body []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.fixtures[fixtureKey(path)] = body
+}
+
+// SetPages makes the ESI path a paged endpoint that serves the pages, selected with the page query parameter,
+// and reports their count through the X-Pages header
+func (s *Server) SetPages(path string, pages ...[]byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pages[fixtureKey(path)] = pages
+}
+
+// SetLastModified overrides the Last-Modified header of the ESI path, which is otherwise the start of the current hour
+func (s *Server) SetLastModified(path string, lastModified time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.modified[fixtureKey(path)] = lastModified
+}
+
+// SetStatus makes the ESI path respond with the status code and an ESI error body. A status of 0
+// restores the fixture
+func (s *Server) SetStatus(path string, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if status == 0 {
+		delete(s.statuses, fixtureKey(path))
+		return
+	}
+
+	s.statuses[fixtureKey(path)] = status
+}
+
+// Requests returns the method and path of each request the server has received, in the order they were received
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append(make([]string, 0, len(s.requests)), s.requests...)
+}
+
+func (s *Server) handleESI(w http.ResponseWriter, r *http.Request) {
+
+	key := fixtureKey(r.URL.Path)
+
+	s.mu.Lock()
+	s.requests = append(s.requests, fmt.Sprintf("%s %s", r.Method, r.URL.RequestURI()))
+	body, ok := s.fixtures[key]
+	pages := s.pages[key]
+	lastModified, modified := s.modified[key]
+	status := s.statuses[key]
+	s.mu.Unlock()
+
+	if !modified {
+		lastModified = time.Now().UTC().Truncate(time.Hour)
+	}
+
+	w.Header().Set("X-Esi-Error-Limit-Remain", "100")
+	w.Header().Set("X-Esi-Error-Limit-Reset", "60")
+
+	if status != 0 {
+		writeError(w, status, http.StatusText(status))
+		return
+	}
+
+	if len(pages) > 0 {
+		number := 1
+		if p := r.URL.Query().Get("page"); p != "" {
+			number, _ = strconv.Atoi(p)
+		}
+
+		if number < 1 || number > len(pages) {
+			writeError(w, http.StatusNotFound, "Requested page does not exist!")
+			return
+		}
+
+		body, ok = pages[number-1], true
+	}
+
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("no fixture recorded for %s", r.URL.Path))
+		return
+	}
+
+	if requiresToken(key) {
+		auth := r.Header.Get(headers.Authorization)
+		if !strings.HasPrefix(auth, "Bearer ") {
+			writeError(w, http.StatusUnauthorized, "authorization not provided")
+			return
+		}
+
+		_, err := s.verifyToken(strings.TrimPrefix(auth, "Bearer "))
+		if err != nil {
+			writeError(w, http.StatusForbidden, "token is not valid for scope(s)")
+			return
+		}
+	}
+
+	etag := fmt.Sprintf(`"%x"`, sha256.Sum256(body))
+
+	w.Header().Set(headers.ETag, etag)
+	w.Header().Set(headers.Expires, time.Now().Add(cacheDuration).UTC().Format(http.TimeFormat))
+	w.Header().Set(headers.LastModified, lastModified.UTC().Format(http.TimeFormat))
+	w.Header().Set("X-Pages", "1")
+	if len(pages) > 0 {
+		w.Header().Set("X-Pages", strconv.Itoa(len(pages)))
+	}
+
+	if r.Header.Get(headers.IfNoneMatch) == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set(headers.ContentType, "application/json; charset=UTF-8")
+	_, _ = w.Write(body)
+
+}
+
+// requiresToken reports whether the path is one of a character's private endpoints or a structure
+func requiresToken(key string) bool {
+	parts := strings.Split(key, "/")
+	if len(parts) > 2 && parts[0] == "universe" && parts[1] == "structures" {
+		return true
+	}
+
+	return len(parts) > 2 && parts[0] == "characters" && parts[2] != "corporationhistory"
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+
+	w.Header().Set(headers.ContentType, "application/json; charset=UTF-8")
+	w.WriteHeader(status)
+
+	err := json.NewEncoder(w).Encode(map[string]string{"error": message})
+	if err != nil {
+		panic(errors.Wrap(err, "failed to write error response"))
+	}
+
+}
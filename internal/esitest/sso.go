@@ -0,0 +1,267 @@
+package esitest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/eveisesi/skillz"
+	"github.com/go-http-utils/headers"
+	"github.com/lestrrat-go/jwx/jwa"
+	"github.com/lestrrat-go/jwx/jwk"
+	"github.com/lestrrat-go/jwx/jwt"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+)
+
+const (
+	// ClientID is the client id that tokens are issued to
+	ClientID = "esitest-client-id"
+	// ClientSecret is accepted by the token endpoint but never checked
+	ClientSecret = "esitest-client-secret"
+
+	keyID          = "JWT-Signature-Key"
+	tokenExpiresIn = 20 * time.Minute
+)
+
+// grant is who a code or a refresh token was issued for
+type grant struct {
+	characterID uint64
+	scopes      []skillz.Scope
+}
+
+// OAuth2Config returns an oauth2 config for the fake SSO that redirects to the callback
+func (s *Server) OAuth2Config(callback string) *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     ClientID,
+		ClientSecret: ClientSecret,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  s.URL + "/v2/oauth/authorize",
+			TokenURL: s.URL + "/v2/oauth/token",
+		},
+		RedirectURL: callback,
+	}
+}
+
+// Code returns an authorization code that can be exchanged for a token of the character with the scopes
+func (s *Server) Code(characterID uint64, scopes ...skillz.Scope) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	code := randomString()
+	s.codes[code] = &grant{characterID: characterID, scopes: scopes}
+
+	return code
+}
+
+// Token returns a signed access token for the character with the scopes, as the SSO would issue it
+func (s *Server) Token(characterID uint64, scopes ...skillz.Scope) (string, error) {
+	return s.signToken(&grant{characterID: characterID, scopes: scopes}, time.Now().Add(tokenExpiresIn))
+}
+
+// TransferCharacter makes the SSO issue the character's tokens with the owner hash, the way the owner hash
+// changes when a character is transferred to another player. Tokens are issued with OwnerHash by default
+func (s *Server) TransferCharacter(characterID uint64, ownerHash string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.owners[characterID] = ownerHash
+}
+
+// RevokeRefreshToken makes the SSO reject the refresh token the way it does once a character's tokens
+// have been revoked
+func (s *Server) RevokeRefreshToken(refreshToken string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.refresh, refreshToken)
+}
+
+func (s *Server) generateKey() error {
+
+	raw, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return errors.Wrap(err, "failed to generate signing key")
+	}
+
+	key, err := jwk.New(raw)
+	if err != nil {
+		return errors.Wrap(err, "failed to build signing key")
+	}
+
+	public, err := jwk.PublicKeyOf(raw)
+	if err != nil {
+		return errors.Wrap(err, "failed to build public key")
+	}
+
+	for _, k := range []jwk.Key{key, public} {
+		_ = k.Set(jwk.KeyIDKey, keyID)
+		_ = k.Set(jwk.AlgorithmKey, jwa.RS256)
+	}
+
+	s.key = key
+	s.jwks = jwk.NewSet()
+	s.jwks.Add(public)
+
+	return nil
+
+}
+
+func (s *Server) signToken(g *grant, expires time.Time) (string, error) {
+
+	s.mu.Lock()
+	owner, ok := s.owners[g.characterID]
+	s.mu.Unlock()
+
+	if !ok {
+		owner = OwnerHash
+	}
+
+	scopes := make([]string, 0, len(g.scopes))
+	for _, scope := range g.scopes {
+		scopes = append(scopes, scope.String())
+	}
+
+	token := jwt.New()
+	claims := map[string]interface{}{
+		jwt.SubjectKey:    fmt.Sprintf("CHARACTER:EVE:%d", g.characterID),
+		jwt.IssuerKey:     s.BaseURI().Host,
+		jwt.IssuedAtKey:   time.Now(),
+		jwt.ExpirationKey: expires,
+		"azp":             ClientID,
+		"name":            CharacterName,
+		"owner":           owner,
+		"scp":             scopes,
+	}
+
+	for k, v := range claims {
+		err := token.Set(k, v)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to set %s claim", k)
+		}
+	}
+
+	signed, err := jwt.Sign(token, jwa.RS256, s.key)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to sign token")
+	}
+
+	return string(signed), nil
+
+}
+
+func (s *Server) verifyToken(t string) (jwt.Token, error) {
+
+	token, err := jwt.ParseString(t, jwt.WithKeySet(s.jwks))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse token")
+	}
+
+	return token, jwt.Validate(token)
+
+}
+
+func (s *Server) handleJWKS(w http.ResponseWriter, r *http.Request) {
+
+	w.Header().Set(headers.ContentType, "application/json; charset=UTF-8")
+
+	err := json.NewEncoder(w).Encode(s.jwks)
+	if err != nil {
+		panic(errors.Wrap(err, "failed to write jwks"))
+	}
+
+}
+
+// handleAuthorize skips the login and immediately redirects back with a code for
+// the fixture character with the requested scopes
+func (s *Server) handleAuthorize(w http.ResponseWriter, r *http.Request) {
+
+	query := r.URL.Query()
+
+	redirect, err := url.Parse(query.Get("redirect_uri"))
+	if err != nil || query.Get("client_id") != ClientID {
+		writeError(w, http.StatusBadRequest, "invalid_request")
+		return
+	}
+
+	scopes := make([]skillz.Scope, 0)
+	for _, scope := range strings.Fields(query.Get("scope")) {
+		scopes = append(scopes, skillz.Scope(scope))
+	}
+
+	params := redirect.Query()
+	params.Set("code", s.Code(CharacterID, scopes...))
+	params.Set("state", query.Get("state"))
+	redirect.RawQuery = params.Encode()
+
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+
+}
+
+func (s *Server) handleToken(w http.ResponseWriter, r *http.Request) {
+
+	err := r.ParseForm()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request")
+		return
+	}
+
+	var lookup map[string]*grant
+	var key string
+	switch r.PostForm.Get("grant_type") {
+	case "authorization_code":
+		lookup, key = s.codes, r.PostForm.Get("code")
+	case "refresh_token":
+		lookup, key = s.refresh, r.PostForm.Get("refresh_token")
+	default:
+		writeError(w, http.StatusBadRequest, "unsupported_grant_type")
+		return
+	}
+
+	s.mu.Lock()
+	g, ok := lookup[key]
+	// Codes and refresh tokens can only be used once
+	delete(lookup, key)
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid_grant")
+		return
+	}
+
+	accessToken, err := s.signToken(g, time.Now().Add(tokenExpiresIn))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	refreshToken := randomString()
+
+	s.mu.Lock()
+	s.refresh[refreshToken] = g
+	s.mu.Unlock()
+
+	w.Header().Set(headers.ContentType, "application/json; charset=UTF-8")
+	err = json.NewEncoder(w).Encode(map[string]interface{}{
+		"access_token":  accessToken,
+		"expires_in":    int(tokenExpiresIn.Seconds()),
+		"token_type":    "Bearer",
+		"refresh_token": refreshToken,
+	})
+	if err != nil {
+		panic(errors.Wrap(err, "failed to write token response"))
+	}
+
+}
+
+func randomString() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
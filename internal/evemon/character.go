@@ -0,0 +1,127 @@
+package evemon
+
+import (
+	"bytes"
+	"encoding/xml"
+	"sort"
+
+	"github.com/eveisesi/skillz"
+	"github.com/pkg/errors"
+)
+
+const birthdayFormat = "2006-01-02 15:04:05"
+
+// character is the character format that EVEMon exports and that both EVEMon and pyfa are able to import
+type character struct {
+	XMLName         xml.Name             `xml:"outputCharacter"`
+	CharacterID     uint64               `xml:"characterID"`
+	Name            string               `xml:"name"`
+	Gender          string               `xml:"gender,omitempty"`
+	CorporationID   uint                 `xml:"corporationID,omitempty"`
+	CorporationName string               `xml:"corporationName,omitempty"`
+	AllianceID      uint                 `xml:"allianceID,omitempty"`
+	AllianceName    string               `xml:"allianceName,omitempty"`
+	Birthday        string               `xml:"birthday,omitempty"`
+	Attributes      *characterAttributes `xml:"attributes"`
+	Implants        []*characterImplant  `xml:"implants>implant"`
+	Skills          []*characterSkill    `xml:"skills>skill"`
+}
+
+type characterAttributes struct {
+	Intelligence uint `xml:"intelligence"`
+	Memory       uint `xml:"memory"`
+	Charisma     uint `xml:"charisma"`
+	Perception   uint `xml:"perception"`
+	Willpower    uint `xml:"willpower"`
+}
+
+type characterImplant struct {
+	TypeID uint   `xml:"typeID,attr"`
+	Name   string `xml:"name,attr"`
+	Slot   uint   `xml:"slot,attr"`
+}
+
+type characterSkill struct {
+	TypeID      uint   `xml:"typeID,attr"`
+	Name        string `xml:"name,attr"`
+	Level       uint   `xml:"level,attr"`
+	Skillpoints uint   `xml:"skillpoints,attr"`
+	OwnsBook    bool   `xml:"ownsBook,attr"`
+	IsKnown     bool   `xml:"isKnown,attr"`
+}
+
+// ExportCharacter writes the user's character, attributes, implants and skills in the EVEMon character format.
+// The user must be loaded with their character, attributes, implants and skill list
+func ExportCharacter(user *skillz.User) ([]byte, error) {
+
+	if user.Character == nil {
+		return nil, errors.New("user must be loaded with their character to be exported")
+	}
+
+	c := &character{
+		CharacterID: user.CharacterID,
+		Name:        user.Character.Name,
+		Gender:      user.Character.Gender,
+		Birthday:    user.Character.Birthday.UTC().Format(birthdayFormat),
+		Implants:    make([]*characterImplant, 0, len(user.Implants)),
+		Skills:      make([]*characterSkill, 0, len(user.Skills)),
+	}
+
+	if corporation := user.Character.Corporation; corporation != nil {
+		c.CorporationID, c.CorporationName = corporation.ID, corporation.Name
+		if alliance := corporation.Alliance; alliance != nil {
+			c.AllianceID, c.AllianceName = alliance.ID, alliance.Name
+		}
+	}
+
+	if user.Attributes != nil {
+		c.Attributes = &characterAttributes{
+			Intelligence: user.Attributes.Intelligence,
+			Memory:       user.Attributes.Memory,
+			Charisma:     user.Attributes.Charisma,
+			Perception:   user.Attributes.Perception,
+			Willpower:    user.Attributes.Willpower,
+		}
+	}
+
+	for _, implant := range user.Implants {
+		i := &characterImplant{TypeID: implant.ImplantID, Slot: implant.Slot}
+		if implant.Type != nil {
+			i.Name = implant.Type.Name
+		}
+
+		c.Implants = append(c.Implants, i)
+	}
+
+	for _, skill := range user.Skills {
+		s := &characterSkill{
+			TypeID:      skill.SkillID,
+			Level:       skill.TrainedSkillLevel,
+			Skillpoints: skill.SkillpointsInSkill,
+			OwnsBook:    true,
+			IsKnown:     true,
+		}
+		if skill.Info != nil {
+			s.Name = skill.Info.Name
+		}
+
+		c.Skills = append(c.Skills, s)
+	}
+
+	sort.Slice(c.Skills, func(i, j int) bool {
+		return c.Skills[i].Name < c.Skills[j].Name
+	})
+
+	var buf = new(bytes.Buffer)
+	buf.WriteString(xml.Header)
+
+	enc := xml.NewEncoder(buf)
+	enc.Indent("", "  ")
+	err := enc.Encode(c)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode character")
+	}
+
+	return buf.Bytes(), nil
+
+}
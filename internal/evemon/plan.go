@@ -0,0 +1,72 @@
+package evemon
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/xml"
+	"io"
+	"strings"
+
+	"github.com/eveisesi/skillz"
+	"github.com/pkg/errors"
+)
+
+var ErrInvalidPlan = errors.New("plan is invalid")
+
+// maxPlanSize limits the size of a decompressed plan
+const maxPlanSize = 5 << 20
+
+// plan is the structure of an EVEMon skill plan. EVEMon saves plans with the .emp
+// extension as gzip compressed xml and exports them as uncompressed xml
+type plan struct {
+	XMLName xml.Name     `xml:"plan"`
+	Name    string       `xml:"name,attr"`
+	Entries []*planEntry `xml:"entry"`
+}
+
+type planEntry struct {
+	SkillID uint   `xml:"skillID,attr"`
+	Skill   string `xml:"skill,attr"`
+	Level   uint   `xml:"level,attr"`
+}
+
+// ParsePlan parses an EVEMon skill plan, either gzip compressed or not, and
+// returns the name of the plan along with its entries in the order they appear
+func ParsePlan(data []byte) (string, []*skillz.SkillPlanEntry, error) {
+
+	var r io.Reader = bytes.NewReader(data)
+	if len(data) > 2 && data[0] == 0x1f && data[1] == 0x8b {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return "", nil, errors.Wrap(ErrInvalidPlan, err.Error())
+		}
+		defer gz.Close()
+
+		r = gz
+	}
+
+	var p = new(plan)
+	err := xml.NewDecoder(io.LimitReader(r, maxPlanSize)).Decode(p)
+	if err != nil {
+		return "", nil, errors.Wrap(ErrInvalidPlan, err.Error())
+	}
+
+	if len(p.Entries) == 0 {
+		return "", nil, errors.Wrap(ErrInvalidPlan, "plan does not have any entries")
+	}
+
+	entries := make([]*skillz.SkillPlanEntry, 0, len(p.Entries))
+	for i, entry := range p.Entries {
+		if entry.SkillID == 0 {
+			return "", nil, errors.Wrapf(ErrInvalidPlan, "entry %d is missing the skillID", i+1)
+		}
+
+		entries = append(entries, &skillz.SkillPlanEntry{
+			SkillID: entry.SkillID,
+			Level:   entry.Level,
+		})
+	}
+
+	return strings.TrimSpace(p.Name), entries, nil
+
+}
@@ -0,0 +1,119 @@
+package fitting
+
+import (
+	"bufio"
+	"strconv"
+	"strings"
+
+	"github.com/eveisesi/skillz"
+	"github.com/pkg/errors"
+)
+
+// ParseEFT parses a fitting in the EFT format, i.e.
+//
+//	[Rifter, My Rifter]
+//	Damage Control I
+//	200mm AutoCannon I, EMP S
+//	[Empty High slot]
+//
+//	Warrior I x5
+//
+// Items are identified by their name and must be resolved against the universe before they can be checked
+func ParseEFT(text string) (*skillz.Fitting, error) {
+
+	var fitting *skillz.Fitting
+
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if fitting == nil {
+			ship, name, err := parseEFTHeader(line)
+			if err != nil {
+				return nil, err
+			}
+
+			fitting = &skillz.Fitting{
+				Name:     name,
+				ShipName: ship,
+				Items:    make([]*skillz.FittingItem, 0),
+			}
+			continue
+		}
+
+		// Empty slots are rendered as [Empty Low slot], [Empty Rig slot], etc
+		if strings.HasPrefix(line, "[") {
+			continue
+		}
+
+		fitting.Items = append(fitting.Items, parseEFTLine(line)...)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(ErrInvalidFitting, err.Error())
+	}
+
+	if fitting == nil {
+		return nil, errors.Wrap(ErrInvalidFitting, "fitting is empty")
+	}
+
+	return fitting, nil
+
+}
+
+// parseEFTHeader parses the [Ship, Name] header of an EFT fitting
+func parseEFTHeader(line string) (string, string, error) {
+
+	if !strings.HasPrefix(line, "[") || !strings.HasSuffix(line, "]") {
+		return "", "", errors.Wrap(ErrInvalidFitting, "fitting must start with a [Ship, Name] header")
+	}
+
+	parts := strings.SplitN(strings.Trim(line, "[]"), ",", 2)
+
+	ship := strings.TrimSpace(parts[0])
+	if ship == "" {
+		return "", "", errors.Wrap(ErrInvalidFitting, "fitting header is missing the ship")
+	}
+
+	var name string
+	if len(parts) == 2 {
+		name = strings.TrimSpace(parts[1])
+	}
+
+	return ship, name, nil
+
+}
+
+// parseEFTLine parses a module, optionally loaded with a charge, or a stack of items
+// from the drone bay or cargo hold of an EFT fitting
+func parseEFTLine(line string) []*skillz.FittingItem {
+
+	line = strings.TrimSpace(strings.TrimSuffix(line, "/OFFLINE"))
+
+	var quantity uint = 1
+	if i := strings.LastIndex(line, " x"); i > 0 {
+		if n, err := strconv.ParseUint(line[i+2:], 10, 32); err == nil {
+			quantity = uint(n)
+			line = strings.TrimSpace(line[:i])
+		}
+	}
+
+	items := make([]*skillz.FittingItem, 0, 2)
+	for _, name := range strings.SplitN(line, ",", 2) {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		items = append(items, &skillz.FittingItem{
+			Name:     name,
+			Quantity: quantity,
+		})
+	}
+
+	return items
+
+}
@@ -0,0 +1,74 @@
+package fitting
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/eveisesi/skillz"
+	"github.com/pkg/errors"
+)
+
+// esiFitting is the structure of a fitting returned by the ESI character fittings endpoint
+type esiFitting struct {
+	FittingID   uint              `json:"fitting_id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	ShipTypeID  uint              `json:"ship_type_id"`
+	Items       []*esiFittingItem `json:"items"`
+}
+
+type esiFittingItem struct {
+	TypeID   uint   `json:"type_id"`
+	Flag     string `json:"flag"`
+	Quantity uint   `json:"quantity"`
+}
+
+// ParseESI parses fittings in the format returned by the ESI character fittings endpoint. Both
+// a list of fittings, as returned by the endpoint, and a single fitting are accepted
+func ParseESI(data []byte) ([]*skillz.Fitting, error) {
+
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, errors.Wrap(ErrInvalidFitting, "fitting is empty")
+	}
+
+	var fittings = make([]*esiFitting, 0)
+	if data[0] == '{' {
+		data = append(append([]byte{'['}, data...), ']')
+	}
+
+	err := json.Unmarshal(data, &fittings)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidFitting, err.Error())
+	}
+
+	results := make([]*skillz.Fitting, 0, len(fittings))
+	for _, f := range fittings {
+		if f.ShipTypeID == 0 {
+			return nil, errors.Wrapf(ErrInvalidFitting, "fitting %q is missing the ship_type_id", f.Name)
+		}
+
+		fitting := &skillz.Fitting{
+			Name:       f.Name,
+			ShipTypeID: f.ShipTypeID,
+			Items:      make([]*skillz.FittingItem, 0, len(f.Items)),
+		}
+
+		for _, item := range f.Items {
+			if item.TypeID == 0 {
+				continue
+			}
+
+			fitting.Items = append(fitting.Items, &skillz.FittingItem{
+				TypeID:   item.TypeID,
+				Flag:     item.Flag,
+				Quantity: item.Quantity,
+			})
+		}
+
+		results = append(results, fitting)
+	}
+
+	return results, nil
+
+}
@@ -0,0 +1,284 @@
+package fitting
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/eveisesi/skillz"
+	"github.com/eveisesi/skillz/internal/skill"
+	"github.com/eveisesi/skillz/internal/universe"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+var ErrInvalidFitting = errors.New("fitting is invalid")
+
+type API interface {
+	Check(ctx context.Context, characterID uint64, fitting *skillz.Fitting) (*skillz.FittingReport, error)
+	CheckEFT(ctx context.Context, characterID uint64, text string) (*skillz.FittingReport, error)
+	CheckESI(ctx context.Context, characterID uint64, data []byte) ([]*skillz.FittingReport, error)
+	Requirements(ctx context.Context, fitting *skillz.Fitting) (*skillz.Type, []*skillz.SkillRequirement, error)
+}
+
+type Service struct {
+	logger   *logrus.Logger
+	universe universe.API
+	skills   skill.API
+}
+
+var _ API = (*Service)(nil)
+
+func New(logger *logrus.Logger, universe universe.API, skills skill.API) *Service {
+	return &Service{
+		logger:   logger,
+		universe: universe,
+		skills:   skills,
+	}
+}
+
+// CheckEFT parses a fitting in the EFT format and checks it against the character's skills
+func (s *Service) CheckEFT(ctx context.Context, characterID uint64, text string) (*skillz.FittingReport, error) {
+
+	fitting, err := ParseEFT(text)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Check(ctx, characterID, fitting)
+
+}
+
+// CheckESI parses fittings in the ESI fittings format and checks each of them against the character's skills
+func (s *Service) CheckESI(ctx context.Context, characterID uint64, data []byte) ([]*skillz.FittingReport, error) {
+
+	fittings, err := ParseESI(data)
+	if err != nil {
+		return nil, err
+	}
+
+	reports := make([]*skillz.FittingReport, 0, len(fittings))
+	for _, fitting := range fittings {
+		report, err := s.Check(ctx, characterID, fitting)
+		if err != nil {
+			return nil, err
+		}
+
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+
+}
+
+// Check resolves the hull and items of the fitting and checks their required skills against
+// the character's trained skills. The report includes a plan of every skill level, including
+// nested prerequisites, the character needs to train to be able to use the entire fitting
+func (s *Service) Check(ctx context.Context, characterID uint64, fitting *skillz.Fitting) (*skillz.FittingReport, error) {
+
+	ship := &skillz.FittingItemReport{
+		TypeID:   fitting.ShipTypeID,
+		Name:     fitting.ShipName,
+		Quantity: 1,
+	}
+
+	items := make([]*skillz.FittingItemReport, 0, len(fitting.Items))
+	itemsByKey := make(map[string]*skillz.FittingItemReport, len(fitting.Items))
+	for _, item := range fitting.Items {
+		key := itemKey(item.TypeID, item.Name)
+		if report, ok := itemsByKey[key]; ok {
+			report.Quantity += item.Quantity
+			continue
+		}
+
+		report := &skillz.FittingItemReport{
+			TypeID:   item.TypeID,
+			Name:     item.Name,
+			Quantity: item.Quantity,
+		}
+
+		items = append(items, report)
+		itemsByKey[key] = report
+	}
+
+	err := s.resolveItems(ctx, append([]*skillz.FittingItemReport{ship}, items...))
+	if err != nil {
+		return nil, err
+	}
+
+	skills, err := s.skills.Skillz(ctx, characterID)
+	if err != nil {
+		return nil, err
+	}
+
+	trained := make(map[uint]uint, len(skills))
+	for _, skill := range skills {
+		trained[skill.SkillID] = skill.TrainedSkillLevel
+	}
+
+	report := &skillz.FittingReport{
+		Name:   fitting.Name,
+		Usable: true,
+		Ship:   ship,
+		Items:  items,
+	}
+
+	// missing is every requirement of the fitting that the character has not trained,
+	// keyed by skill so that each skill is only planned once at the highest level required
+	missing := make([]*skillz.SkillRequirement, 0)
+	missingBySkill := make(map[uint]*skillz.SkillRequirement)
+
+	for _, item := range append([]*skillz.FittingItemReport{ship}, items...) {
+		item.Missing = make([]*skillz.SkillRequirement, 0)
+		if !item.Resolved {
+			report.Usable = false
+			continue
+		}
+
+		for _, requirement := range skill.RequiredSkills(item.Type) {
+			if trained[requirement.SkillID] >= requirement.Level {
+				continue
+			}
+
+			item.Missing = append(item.Missing, requirement)
+
+			if m, ok := missingBySkill[requirement.SkillID]; ok {
+				if requirement.Level > m.Level {
+					m.Level = requirement.Level
+				}
+				continue
+			}
+
+			m := &skillz.SkillRequirement{SkillID: requirement.SkillID, Level: requirement.Level}
+			missing = append(missing, m)
+			missingBySkill[requirement.SkillID] = m
+		}
+
+		item.Usable = len(item.Missing) == 0
+		if !item.Usable {
+			report.Usable = false
+		}
+	}
+
+	report.Plan, err = s.skills.RequirementsPlan(ctx, characterID, missing)
+	if err != nil {
+		return nil, err
+	}
+
+	// The plan hydrates the missing requirements with their skill types, which
+	// are then shared with the requirements reported against each item
+	for _, item := range append([]*skillz.FittingItemReport{ship}, items...) {
+		for _, requirement := range item.Missing {
+			requirement.Type = missingBySkill[requirement.SkillID].Type
+		}
+	}
+
+	report.Plan.Name = strings.TrimSpace(fmt.Sprintf("%s %s", ship.Name, fitting.Name))
+
+	return report, nil
+
+}
+
+// Requirements resolves the hull and items of the fitting and returns the hull along with the skill levels that
+// the hull and items directly require. Each skill is only returned once at the highest level that is required
+func (s *Service) Requirements(ctx context.Context, fitting *skillz.Fitting) (*skillz.Type, []*skillz.SkillRequirement, error) {
+
+	ship := &skillz.FittingItemReport{
+		TypeID: fitting.ShipTypeID,
+		Name:   fitting.ShipName,
+	}
+
+	items := []*skillz.FittingItemReport{ship}
+	for _, item := range fitting.Items {
+		items = append(items, &skillz.FittingItemReport{
+			TypeID: item.TypeID,
+			Name:   item.Name,
+		})
+	}
+
+	err := s.resolveItems(ctx, items)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	requirements := make([]*skillz.SkillRequirement, 0)
+	requirementsBySkill := make(map[uint]*skillz.SkillRequirement)
+	for _, item := range items {
+		if !item.Resolved {
+			return nil, nil, errors.Wrapf(ErrInvalidFitting, "%q could not be found", item.Name)
+		}
+
+		for _, requirement := range skill.RequiredSkills(item.Type) {
+			if r, ok := requirementsBySkill[requirement.SkillID]; ok {
+				if requirement.Level > r.Level {
+					r.Level = requirement.Level
+				}
+				continue
+			}
+
+			r := &skillz.SkillRequirement{SkillID: requirement.SkillID, Level: requirement.Level}
+			requirements = append(requirements, r)
+			requirementsBySkill[requirement.SkillID] = r
+		}
+	}
+
+	return ship.Type, requirements, nil
+
+}
+
+// resolveItems hydrates the items with their types. Items with a TypeID are looked up by ID and
+// the remainder are looked up by name. Items that cannot be found are left unresolved
+func (s *Service) resolveItems(ctx context.Context, items []*skillz.FittingItemReport) error {
+
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		if item.TypeID != 0 {
+			t, err := s.universe.Type(ctx, item.TypeID)
+			if err != nil {
+				return errors.Wrapf(err, "failed to fetch type %d", item.TypeID)
+			}
+
+			if t != nil && t.ID != 0 {
+				item.Type, item.Name, item.Resolved = t, t.Name, true
+			}
+			continue
+		}
+
+		names = append(names, item.Name)
+	}
+
+	if len(names) == 0 {
+		return nil
+	}
+
+	types, err := s.universe.TypesByName(ctx, names)
+	if err != nil {
+		return err
+	}
+
+	typesByName := make(map[string]*skillz.Type, len(types))
+	for _, t := range types {
+		typesByName[strings.ToLower(t.Name)] = t
+	}
+
+	for _, item := range items {
+		if item.Resolved {
+			continue
+		}
+
+		if t, ok := typesByName[strings.ToLower(item.Name)]; ok {
+			item.Type, item.TypeID, item.Name, item.Resolved = t, t.ID, t.Name, true
+		}
+	}
+
+	return nil
+
+}
+
+func itemKey(typeID uint, name string) string {
+	if typeID != 0 {
+		return fmt.Sprintf("%d", typeID)
+	}
+
+	return strings.ToLower(name)
+}
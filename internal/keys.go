@@ -3,6 +3,13 @@ package internal
 const (
 	UpdateQueue   = "skillz::queue::update"
 	UpdatingQueue = "skillz::queue::updating"
+	UserLease     = "skillz::lease::user::%s"
+	FailedQueue   = "skillz::queue::failed"
+	DeadQueue     = "skillz::queue::dead"
+)
+
+const (
+	ESIErrorBudget = "skillz::esi::error_budget"
 )
 
 const (
@@ -0,0 +1,76 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/eveisesi/skillz"
+	"github.com/pkg/errors"
+	"github.com/volatiletech/null"
+)
+
+type allianceRepository struct {
+	db *DB
+}
+
+func NewAllianceRepository(db *DB) skillz.AllianceRepository {
+	return &allianceRepository{
+		db: db,
+	}
+}
+
+func (r *allianceRepository) Alliance(ctx context.Context, allianceID uint) (*skillz.Alliance, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	alliance, ok := r.db.alliances[allianceID]
+	if !ok {
+		return new(skillz.Alliance), errors.Wrapf(sql.ErrNoRows, prefixFormat, allianceRepositoryIdentifier, "Alliance")
+	}
+
+	return row(alliance).(*skillz.Alliance), nil
+
+}
+
+func (r *allianceRepository) CreateAlliance(ctx context.Context, alliance *skillz.Alliance) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	now := time.Now()
+	alliance.CreatedAt = now
+	alliance.UpdatedAt = now
+
+	if _, ok := r.db.alliances[alliance.ID]; ok {
+		return errors.Wrapf(ErrDuplicateKey, prefixFormat, allianceRepositoryIdentifier, "CreateAlliance")
+	}
+
+	r.db.alliances[alliance.ID] = allianceRow(alliance)
+
+	return nil
+
+}
+
+func (r *allianceRepository) UpdateAlliance(ctx context.Context, alliance *skillz.Alliance) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	alliance.UpdatedAt = time.Now()
+
+	if existing, ok := r.db.alliances[alliance.ID]; ok {
+		r.db.alliances[alliance.ID] = replace(existing, allianceRow(alliance)).(*skillz.Alliance)
+	}
+
+	return nil
+
+}
+
+// allianceRow returns the row for the alliance. The faction of an alliance is not stored
+func allianceRow(alliance *skillz.Alliance) *skillz.Alliance {
+	a := row(alliance).(*skillz.Alliance)
+	a.FactionID = null.Uint{}
+	return a
+}
@@ -0,0 +1,117 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/eveisesi/skillz"
+	"github.com/pkg/errors"
+)
+
+type characterRepository struct {
+	db *DB
+}
+
+func NewCharacterRepository(db *DB) skillz.CharacterRepository {
+	return &characterRepository{
+		db: db,
+	}
+}
+
+func (r *characterRepository) Character(ctx context.Context, characterID uint64) (*skillz.Character, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	character, ok := r.db.characters[characterID]
+	if !ok {
+		return new(skillz.Character), errors.Wrapf(sql.ErrNoRows, prefixFormat, characterRepositoryIdentifier, "Character")
+	}
+
+	return row(character).(*skillz.Character), nil
+
+}
+
+func (r *characterRepository) CreateCharacter(ctx context.Context, character *skillz.Character) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	now := time.Now()
+	character.CreatedAt = now
+	character.UpdatedAt = now
+
+	if _, ok := r.db.characters[character.ID]; ok {
+		return errors.Wrapf(ErrDuplicateKey, prefixFormat, characterRepositoryIdentifier, "CreateCharacter")
+	}
+
+	r.db.characters[character.ID] = row(character).(*skillz.Character)
+
+	return nil
+
+}
+
+func (r *characterRepository) UpdateCharacter(ctx context.Context, character *skillz.Character) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	character.UpdatedAt = time.Now()
+
+	if existing, ok := r.db.characters[character.ID]; ok {
+		r.db.characters[character.ID] = replace(existing, character).(*skillz.Character)
+	}
+
+	return nil
+
+}
+
+func (r *characterRepository) CharacterCorporationHistory(ctx context.Context, characterID uint64) ([]*skillz.CharacterCorporationHistory, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var records = make([]*skillz.CharacterCorporationHistory, 0)
+	for _, record := range r.db.characterHistory {
+		if record.CharacterID == characterID {
+			records = append(records, row(record).(*skillz.CharacterCorporationHistory))
+		}
+	}
+
+	return records, nil
+
+}
+
+func (r *characterRepository) CreateCharacterCorporationHistory(ctx context.Context, records []*skillz.CharacterCorporationHistory) ([]*skillz.CharacterCorporationHistory, error) {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if len(records) == 0 {
+		return nil, errors.Wrapf(errNoValues, prefixFormat, characterRepositoryIdentifier, "CreateCharacterCorporationHistory")
+	}
+
+	index := make(map[string]int, len(r.db.characterHistory))
+	for i, record := range r.db.characterHistory {
+		index[key(record.CharacterID, record.RecordID)] = i
+	}
+
+	now := time.Now()
+	for _, record := range records {
+		record.CreatedAt = now
+		record.UpdatedAt = now
+
+		k := key(record.CharacterID, record.RecordID)
+		if i, ok := index[k]; ok {
+			r.db.characterHistory[i] = upsert(r.db.characterHistory[i], record).(*skillz.CharacterCorporationHistory)
+			continue
+		}
+
+		index[k] = len(r.db.characterHistory)
+		r.db.characterHistory = append(r.db.characterHistory, row(record).(*skillz.CharacterCorporationHistory))
+	}
+
+	return records, nil
+
+}
@@ -0,0 +1,234 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/eveisesi/skillz"
+	"github.com/pkg/errors"
+)
+
+type cloneRepository struct {
+	db *DB
+}
+
+func NewCloneRepository(db *DB) skillz.CloneRepository {
+	return &cloneRepository{
+		db: db,
+	}
+}
+
+func (r *cloneRepository) CharacterImplants(ctx context.Context, characterID uint64) ([]*skillz.CharacterImplant, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var implants = make([]*skillz.CharacterImplant, 0, 10)
+	for _, implant := range r.db.implants {
+		if implant.CharacterID == characterID && implant.Slot <= 5 {
+			implants = append(implants, row(implant).(*skillz.CharacterImplant))
+		}
+	}
+
+	sort.SliceStable(implants, func(i, j int) bool {
+		return implants[i].Slot < implants[j].Slot
+	})
+
+	return implants, nil
+
+}
+
+func (r *cloneRepository) CreateCharacterImplants(ctx context.Context, implants []*skillz.CharacterImplant) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if len(implants) == 0 {
+		return errors.Wrapf(errNoValues, prefixFormat, cloneRepositoryIdentifier, "CreateCharacterImplants")
+	}
+
+	keys := make(map[string]bool, len(r.db.implants)+len(implants))
+	for _, implant := range r.db.implants {
+		keys[key(implant.CharacterID, implant.ImplantID)] = true
+	}
+
+	now := time.Now()
+	rows := make([]*skillz.CharacterImplant, 0, len(implants))
+	for _, implant := range implants {
+		implant.CreatedAt = now
+
+		k := key(implant.CharacterID, implant.ImplantID)
+		if keys[k] {
+			return errors.Wrapf(ErrDuplicateKey, prefixFormat, cloneRepositoryIdentifier, "CreateCharacterImplants")
+		}
+		keys[k] = true
+
+		rows = append(rows, row(implant).(*skillz.CharacterImplant))
+	}
+
+	r.db.implants = append(r.db.implants, rows...)
+
+	return nil
+
+}
+
+func (r *cloneRepository) DeleteCharacterImplants(ctx context.Context, characterID uint64) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	r.db.deleteCharacterImplants(characterID)
+
+	return nil
+
+}
+
+func (r *cloneRepository) CharacterJumpClones(ctx context.Context, characterID uint64) ([]*skillz.CharacterJumpClone, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var clones = make([]*skillz.CharacterJumpClone, 0)
+	for _, clone := range r.db.jumpClones {
+		if clone.CharacterID == characterID {
+			clones = append(clones, row(clone).(*skillz.CharacterJumpClone))
+		}
+	}
+
+	sort.SliceStable(clones, func(i, j int) bool {
+		return clones[i].JumpCloneID < clones[j].JumpCloneID
+	})
+
+	return clones, nil
+
+}
+
+func (r *cloneRepository) CharacterJumpCloneImplants(ctx context.Context, characterID uint64) ([]*skillz.CharacterJumpCloneImplant, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var implants = make([]*skillz.CharacterJumpCloneImplant, 0)
+	for _, implant := range r.db.jumpCloneImplants {
+		if implant.CharacterID == characterID && implant.Slot <= 5 {
+			implants = append(implants, row(implant).(*skillz.CharacterJumpCloneImplant))
+		}
+	}
+
+	sort.SliceStable(implants, func(i, j int) bool {
+		return implants[i].Slot < implants[j].Slot
+	})
+
+	return implants, nil
+
+}
+
+func (r *cloneRepository) CreateCharacterJumpClones(ctx context.Context, clones []*skillz.CharacterJumpClone) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if len(clones) == 0 {
+		return errors.Wrapf(errNoValues, prefixFormat, cloneRepositoryIdentifier, "CreateCharacterJumpClones")
+	}
+
+	keys := make(map[string]bool, len(r.db.jumpClones)+len(clones))
+	for _, clone := range r.db.jumpClones {
+		keys[key(clone.CharacterID, clone.JumpCloneID)] = true
+	}
+
+	now := time.Now()
+	rows := make([]*skillz.CharacterJumpClone, 0, len(clones))
+	for _, clone := range clones {
+		clone.CreatedAt = now
+
+		k := key(clone.CharacterID, clone.JumpCloneID)
+		if keys[k] {
+			return errors.Wrapf(ErrDuplicateKey, prefixFormat, cloneRepositoryIdentifier, "CreateCharacterJumpClones")
+		}
+		keys[k] = true
+
+		rows = append(rows, row(clone).(*skillz.CharacterJumpClone))
+	}
+
+	r.db.jumpClones = append(r.db.jumpClones, rows...)
+
+	return nil
+
+}
+
+func (r *cloneRepository) CreateCharacterJumpCloneImplants(ctx context.Context, implants []*skillz.CharacterJumpCloneImplant) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if len(implants) == 0 {
+		return errors.Wrapf(errNoValues, prefixFormat, cloneRepositoryIdentifier, "CreateCharacterJumpCloneImplants")
+	}
+
+	keys := make(map[string]bool, len(r.db.jumpCloneImplants)+len(implants))
+	for _, implant := range r.db.jumpCloneImplants {
+		keys[key(implant.CharacterID, implant.JumpCloneID, implant.ImplantID)] = true
+	}
+
+	now := time.Now()
+	rows := make([]*skillz.CharacterJumpCloneImplant, 0, len(implants))
+	for _, implant := range implants {
+		implant.CreatedAt = now
+
+		k := key(implant.CharacterID, implant.JumpCloneID, implant.ImplantID)
+		if keys[k] {
+			return errors.Wrapf(ErrDuplicateKey, prefixFormat, cloneRepositoryIdentifier, "CreateCharacterJumpCloneImplants")
+		}
+		keys[k] = true
+
+		rows = append(rows, row(implant).(*skillz.CharacterJumpCloneImplant))
+	}
+
+	r.db.jumpCloneImplants = append(r.db.jumpCloneImplants, rows...)
+
+	return nil
+
+}
+
+// DeleteCharacterJumpClones deletes the character's jump clones along with their implants,
+// as the foreign key does in MySQL
+func (r *cloneRepository) DeleteCharacterJumpClones(ctx context.Context, characterID uint64) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	r.db.deleteCharacterJumpClones(characterID)
+
+	return nil
+
+}
+
+func (db *DB) deleteCharacterImplants(characterID uint64) {
+	implants := db.implants[:0]
+	for _, implant := range db.implants {
+		if implant.CharacterID != characterID {
+			implants = append(implants, implant)
+		}
+	}
+	db.implants = implants
+}
+
+func (db *DB) deleteCharacterJumpClones(characterID uint64) {
+	clones := db.jumpClones[:0]
+	for _, clone := range db.jumpClones {
+		if clone.CharacterID != characterID {
+			clones = append(clones, clone)
+		}
+	}
+	db.jumpClones = clones
+
+	implants := db.jumpCloneImplants[:0]
+	for _, implant := range db.jumpCloneImplants {
+		if implant.CharacterID != characterID {
+			implants = append(implants, implant)
+		}
+	}
+	db.jumpCloneImplants = implants
+}
@@ -0,0 +1,30 @@
+package memory
+
+const (
+	columnCharacterID string = "character_id"
+	columnCreatedAt   string = "created_at"
+	columnUpdatedAt   string = "updated_at"
+)
+
+const (
+	prefixFormat string = "[%s.%s]"
+)
+
+const (
+	allianceRepositoryIdentifier    string = "AllianceRepository"
+	characterRepositoryIdentifier   string = "CharacterRepository"
+	contactRepositoryIdentifier     string = "ContactRepository"
+	cloneRepositoryIdentifier       string = "CloneRepository"
+	corporationRepositoryIdentifier string = "CorporationRepository"
+	etagRepositoryIdentifier        string = "ETagRepository"
+	skillsRepositoryIdentifier      string = "SkillsRepository"
+	universeRepositoryIdentifier    string = "UniverseRepository"
+	userRepositoryIdentifier        string = "UserRepository"
+)
+
+// The tables that have an auto increment primary key
+const (
+	tableCharacterSkillSnapshots string = "character_skill_snapshots"
+	tableSkillPlans              string = "skill_plans"
+	tableUserShareLinkViews      string = "user_share_link_views"
+)
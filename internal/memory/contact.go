@@ -0,0 +1,93 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/eveisesi/skillz"
+	"github.com/pkg/errors"
+)
+
+type contactRepository struct {
+	db *DB
+}
+
+func NewContactRepository(db *DB) skillz.ContactRepository {
+	return &contactRepository{
+		db: db,
+	}
+}
+
+func (r *contactRepository) CharacterContacts(ctx context.Context, characterID uint64) ([]*skillz.CharacterContact, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var contacts = make([]*skillz.CharacterContact, 0)
+	for _, contact := range r.db.contacts {
+		if contact.CharacterID == characterID {
+			contacts = append(contacts, row(contact).(*skillz.CharacterContact))
+		}
+	}
+
+	return contacts, nil
+
+}
+
+func (r *contactRepository) CreateCharacterContacts(ctx context.Context, contacts []*skillz.CharacterContact) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if len(contacts) == 0 {
+		return errors.Wrapf(errNoValues, prefixFormat, contactRepositoryIdentifier, "CreateCharacterContacts")
+	}
+
+	keys := make(map[string]bool, len(r.db.contacts)+len(contacts))
+	for _, contact := range r.db.contacts {
+		keys[key(contact.CharacterID, contact.ContactID)] = true
+	}
+
+	now := time.Now()
+	rows := make([]*skillz.CharacterContact, 0, len(contacts))
+	for _, contact := range contacts {
+		contact.CreatedAt = now
+
+		k := key(contact.CharacterID, contact.ContactID)
+		if keys[k] {
+			return errors.Wrapf(ErrDuplicateKey, prefixFormat, contactRepositoryIdentifier, "CreateCharacterContacts")
+		}
+		keys[k] = true
+
+		// The table does not have an updated_at column
+		stored := row(contact).(*skillz.CharacterContact)
+		stored.UpdatedAt = time.Time{}
+		rows = append(rows, stored)
+	}
+
+	r.db.contacts = append(r.db.contacts, rows...)
+
+	return nil
+
+}
+
+func (r *contactRepository) DeleteCharacterContacts(ctx context.Context, characterID uint64) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	r.db.deleteCharacterContacts(characterID)
+
+	return nil
+
+}
+
+func (db *DB) deleteCharacterContacts(characterID uint64) {
+	contacts := db.contacts[:0]
+	for _, contact := range db.contacts {
+		if contact.CharacterID != characterID {
+			contacts = append(contacts, contact)
+		}
+	}
+	db.contacts = contacts
+}
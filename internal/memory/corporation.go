@@ -0,0 +1,117 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/eveisesi/skillz"
+	"github.com/pkg/errors"
+)
+
+type corporationRepository struct {
+	db *DB
+}
+
+func NewCorporationRepository(db *DB) skillz.CorporationRepository {
+	return &corporationRepository{
+		db: db,
+	}
+}
+
+func (r *corporationRepository) Corporation(ctx context.Context, corporationID uint) (*skillz.Corporation, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	corporation, ok := r.db.corporations[corporationID]
+	if !ok {
+		return new(skillz.Corporation), errors.Wrapf(sql.ErrNoRows, prefixFormat, corporationRepositoryIdentifier, "Corporation")
+	}
+
+	return row(corporation).(*skillz.Corporation), nil
+
+}
+
+func (r *corporationRepository) CreateCorporation(ctx context.Context, corporation *skillz.Corporation) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	now := time.Now()
+	corporation.CreatedAt = now
+	corporation.UpdatedAt = now
+
+	if _, ok := r.db.corporations[corporation.ID]; ok {
+		return errors.Wrapf(ErrDuplicateKey, prefixFormat, corporationRepositoryIdentifier, "CreateCorporation")
+	}
+
+	r.db.corporations[corporation.ID] = row(corporation).(*skillz.Corporation)
+
+	return nil
+
+}
+
+func (r *corporationRepository) UpdateCorporation(ctx context.Context, corporation *skillz.Corporation) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	corporation.UpdatedAt = time.Now()
+
+	if existing, ok := r.db.corporations[corporation.ID]; ok {
+		r.db.corporations[corporation.ID] = replace(existing, corporation).(*skillz.Corporation)
+	}
+
+	return nil
+
+}
+
+func (r *corporationRepository) CorporationAllianceHistory(ctx context.Context, corporationID uint) ([]*skillz.CorporationAllianceHistory, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var records = make([]*skillz.CorporationAllianceHistory, 0)
+	for _, record := range r.db.corporationHistory {
+		if record.CorporationID == corporationID {
+			records = append(records, row(record).(*skillz.CorporationAllianceHistory))
+		}
+	}
+
+	return records, nil
+
+}
+
+func (r *corporationRepository) CreateCorporationAllianceHistory(ctx context.Context, records []*skillz.CorporationAllianceHistory) ([]*skillz.CorporationAllianceHistory, error) {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if len(records) == 0 {
+		return nil, errors.Wrapf(errNoValues, prefixFormat, corporationRepositoryIdentifier, "CreateCorporationAllianceHistory")
+	}
+
+	index := make(map[string]int, len(r.db.corporationHistory))
+	for i, record := range r.db.corporationHistory {
+		index[key(record.CorporationID, record.RecordID)] = i
+	}
+
+	now := time.Now()
+	for _, record := range records {
+		record.CreatedAt = now
+		record.UpdatedAt = now
+
+		k := key(record.CorporationID, record.RecordID)
+		if i, ok := index[k]; ok {
+			r.db.corporationHistory[i] = upsert(r.db.corporationHistory[i], record).(*skillz.CorporationAllianceHistory)
+			continue
+		}
+
+		index[k] = len(r.db.corporationHistory)
+		r.db.corporationHistory = append(r.db.corporationHistory, row(record).(*skillz.CorporationAllianceHistory))
+	}
+
+	return records, nil
+
+}
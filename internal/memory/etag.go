@@ -0,0 +1,49 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/eveisesi/skillz"
+	"github.com/pkg/errors"
+)
+
+type etagRepository struct {
+	db *DB
+}
+
+func NewETagRepository(db *DB) skillz.EtagRepository {
+	return &etagRepository{
+		db: db,
+	}
+}
+
+func (r *etagRepository) Etag(ctx context.Context, path string) (*skillz.Etag, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	etag, ok := r.db.etags[path]
+	if !ok {
+		return nil, errors.Wrapf(sql.ErrNoRows, prefixFormat, etagRepositoryIdentifier, "Etag")
+	}
+
+	return row(etag).(*skillz.Etag), nil
+
+}
+
+func (r *etagRepository) InsertEtag(ctx context.Context, etag *skillz.Etag) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	now := time.Now()
+	etag.CreatedAt = now
+	etag.UpdatedAt = now
+
+	r.db.etags[etag.Path] = upsert(r.db.etags[etag.Path], etag).(*skillz.Etag)
+
+	return nil
+
+}
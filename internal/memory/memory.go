@@ -0,0 +1,522 @@
+// Package memory implements the skillz repositories in memory so that services can be tested without
+// MySQL. The repositories behave like their MySQL counterparts in the internal/mysql package: misses
+// return sql.ErrNoRows, the Create methods that upsert in MySQL upsert here and the ones that do not
+// fail on a duplicate key. Foreign keys are not enforced, but deletes cascade the way they do in MySQL
+package memory
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eveisesi/skillz"
+	"github.com/pkg/errors"
+	"github.com/volatiletech/null"
+)
+
+// ErrDuplicateKey is returned when a row is created with the primary key of an existing row
+// by a Create method that does not upsert
+var ErrDuplicateKey = errors.New("duplicate entry for key PRIMARY")
+
+// errNoValues matches the error that squirrel returns when an insert is built without any rows
+var errNoValues = errors.New("insert statements must have at least one set of values or select clause")
+
+// DB holds the rows of every table. A DB is shared by the repositories that are built from it the
+// same way the MySQL repositories share a connection, so that deletes are able to cascade across them
+type DB struct {
+	mu sync.RWMutex
+
+	alliances          map[uint]*skillz.Alliance
+	characters         map[uint64]*skillz.Character
+	characterHistory   []*skillz.CharacterCorporationHistory
+	corporations       map[uint]*skillz.Corporation
+	corporationHistory []*skillz.CorporationAllianceHistory
+	contacts           []*skillz.CharacterContact
+	etags              map[string]*skillz.Etag
+
+	implants          []*skillz.CharacterImplant
+	jumpClones        []*skillz.CharacterJumpClone
+	jumpCloneImplants []*skillz.CharacterJumpCloneImplant
+
+	attributes  map[uint64]*skillz.CharacterAttributes
+	meta        map[uint64]*skillz.CharacterSkillMeta
+	skills      []*skillz.CharacterSkill
+	queue       []*skillz.CharacterSkillQueue
+	flyable     []*skillz.CharacterFlyableShip
+	plans       []*skillz.SkillPlan
+	planEntries []*skillz.SkillPlanEntry
+	snapshots   []*skillz.CharacterSkillSnapshot
+	changes     []*skillz.CharacterSkillChange
+	completions []*skillz.CharacterSkillCompletion
+
+	bloodlines     map[uint]*skillz.Bloodline
+	categories     map[uint]*skillz.Category
+	constellations map[uint]*skillz.Constellation
+	factions       map[uint]*skillz.Faction
+	groups         map[uint]*skillz.Group
+	races          map[uint]*skillz.Race
+	regions        map[uint]*skillz.Region
+	solarSystems   map[uint]*skillz.SolarSystem
+	stations       map[uint]*skillz.Station
+	structures     map[uint64]*skillz.Structure
+	types          map[uint]*skillz.Type
+	typeAttributes []*skillz.TypeDogmaAttribute
+	requirements   []*skillz.ShipFlightRequirement
+
+	users      []*skillz.User
+	settings   map[string]*skillz.UserSettings
+	accounts   map[string]*skillz.UserAccount
+	shareLinks []*skillz.UserShareLink
+	shareViews []*skillz.UserShareLinkView
+
+	// lastInsertID is the auto increment of the tables that have one, keyed by table
+	lastInsertID map[string]uint64
+}
+
+func NewDB() *DB {
+	return &DB{
+		alliances:    make(map[uint]*skillz.Alliance),
+		characters:   make(map[uint64]*skillz.Character),
+		corporations: make(map[uint]*skillz.Corporation),
+		etags:        make(map[string]*skillz.Etag),
+
+		attributes: make(map[uint64]*skillz.CharacterAttributes),
+		meta:       make(map[uint64]*skillz.CharacterSkillMeta),
+
+		bloodlines:     make(map[uint]*skillz.Bloodline),
+		categories:     make(map[uint]*skillz.Category),
+		constellations: make(map[uint]*skillz.Constellation),
+		factions:       make(map[uint]*skillz.Faction),
+		groups:         make(map[uint]*skillz.Group),
+		races:          make(map[uint]*skillz.Race),
+		regions:        make(map[uint]*skillz.Region),
+		solarSystems:   make(map[uint]*skillz.SolarSystem),
+		stations:       make(map[uint]*skillz.Station),
+		structures:     make(map[uint64]*skillz.Structure),
+		types:          make(map[uint]*skillz.Type),
+
+		settings: make(map[string]*skillz.UserSettings),
+		accounts: make(map[string]*skillz.UserAccount),
+
+		lastInsertID: make(map[string]uint64),
+	}
+}
+
+func (db *DB) nextID(table string) uint64 {
+	db.lastInsertID[table]++
+	return db.lastInsertID[table]
+}
+
+// key joins the primary key columns of a row so that rows can be looked up by a composite key
+func key(columns ...interface{}) string {
+	return fmt.Sprintf("%v", columns)
+}
+
+// row returns a copy of the row holding only the fields that are backed by a column, which are the fields
+// with a db tag. Times are stored in UTC and rounded to the second like they are in a DATETIME column
+func row(v interface{}) interface{} {
+
+	src := reflect.ValueOf(v).Elem()
+	dst := reflect.New(src.Type()).Elem()
+
+	for i := 0; i < src.NumField(); i++ {
+		if column(src.Type().Field(i)) == "" {
+			continue
+		}
+
+		field := src.Field(i)
+		switch value := field.Interface().(type) {
+		case time.Time:
+			dst.Field(i).Set(reflect.ValueOf(datetime(value)))
+		case null.Time:
+			if value.Valid {
+				value.Time = datetime(value.Time)
+			}
+			dst.Field(i).Set(reflect.ValueOf(value))
+		default:
+			if field.Kind() == reflect.Slice && !field.IsNil() {
+				dst.Field(i).Set(reflect.AppendSlice(reflect.MakeSlice(field.Type(), 0, field.Len()), field))
+				continue
+			}
+			dst.Field(i).Set(field)
+		}
+	}
+
+	return dst.Addr().Interface()
+
+}
+
+// upsert returns the row to store for v when it is inserted with an ON DUPLICATE KEY UPDATE. When existing
+// is not nil, created_at and the columns to keep are carried over from it like MySQL leaves them untouched
+func upsert(existing, v interface{}, keep ...string) interface{} {
+
+	stored := row(v)
+	if reflect.ValueOf(existing).IsNil() {
+		return stored
+	}
+
+	return retain(stored, existing, append(keep, columnCreatedAt)...)
+
+}
+
+// replace returns the row to store for v when the existing row is updated, which keeps its created_at
+func replace(existing, v interface{}) interface{} {
+	return retain(row(v), existing, columnCreatedAt)
+}
+
+// retain copies the columns of src onto dst and returns dst
+func retain(dst, src interface{}, columns ...string) interface{} {
+
+	d := reflect.ValueOf(dst).Elem()
+	s := reflect.ValueOf(src).Elem()
+	for i := 0; i < d.NumField(); i++ {
+		name := column(d.Type().Field(i))
+		for _, c := range columns {
+			if name == c {
+				d.Field(i).Set(s.Field(i))
+			}
+		}
+	}
+
+	return dst
+
+}
+
+func datetime(t time.Time) time.Time {
+	if t.IsZero() {
+		return t
+	}
+	return t.Round(time.Second).UTC()
+}
+
+// column returns the name of the column that backs the field, or an empty string when the field is not backed by one
+func column(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("db"), ",")[0]
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+// columnValue returns the value of the column of the row, normalized to an int64, uint64,
+// float64, string, time.Time or nil so that values of different types can be compared
+func columnValue(v interface{}, name string) (interface{}, bool) {
+
+	rv := reflect.ValueOf(v).Elem()
+	for i := 0; i < rv.NumField(); i++ {
+		if column(rv.Type().Field(i)) == name {
+			return normalize(rv.Field(i).Interface()), true
+		}
+	}
+
+	return nil, false
+
+}
+
+func normalize(v interface{}) interface{} {
+
+	if valuer, ok := v.(driver.Valuer); ok {
+		value, err := valuer.Value()
+		if err != nil {
+			return nil
+		}
+		v = value
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return rv.Uint()
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	case reflect.Bool:
+		// Booleans are stored in TINYINT columns
+		return int64(boolToUint(rv.Bool()))
+	case reflect.String:
+		return rv.String()
+	}
+
+	return v
+
+}
+
+// compare compares two normalized values, returning false when they cannot be compared
+func compare(a, b interface{}) (int, bool) {
+
+	switch x := a.(type) {
+	case int64:
+		switch y := b.(type) {
+		case int64:
+			return compareInts(x, y), true
+		case uint64:
+			if x < 0 {
+				return -1, true
+			}
+			return compareUints(uint64(x), y), true
+		case float64:
+			return compareFloats(float64(x), y), true
+		}
+	case uint64:
+		switch y := b.(type) {
+		case uint64:
+			return compareUints(x, y), true
+		case int64:
+			if y < 0 {
+				return 1, true
+			}
+			return compareUints(x, uint64(y)), true
+		case float64:
+			return compareFloats(float64(x), y), true
+		}
+	case float64:
+		switch y := b.(type) {
+		case float64:
+			return compareFloats(x, y), true
+		case int64:
+			return compareFloats(x, float64(y)), true
+		case uint64:
+			return compareFloats(x, float64(y)), true
+		}
+	case string:
+		if y, ok := b.(string); ok {
+			// The tables use a case insensitive collation
+			return strings.Compare(strings.ToLower(x), strings.ToLower(y)), true
+		}
+	case time.Time:
+		if y, ok := b.(time.Time); ok {
+			switch {
+			case x.Before(y):
+				return -1, true
+			case x.After(y):
+				return 1, true
+			}
+			return 0, true
+		}
+	}
+
+	return 0, false
+
+}
+
+func compareUints(x, y uint64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
+
+func compareInts(x, y int64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
+
+func compareFloats(x, y float64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
+
+func boolToUint(b bool) uint64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// filter returns the rows that match the where operators, ordered and limited by the order and limit operators
+func filter(rows []interface{}, operators ...*skillz.Operator) []interface{} {
+
+	var orders = make([]*skillz.Operator, 0)
+	var limit = -1
+	var skip = 0
+
+	var results = make([]interface{}, 0, len(rows))
+	for _, r := range rows {
+		if matches(r, operators...) {
+			results = append(results, r)
+		}
+	}
+
+	for _, operator := range operators {
+		if operator == nil || !operator.Operation.IsValid() {
+			continue
+		}
+
+		switch operator.Operation {
+		case skillz.OrderOp:
+			orders = append(orders, operator)
+		case skillz.LimitOp:
+			if n, ok := normalize(operator.Value).(int64); ok {
+				limit = int(n)
+			} else if n, ok := normalize(operator.Value).(uint64); ok {
+				limit = int(n)
+			}
+		case skillz.SkipOp:
+			if n, ok := normalize(operator.Value).(int64); ok {
+				skip = int(n)
+			} else if n, ok := normalize(operator.Value).(uint64); ok {
+				skip = int(n)
+			}
+		}
+	}
+
+	if len(orders) > 0 {
+		sort.SliceStable(results, func(i, j int) bool {
+			for _, order := range orders {
+				a, _ := columnValue(results[i], order.Column)
+				b, _ := columnValue(results[j], order.Column)
+				c, _ := compare(a, b)
+				if c == 0 {
+					continue
+				}
+
+				if descending(order.Value) {
+					return c > 0
+				}
+				return c < 0
+			}
+			return false
+		})
+	}
+
+	if skip > len(results) {
+		skip = len(results)
+	}
+	results = results[skip:]
+
+	if limit >= 0 && limit < len(results) {
+		results = results[:limit]
+	}
+
+	return results
+
+}
+
+func descending(v interface{}) bool {
+	switch value := normalize(v).(type) {
+	case int64:
+		return value == int64(skillz.SortDesc)
+	case string:
+		return strings.EqualFold(value, "desc")
+	}
+	return false
+}
+
+// matches reports whether the row satisfies each of the where operators
+func matches(r interface{}, operators ...*skillz.Operator) bool {
+
+	for _, operator := range operators {
+		if operator == nil || !operator.Operation.IsValid() {
+			continue
+		}
+
+		if !match(r, operator) {
+			return false
+		}
+	}
+
+	return true
+
+}
+
+func match(r interface{}, operator *skillz.Operator) bool {
+
+	switch operator.Operation {
+	case skillz.OrderOp, skillz.LimitOp, skillz.SkipOp:
+		return true
+	case skillz.OrOp, skillz.AndOp:
+		operators, _ := operator.Value.([]*skillz.Operator)
+		for _, o := range operators {
+			ok := match(r, o)
+			if operator.Operation == skillz.OrOp && ok {
+				return true
+			}
+			if operator.Operation == skillz.AndOp && !ok {
+				return false
+			}
+		}
+		return operator.Operation == skillz.AndOp
+	}
+
+	value, ok := columnValue(r, operator.Column)
+	if !ok {
+		return false
+	}
+
+	switch operator.Operation {
+	case skillz.ExistsOp:
+		exists, _ := operator.Value.(bool)
+		return (value != nil) == exists
+	case skillz.InOp, skillz.NotInOp:
+		in := false
+		for _, v := range values(operator.Value) {
+			if c, ok := compare(value, normalize(v)); ok && c == 0 {
+				in = true
+				break
+			}
+		}
+		return in == (operator.Operation == skillz.InOp)
+	case skillz.LikeOp:
+		s, ok := value.(string)
+		return ok && strings.Contains(strings.ToLower(s), strings.ToLower(fmt.Sprintf("%v", operator.Value)))
+	}
+
+	c, ok := compare(value, normalize(operator.Value))
+	if !ok {
+		// NULL does not compare to anything
+		return false
+	}
+
+	switch operator.Operation {
+	case skillz.EqualOp:
+		return c == 0
+	case skillz.NotEqualOp:
+		return c != 0
+	case skillz.GreaterThanOp:
+		return c > 0
+	case skillz.GreaterThanEqualToOp:
+		return c >= 0
+	case skillz.LessThanOp:
+		return c < 0
+	case skillz.LessThanEqualToOp:
+		return c <= 0
+	}
+
+	return false
+
+}
+
+// values returns the elements of the slice that an In or NotIn operator was given
+func values(v interface{}) []interface{} {
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice {
+		return []interface{}{v}
+	}
+
+	out := make([]interface{}, 0, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		out = append(out, rv.Index(i).Interface())
+	}
+
+	return out
+
+}
@@ -0,0 +1,25 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/eveisesi/skillz/internal/memory"
+	"github.com/eveisesi/skillz/internal/repotest"
+)
+
+func TestRepositories(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) *repotest.Repositories {
+		db := memory.NewDB()
+		return &repotest.Repositories{
+			Alliance:    memory.NewAllianceRepository(db),
+			Character:   memory.NewCharacterRepository(db),
+			Clone:       memory.NewCloneRepository(db),
+			Contact:     memory.NewContactRepository(db),
+			Corporation: memory.NewCorporationRepository(db),
+			Etag:        memory.NewETagRepository(db),
+			Skill:       memory.NewSkillRepository(db),
+			Universe:    memory.NewUniverseRepository(db),
+			User:        memory.NewUserRepository(db),
+		}
+	})
+}
@@ -0,0 +1,704 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/eveisesi/skillz"
+	"github.com/pkg/errors"
+)
+
+type skillRepository struct {
+	db *DB
+}
+
+func NewSkillRepository(db *DB) skillz.CharacterSkillRepository {
+	return &skillRepository{
+		db: db,
+	}
+}
+
+func (r *skillRepository) CharacterAttributes(ctx context.Context, characterID uint64) (*skillz.CharacterAttributes, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	attributes, ok := r.db.attributes[characterID]
+	if !ok {
+		return new(skillz.CharacterAttributes), errors.Wrapf(sql.ErrNoRows, prefixFormat, skillsRepositoryIdentifier, "CharacterAttributes")
+	}
+
+	return row(attributes).(*skillz.CharacterAttributes), nil
+
+}
+
+func (r *skillRepository) CreateCharacterAttributes(ctx context.Context, attributes *skillz.CharacterAttributes) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	now := time.Now()
+	attributes.CreatedAt = now
+	attributes.UpdatedAt = now
+
+	r.db.attributes[attributes.CharacterID] = upsert(r.db.attributes[attributes.CharacterID], attributes).(*skillz.CharacterAttributes)
+
+	return nil
+
+}
+
+func (r *skillRepository) DeleteCharacterAttributes(ctx context.Context, characterID uint64) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	delete(r.db.attributes, characterID)
+
+	return nil
+
+}
+
+func (r *skillRepository) CharacterFlyableShips(ctx context.Context, characterID uint64) ([]*skillz.CharacterFlyableShip, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var ships = make([]*skillz.CharacterFlyableShip, 0)
+	for _, ship := range r.db.flyable {
+		if ship.CharacterID == characterID {
+			ships = append(ships, row(ship).(*skillz.CharacterFlyableShip))
+		}
+	}
+
+	return ships, nil
+
+}
+
+func (r *skillRepository) CreateCharacterFlyableShips(ctx context.Context, ships []*skillz.CharacterFlyableShip) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if len(ships) == 0 {
+		return errors.Wrapf(errNoValues, prefixFormat, skillsRepositoryIdentifier, "CreateCharacterFlyableShips")
+	}
+
+	keys := make(map[string]bool, len(r.db.flyable)+len(ships))
+	for _, ship := range r.db.flyable {
+		keys[key(ship.CharacterID, ship.ShipTypeID)] = true
+	}
+
+	now := time.Now()
+	rows := make([]*skillz.CharacterFlyableShip, 0, len(ships))
+	for _, ship := range ships {
+		ship.CreatedAt = now
+
+		k := key(ship.CharacterID, ship.ShipTypeID)
+		if keys[k] {
+			return errors.Wrapf(ErrDuplicateKey, prefixFormat, skillsRepositoryIdentifier, "CreateCharacterFlyableShips")
+		}
+		keys[k] = true
+
+		rows = append(rows, row(ship).(*skillz.CharacterFlyableShip))
+	}
+
+	r.db.flyable = append(r.db.flyable, rows...)
+
+	return nil
+
+}
+
+func (r *skillRepository) DeleteCharacterFlyableShips(ctx context.Context, characterID uint64) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	r.db.deleteCharacterFlyableShips(characterID)
+
+	return nil
+
+}
+
+func (r *skillRepository) CharacterSkillMeta(ctx context.Context, characterID uint64) (*skillz.CharacterSkillMeta, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	meta, ok := r.db.meta[characterID]
+	if !ok {
+		return new(skillz.CharacterSkillMeta), errors.Wrapf(sql.ErrNoRows, prefixFormat, skillsRepositoryIdentifier, "CharacterSkillMeta")
+	}
+
+	return row(meta).(*skillz.CharacterSkillMeta), nil
+
+}
+
+func (r *skillRepository) CreateCharacterSkillMeta(ctx context.Context, meta *skillz.CharacterSkillMeta) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	now := time.Now()
+	meta.CreatedAt = now
+	meta.UpdatedAt = now
+
+	r.db.meta[meta.CharacterID] = upsert(r.db.meta[meta.CharacterID], meta).(*skillz.CharacterSkillMeta)
+
+	return nil
+
+}
+
+func (r *skillRepository) DeleteCharacterSkillMeta(ctx context.Context, characterID uint64) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	delete(r.db.meta, characterID)
+
+	return nil
+
+}
+
+func (r *skillRepository) CharacterSkills(ctx context.Context, characterID uint64) ([]*skillz.CharacterSkill, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var skills = make([]*skillz.CharacterSkill, 0)
+	for _, skill := range r.db.skills {
+		if skill.CharacterID == characterID {
+			skills = append(skills, row(skill).(*skillz.CharacterSkill))
+		}
+	}
+
+	return skills, nil
+
+}
+
+func (r *skillRepository) CreateCharacterSkills(ctx context.Context, skills []*skillz.CharacterSkill) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if len(skills) == 0 {
+		return errors.Wrapf(errNoValues, prefixFormat, skillsRepositoryIdentifier, "CreateCharacterSkills")
+	}
+
+	index := make(map[string]int, len(r.db.skills))
+	for i, skill := range r.db.skills {
+		index[key(skill.CharacterID, skill.SkillID)] = i
+	}
+
+	now := time.Now()
+	for _, skill := range skills {
+		skill.CreatedAt = now
+		skill.UpdatedAt = now
+
+		k := key(skill.CharacterID, skill.SkillID)
+		if i, ok := index[k]; ok {
+			r.db.skills[i] = upsert(r.db.skills[i], skill).(*skillz.CharacterSkill)
+			continue
+		}
+
+		index[k] = len(r.db.skills)
+		r.db.skills = append(r.db.skills, row(skill).(*skillz.CharacterSkill))
+	}
+
+	return nil
+
+}
+
+func (r *skillRepository) DeleteCharacterSkills(ctx context.Context, characterID uint64) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	r.db.deleteCharacterSkills(characterID)
+
+	return nil
+
+}
+
+func (r *skillRepository) CharacterSkillQueue(ctx context.Context, characterID uint64) ([]*skillz.CharacterSkillQueue, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var positions = make([]*skillz.CharacterSkillQueue, 0)
+	for _, position := range r.db.queue {
+		if position.CharacterID == characterID {
+			positions = append(positions, row(position).(*skillz.CharacterSkillQueue))
+		}
+	}
+
+	sort.SliceStable(positions, func(i, j int) bool {
+		return positions[i].QueuePosition < positions[j].QueuePosition
+	})
+
+	return positions, nil
+
+}
+
+func (r *skillRepository) CreateCharacterSkillQueue(ctx context.Context, positions []*skillz.CharacterSkillQueue) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if len(positions) == 0 {
+		return errors.Wrapf(errNoValues, prefixFormat, skillsRepositoryIdentifier, "CreateCharacterSkillQueue")
+	}
+
+	keys := make(map[string]bool, len(r.db.queue)+len(positions))
+	for _, position := range r.db.queue {
+		keys[key(position.CharacterID, position.QueuePosition)] = true
+	}
+
+	now := time.Now()
+	rows := make([]*skillz.CharacterSkillQueue, 0, len(positions))
+	for _, position := range positions {
+		position.CreatedAt = now
+
+		k := key(position.CharacterID, position.QueuePosition)
+		if keys[k] {
+			return errors.Wrapf(ErrDuplicateKey, prefixFormat, skillsRepositoryIdentifier, "CreateCharacterSkillQueue")
+		}
+		keys[k] = true
+
+		rows = append(rows, row(position).(*skillz.CharacterSkillQueue))
+	}
+
+	r.db.queue = append(r.db.queue, rows...)
+
+	return nil
+
+}
+
+func (r *skillRepository) DeleteCharacterSkillQueue(ctx context.Context, characterID uint64) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	r.db.deleteCharacterSkillQueue(characterID)
+
+	return nil
+
+}
+
+func (r *skillRepository) SkillPlan(ctx context.Context, id uint) (*skillz.SkillPlan, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	for _, plan := range r.db.plans {
+		if plan.ID == id {
+			return row(plan).(*skillz.SkillPlan), nil
+		}
+	}
+
+	return new(skillz.SkillPlan), errors.Wrapf(sql.ErrNoRows, prefixFormat, skillsRepositoryIdentifier, "SkillPlan")
+
+}
+
+func (r *skillRepository) SkillPlansByUserID(ctx context.Context, userID string) ([]*skillz.SkillPlan, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var plans = make([]*skillz.SkillPlan, 0)
+	for _, plan := range r.db.plans {
+		if plan.UserID == userID {
+			plans = append(plans, row(plan).(*skillz.SkillPlan))
+		}
+	}
+
+	sort.SliceStable(plans, func(i, j int) bool {
+		c, _ := compare(plans[i].Name, plans[j].Name)
+		return c < 0
+	})
+
+	return plans, nil
+
+}
+
+func (r *skillRepository) CreateSkillPlan(ctx context.Context, plan *skillz.SkillPlan) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	id := uint(r.db.nextID(tableSkillPlans))
+	for _, entry := range plan.Entries {
+		entry.PlanID = id
+	}
+
+	err := r.db.createSkillPlanEntries(plan.Entries)
+	if err != nil {
+		return errors.Wrapf(err, prefixFormat, skillsRepositoryIdentifier, "CreateSkillPlan")
+	}
+
+	now := time.Now()
+	plan.ID = id
+	plan.CreatedAt = now
+	plan.UpdatedAt = now
+
+	r.db.plans = append(r.db.plans, row(plan).(*skillz.SkillPlan))
+
+	return nil
+
+}
+
+func (r *skillRepository) UpdateSkillPlan(ctx context.Context, plan *skillz.SkillPlan) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	plan.UpdatedAt = time.Now()
+
+	for _, existing := range r.db.plans {
+		if existing.ID == plan.ID {
+			existing.Name = plan.Name
+			existing.UpdatedAt = datetime(plan.UpdatedAt)
+			break
+		}
+	}
+
+	return nil
+
+}
+
+// DeleteSkillPlan deletes the plan along with its entries, as the foreign key does in MySQL
+func (r *skillRepository) DeleteSkillPlan(ctx context.Context, id uint) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	r.db.deleteSkillPlan(id)
+
+	return nil
+
+}
+
+func (r *skillRepository) SkillPlanEntries(ctx context.Context, planID uint) ([]*skillz.SkillPlanEntry, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var entries = make([]*skillz.SkillPlanEntry, 0)
+	for _, entry := range r.db.planEntries {
+		if entry.PlanID == planID {
+			entries = append(entries, row(entry).(*skillz.SkillPlanEntry))
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Position < entries[j].Position
+	})
+
+	return entries, nil
+
+}
+
+func (r *skillRepository) CreateSkillPlanEntries(ctx context.Context, entries []*skillz.SkillPlanEntry) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if len(entries) == 0 {
+		return errors.Wrapf(errNoValues, prefixFormat, skillsRepositoryIdentifier, "CreateSkillPlanEntries")
+	}
+
+	err := r.db.createSkillPlanEntries(entries)
+	return errors.Wrapf(err, prefixFormat, skillsRepositoryIdentifier, "CreateSkillPlanEntries")
+
+}
+
+func (r *skillRepository) DeleteSkillPlanEntries(ctx context.Context, planID uint) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	r.db.deleteSkillPlanEntries(planID)
+
+	return nil
+
+}
+
+func (r *skillRepository) ReplaceSkillPlanEntries(ctx context.Context, planID uint, entries []*skillz.SkillPlanEntry) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	previous := append(make([]*skillz.SkillPlanEntry, 0, len(r.db.planEntries)), r.db.planEntries...)
+
+	r.db.deleteSkillPlanEntries(planID)
+
+	err := r.db.createSkillPlanEntries(entries)
+	if err != nil {
+		// Nothing is replaced when the entries fail to be created, the way the transaction is rolled back in MySQL
+		r.db.planEntries = previous
+		return errors.Wrapf(err, prefixFormat, skillsRepositoryIdentifier, "ReplaceSkillPlanEntries")
+	}
+
+	return nil
+
+}
+
+func (r *skillRepository) CharacterSkillSnapshots(ctx context.Context, characterID uint64, from, to time.Time) ([]*skillz.CharacterSkillSnapshot, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var snapshots = make([]*skillz.CharacterSkillSnapshot, 0)
+	for _, snapshot := range r.db.snapshots {
+		if snapshot.CharacterID == characterID && between(snapshot.CreatedAt, from, to) {
+			snapshots = append(snapshots, row(snapshot).(*skillz.CharacterSkillSnapshot))
+		}
+	}
+
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return snapshots[i].CreatedAt.Before(snapshots[j].CreatedAt)
+	})
+
+	return snapshots, nil
+
+}
+
+func (r *skillRepository) CreateCharacterSkillSnapshot(ctx context.Context, snapshot *skillz.CharacterSkillSnapshot) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	snapshot.CreatedAt = time.Now()
+	snapshot.ID = r.db.nextID(tableCharacterSkillSnapshots)
+
+	r.db.snapshots = append(r.db.snapshots, row(snapshot).(*skillz.CharacterSkillSnapshot))
+
+	return nil
+
+}
+
+func (r *skillRepository) CharacterSkillChanges(ctx context.Context, characterID uint64, from, to time.Time) ([]*skillz.CharacterSkillChange, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var changes = make([]*skillz.CharacterSkillChange, 0)
+	for _, change := range r.db.changes {
+		if change.CharacterID == characterID && between(change.CreatedAt, from, to) {
+			changes = append(changes, row(change).(*skillz.CharacterSkillChange))
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		if !changes[i].CreatedAt.Equal(changes[j].CreatedAt) {
+			return changes[i].CreatedAt.Before(changes[j].CreatedAt)
+		}
+		return changes[i].SkillID < changes[j].SkillID
+	})
+
+	return changes, nil
+
+}
+
+func (r *skillRepository) CreateCharacterSkillChanges(ctx context.Context, changes []*skillz.CharacterSkillChange) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if len(changes) == 0 {
+		return errors.Wrapf(errNoValues, prefixFormat, skillsRepositoryIdentifier, "CreateCharacterSkillChanges")
+	}
+
+	keys := make(map[string]bool, len(r.db.changes)+len(changes))
+	for _, change := range r.db.changes {
+		keys[key(change.SnapshotID, change.SkillID)] = true
+	}
+
+	now := time.Now()
+	rows := make([]*skillz.CharacterSkillChange, 0, len(changes))
+	for _, change := range changes {
+		change.CreatedAt = now
+
+		k := key(change.SnapshotID, change.SkillID)
+		if keys[k] {
+			return errors.Wrapf(ErrDuplicateKey, prefixFormat, skillsRepositoryIdentifier, "CreateCharacterSkillChanges")
+		}
+		keys[k] = true
+
+		rows = append(rows, row(change).(*skillz.CharacterSkillChange))
+	}
+
+	r.db.changes = append(r.db.changes, rows...)
+
+	return nil
+
+}
+
+func (r *skillRepository) CharacterSkillCompletions(ctx context.Context, characterID uint64, limit uint64) ([]*skillz.CharacterSkillCompletion, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var completions = make([]*skillz.CharacterSkillCompletion, 0)
+	for _, completion := range r.db.completions {
+		if completion.CharacterID == characterID {
+			completions = append(completions, row(completion).(*skillz.CharacterSkillCompletion))
+		}
+	}
+
+	sort.SliceStable(completions, func(i, j int) bool {
+		return completions[i].CompletedAt.After(completions[j].CompletedAt)
+	})
+
+	if uint64(len(completions)) > limit {
+		completions = completions[:limit]
+	}
+
+	return completions, nil
+
+}
+
+// CreateCharacterSkillCompletions ignores completions that have already been recorded, so
+// the same completion can be detected on consecutive runs without being duplicated
+func (r *skillRepository) CreateCharacterSkillCompletions(ctx context.Context, completions []*skillz.CharacterSkillCompletion) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if len(completions) == 0 {
+		return errors.Wrapf(errNoValues, prefixFormat, skillsRepositoryIdentifier, "CreateCharacterSkillCompletions")
+	}
+
+	keys := make(map[string]bool, len(r.db.completions)+len(completions))
+	for _, completion := range r.db.completions {
+		keys[key(completion.CharacterID, completion.SkillID, completion.Level)] = true
+	}
+
+	now := time.Now()
+	for _, completion := range completions {
+		completion.CreatedAt = now
+
+		k := key(completion.CharacterID, completion.SkillID, completion.Level)
+		if keys[k] {
+			continue
+		}
+		keys[k] = true
+
+		r.db.completions = append(r.db.completions, row(completion).(*skillz.CharacterSkillCompletion))
+	}
+
+	return nil
+
+}
+
+// between reports whether the stored time is within from and to, inclusive
+func between(t, from, to time.Time) bool {
+	return !t.Before(from) && !t.After(to)
+}
+
+func (db *DB) deleteCharacterFlyableShips(characterID uint64) {
+	ships := db.flyable[:0]
+	for _, ship := range db.flyable {
+		if ship.CharacterID != characterID {
+			ships = append(ships, ship)
+		}
+	}
+	db.flyable = ships
+}
+
+func (db *DB) deleteCharacterSkills(characterID uint64) {
+	skills := db.skills[:0]
+	for _, skill := range db.skills {
+		if skill.CharacterID != characterID {
+			skills = append(skills, skill)
+		}
+	}
+	db.skills = skills
+}
+
+func (db *DB) deleteCharacterSkillQueue(characterID uint64) {
+	positions := db.queue[:0]
+	for _, position := range db.queue {
+		if position.CharacterID != characterID {
+			positions = append(positions, position)
+		}
+	}
+	db.queue = positions
+}
+
+func (db *DB) deleteCharacterSkillSnapshots(characterID uint64) {
+	snapshots := db.snapshots[:0]
+	for _, snapshot := range db.snapshots {
+		if snapshot.CharacterID != characterID {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+	db.snapshots = snapshots
+
+	changes := db.changes[:0]
+	for _, change := range db.changes {
+		if change.CharacterID != characterID {
+			changes = append(changes, change)
+		}
+	}
+	db.changes = changes
+}
+
+func (db *DB) deleteCharacterSkillCompletions(characterID uint64) {
+	completions := db.completions[:0]
+	for _, completion := range db.completions {
+		if completion.CharacterID != characterID {
+			completions = append(completions, completion)
+		}
+	}
+	db.completions = completions
+}
+
+func (db *DB) deleteSkillPlan(id uint) {
+	plans := db.plans[:0]
+	for _, plan := range db.plans {
+		if plan.ID != id {
+			plans = append(plans, plan)
+		}
+	}
+	db.plans = plans
+
+	db.deleteSkillPlanEntries(id)
+}
+
+// createSkillPlanEntries creates the entries, or none of them when one of them has the position of an existing entry
+func (db *DB) createSkillPlanEntries(entries []*skillz.SkillPlanEntry) error {
+	keys := make(map[string]bool, len(db.planEntries)+len(entries))
+	for _, entry := range db.planEntries {
+		keys[key(entry.PlanID, entry.Position)] = true
+	}
+
+	now := time.Now()
+	rows := make([]*skillz.SkillPlanEntry, 0, len(entries))
+	for _, entry := range entries {
+		entry.CreatedAt = now
+
+		k := key(entry.PlanID, entry.Position)
+		if keys[k] {
+			return ErrDuplicateKey
+		}
+		keys[k] = true
+
+		rows = append(rows, row(entry).(*skillz.SkillPlanEntry))
+	}
+
+	db.planEntries = append(db.planEntries, rows...)
+
+	return nil
+}
+
+func (db *DB) deleteSkillPlanEntries(planID uint) {
+	entries := db.planEntries[:0]
+	for _, entry := range db.planEntries {
+		if entry.PlanID != planID {
+			entries = append(entries, entry)
+		}
+	}
+	db.planEntries = entries
+}
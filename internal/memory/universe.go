@@ -0,0 +1,874 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/eveisesi/skillz"
+	"github.com/pkg/errors"
+)
+
+type universeRepository struct {
+	db *DB
+}
+
+func NewUniverseRepository(db *DB) skillz.UniverseRepository {
+	return &universeRepository{
+		db: db,
+	}
+}
+
+func (r *universeRepository) Bloodline(ctx context.Context, bloodlineID uint) (*skillz.Bloodline, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	bloodline, ok := r.db.bloodlines[bloodlineID]
+	if !ok {
+		return new(skillz.Bloodline), errors.Wrapf(sql.ErrNoRows, prefixFormat, universeRepositoryIdentifier, "Bloodline")
+	}
+
+	return row(bloodline).(*skillz.Bloodline), nil
+
+}
+
+func (r *universeRepository) Bloodlines(ctx context.Context) ([]*skillz.Bloodline, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var bloodlines = make([]*skillz.Bloodline, 0, len(r.db.bloodlines))
+	for _, bloodline := range r.db.bloodlines {
+		bloodlines = append(bloodlines, row(bloodline).(*skillz.Bloodline))
+	}
+
+	sort.Slice(bloodlines, func(i, j int) bool {
+		return bloodlines[i].ID < bloodlines[j].ID
+	})
+
+	return bloodlines, nil
+
+}
+
+func (r *universeRepository) CreateBloodline(ctx context.Context, bloodline *skillz.Bloodline) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	now := time.Now()
+	bloodline.CreatedAt = now
+	bloodline.UpdatedAt = now
+
+	if _, ok := r.db.bloodlines[bloodline.ID]; ok {
+		return errors.Wrapf(ErrDuplicateKey, prefixFormat, universeRepositoryIdentifier, "CreateBloodline")
+	}
+
+	r.db.bloodlines[bloodline.ID] = row(bloodline).(*skillz.Bloodline)
+
+	return nil
+
+}
+
+func (r *universeRepository) UpdateBloodline(ctx context.Context, bloodline *skillz.Bloodline) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	bloodline.UpdatedAt = time.Now()
+
+	if existing, ok := r.db.bloodlines[bloodline.ID]; ok {
+		r.db.bloodlines[bloodline.ID] = replace(existing, bloodline).(*skillz.Bloodline)
+	}
+
+	return nil
+
+}
+
+func (r *universeRepository) Category(ctx context.Context, categoryID uint) (*skillz.Category, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	category, ok := r.db.categories[categoryID]
+	if !ok {
+		return new(skillz.Category), errors.Wrapf(sql.ErrNoRows, prefixFormat, universeRepositoryIdentifier, "Category")
+	}
+
+	return row(category).(*skillz.Category), nil
+
+}
+
+func (r *universeRepository) Categories(ctx context.Context) ([]*skillz.Category, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var categories = make([]*skillz.Category, 0, len(r.db.categories))
+	for _, category := range r.db.categories {
+		categories = append(categories, row(category).(*skillz.Category))
+	}
+
+	sort.Slice(categories, func(i, j int) bool {
+		return categories[i].ID < categories[j].ID
+	})
+
+	return categories, nil
+
+}
+
+func (r *universeRepository) CreateCategory(ctx context.Context, category *skillz.Category) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	now := time.Now()
+	category.CreatedAt = now
+	category.UpdatedAt = now
+
+	r.db.categories[category.ID] = upsert(r.db.categories[category.ID], category).(*skillz.Category)
+
+	return nil
+
+}
+
+func (r *universeRepository) UpdateCategory(ctx context.Context, category *skillz.Category) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	category.UpdatedAt = time.Now()
+
+	if existing, ok := r.db.categories[category.ID]; ok {
+		r.db.categories[category.ID] = replace(existing, category).(*skillz.Category)
+	}
+
+	return nil
+
+}
+
+func (r *universeRepository) Constellation(ctx context.Context, constellationID uint) (*skillz.Constellation, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	constellation, ok := r.db.constellations[constellationID]
+	if !ok {
+		return new(skillz.Constellation), errors.Wrapf(sql.ErrNoRows, prefixFormat, universeRepositoryIdentifier, "Constellation")
+	}
+
+	return row(constellation).(*skillz.Constellation), nil
+
+}
+
+func (r *universeRepository) Constellations(ctx context.Context) ([]*skillz.Constellation, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var constellations = make([]*skillz.Constellation, 0, len(r.db.constellations))
+	for _, constellation := range r.db.constellations {
+		constellations = append(constellations, row(constellation).(*skillz.Constellation))
+	}
+
+	sort.Slice(constellations, func(i, j int) bool {
+		return constellations[i].ID < constellations[j].ID
+	})
+
+	return constellations, nil
+
+}
+
+func (r *universeRepository) CreateConstellation(ctx context.Context, constellation *skillz.Constellation) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	now := time.Now()
+	constellation.CreatedAt = now
+	constellation.UpdatedAt = now
+
+	r.db.constellations[constellation.ID] = upsert(r.db.constellations[constellation.ID], constellation).(*skillz.Constellation)
+
+	return nil
+
+}
+
+func (r *universeRepository) UpdateConstellation(ctx context.Context, constellation *skillz.Constellation) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	constellation.UpdatedAt = time.Now()
+
+	if existing, ok := r.db.constellations[constellation.ID]; ok {
+		r.db.constellations[constellation.ID] = replace(existing, constellation).(*skillz.Constellation)
+	}
+
+	return nil
+
+}
+
+func (r *universeRepository) Faction(ctx context.Context, factionID uint) (*skillz.Faction, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	faction, ok := r.db.factions[factionID]
+	if !ok {
+		return new(skillz.Faction), errors.Wrapf(sql.ErrNoRows, prefixFormat, universeRepositoryIdentifier, "Faction")
+	}
+
+	return row(faction).(*skillz.Faction), nil
+
+}
+
+func (r *universeRepository) Factions(ctx context.Context) ([]*skillz.Faction, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var factions = make([]*skillz.Faction, 0, len(r.db.factions))
+	for _, faction := range r.db.factions {
+		factions = append(factions, row(faction).(*skillz.Faction))
+	}
+
+	sort.Slice(factions, func(i, j int) bool {
+		return factions[i].ID < factions[j].ID
+	})
+
+	return factions, nil
+
+}
+
+func (r *universeRepository) CreateFaction(ctx context.Context, faction *skillz.Faction) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	now := time.Now()
+	faction.CreatedAt = now
+	faction.UpdatedAt = now
+
+	if _, ok := r.db.factions[faction.ID]; ok {
+		return errors.Wrapf(ErrDuplicateKey, prefixFormat, universeRepositoryIdentifier, "CreateFaction")
+	}
+
+	r.db.factions[faction.ID] = row(faction).(*skillz.Faction)
+
+	return nil
+
+}
+
+func (r *universeRepository) UpdateFaction(ctx context.Context, faction *skillz.Faction) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	faction.UpdatedAt = time.Now()
+
+	if existing, ok := r.db.factions[faction.ID]; ok {
+		r.db.factions[faction.ID] = replace(existing, faction).(*skillz.Faction)
+	}
+
+	return nil
+
+}
+
+func (r *universeRepository) Group(ctx context.Context, groupID uint) (*skillz.Group, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	group, ok := r.db.groups[groupID]
+	if !ok {
+		return new(skillz.Group), errors.Wrapf(sql.ErrNoRows, prefixFormat, universeRepositoryIdentifier, "Group")
+	}
+
+	return row(group).(*skillz.Group), nil
+
+}
+
+func (r *universeRepository) Groups(ctx context.Context, operators ...*skillz.Operator) ([]*skillz.Group, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var rows = make([]interface{}, 0, len(r.db.groups))
+	for _, group := range r.db.groups {
+		rows = append(rows, row(group))
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i].(*skillz.Group).ID < rows[j].(*skillz.Group).ID
+	})
+
+	rows = filter(rows, operators...)
+
+	var groups = make([]*skillz.Group, 0, len(rows))
+	for _, group := range rows {
+		groups = append(groups, group.(*skillz.Group))
+	}
+
+	return groups, nil
+
+}
+
+func (r *universeRepository) CreateGroup(ctx context.Context, group *skillz.Group) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	now := time.Now()
+	group.CreatedAt = now
+	group.UpdatedAt = now
+
+	r.db.groups[group.ID] = upsert(r.db.groups[group.ID], group).(*skillz.Group)
+
+	return nil
+
+}
+
+func (r *universeRepository) UpdateGroup(ctx context.Context, group *skillz.Group) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	group.UpdatedAt = time.Now()
+
+	if existing, ok := r.db.groups[group.ID]; ok {
+		r.db.groups[group.ID] = replace(existing, group).(*skillz.Group)
+	}
+
+	return nil
+
+}
+
+func (r *universeRepository) Race(ctx context.Context, raceID uint) (*skillz.Race, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	race, ok := r.db.races[raceID]
+	if !ok {
+		return new(skillz.Race), errors.Wrapf(sql.ErrNoRows, prefixFormat, universeRepositoryIdentifier, "Race")
+	}
+
+	return row(race).(*skillz.Race), nil
+
+}
+
+// Races returns every race. The operators are accepted for the interface but, like in MySQL, are not applied
+func (r *universeRepository) Races(ctx context.Context, operators ...*skillz.Operator) ([]*skillz.Race, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var races = make([]*skillz.Race, 0, len(r.db.races))
+	for _, race := range r.db.races {
+		races = append(races, row(race).(*skillz.Race))
+	}
+
+	sort.Slice(races, func(i, j int) bool {
+		return races[i].ID < races[j].ID
+	})
+
+	return races, nil
+
+}
+
+func (r *universeRepository) CreateRace(ctx context.Context, race *skillz.Race) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	now := time.Now()
+	race.CreatedAt = now
+	race.UpdatedAt = now
+
+	if _, ok := r.db.races[race.ID]; ok {
+		return errors.Wrapf(ErrDuplicateKey, prefixFormat, universeRepositoryIdentifier, "CreateRace")
+	}
+
+	r.db.races[race.ID] = row(race).(*skillz.Race)
+
+	return nil
+
+}
+
+func (r *universeRepository) UpdateRace(ctx context.Context, race *skillz.Race) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	race.UpdatedAt = time.Now()
+
+	if existing, ok := r.db.races[race.ID]; ok {
+		r.db.races[race.ID] = replace(existing, race).(*skillz.Race)
+	}
+
+	return nil
+
+}
+
+func (r *universeRepository) Region(ctx context.Context, regionID uint) (*skillz.Region, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	region, ok := r.db.regions[regionID]
+	if !ok {
+		return new(skillz.Region), errors.Wrapf(sql.ErrNoRows, prefixFormat, universeRepositoryIdentifier, "Region")
+	}
+
+	return row(region).(*skillz.Region), nil
+
+}
+
+func (r *universeRepository) Regions(ctx context.Context) ([]*skillz.Region, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var regions = make([]*skillz.Region, 0, len(r.db.regions))
+	for _, region := range r.db.regions {
+		regions = append(regions, row(region).(*skillz.Region))
+	}
+
+	sort.Slice(regions, func(i, j int) bool {
+		return regions[i].ID < regions[j].ID
+	})
+
+	return regions, nil
+
+}
+
+func (r *universeRepository) CreateRegion(ctx context.Context, region *skillz.Region) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	now := time.Now()
+	region.CreatedAt = now
+	region.UpdatedAt = now
+
+	r.db.regions[region.ID] = upsert(r.db.regions[region.ID], region).(*skillz.Region)
+
+	return nil
+
+}
+
+func (r *universeRepository) UpdateRegion(ctx context.Context, region *skillz.Region) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	region.UpdatedAt = time.Now()
+
+	if existing, ok := r.db.regions[region.ID]; ok {
+		r.db.regions[region.ID] = replace(existing, region).(*skillz.Region)
+	}
+
+	return nil
+
+}
+
+func (r *universeRepository) SolarSystem(ctx context.Context, solarSystemID uint) (*skillz.SolarSystem, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	solarSystem, ok := r.db.solarSystems[solarSystemID]
+	if !ok {
+		return new(skillz.SolarSystem), errors.Wrapf(sql.ErrNoRows, prefixFormat, universeRepositoryIdentifier, "SolarSystem")
+	}
+
+	return row(solarSystem).(*skillz.SolarSystem), nil
+
+}
+
+// SolarSystems returns every solar system. The operators are accepted for the interface but, like in MySQL, are not applied
+func (r *universeRepository) SolarSystems(ctx context.Context, operators ...*skillz.Operator) ([]*skillz.SolarSystem, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var solarSystems = make([]*skillz.SolarSystem, 0, len(r.db.solarSystems))
+	for _, solarSystem := range r.db.solarSystems {
+		solarSystems = append(solarSystems, row(solarSystem).(*skillz.SolarSystem))
+	}
+
+	sort.Slice(solarSystems, func(i, j int) bool {
+		return solarSystems[i].ID < solarSystems[j].ID
+	})
+
+	return solarSystems, nil
+
+}
+
+func (r *universeRepository) CreateSolarSystem(ctx context.Context, solarSystem *skillz.SolarSystem) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	now := time.Now()
+	solarSystem.CreatedAt = now
+	solarSystem.UpdatedAt = now
+
+	r.db.solarSystems[solarSystem.ID] = upsert(r.db.solarSystems[solarSystem.ID], solarSystem).(*skillz.SolarSystem)
+
+	return nil
+
+}
+
+func (r *universeRepository) UpdateSolarSystem(ctx context.Context, solarSystem *skillz.SolarSystem) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	solarSystem.UpdatedAt = time.Now()
+
+	if existing, ok := r.db.solarSystems[solarSystem.ID]; ok {
+		r.db.solarSystems[solarSystem.ID] = replace(existing, solarSystem).(*skillz.SolarSystem)
+	}
+
+	return nil
+
+}
+
+func (r *universeRepository) Station(ctx context.Context, stationID uint) (*skillz.Station, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	station, ok := r.db.stations[stationID]
+	if !ok {
+		return new(skillz.Station), errors.Wrapf(sql.ErrNoRows, prefixFormat, universeRepositoryIdentifier, "Station")
+	}
+
+	return row(station).(*skillz.Station), nil
+
+}
+
+// Stations returns every station. The operators are accepted for the interface but, like in MySQL, are not applied
+func (r *universeRepository) Stations(ctx context.Context, operators ...*skillz.Operator) ([]*skillz.Station, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var stations = make([]*skillz.Station, 0, len(r.db.stations))
+	for _, station := range r.db.stations {
+		stations = append(stations, row(station).(*skillz.Station))
+	}
+
+	sort.Slice(stations, func(i, j int) bool {
+		return stations[i].ID < stations[j].ID
+	})
+
+	return stations, nil
+
+}
+
+func (r *universeRepository) CreateStation(ctx context.Context, station *skillz.Station) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	now := time.Now()
+	station.CreatedAt = now
+	station.UpdatedAt = now
+
+	r.db.stations[station.ID] = upsert(r.db.stations[station.ID], station).(*skillz.Station)
+
+	return nil
+
+}
+
+func (r *universeRepository) UpdateStation(ctx context.Context, station *skillz.Station) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	station.UpdatedAt = time.Now()
+
+	if existing, ok := r.db.stations[station.ID]; ok {
+		r.db.stations[station.ID] = replace(existing, station).(*skillz.Station)
+	}
+
+	return nil
+
+}
+
+func (r *universeRepository) Structure(ctx context.Context, structureID uint64) (*skillz.Structure, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	structure, ok := r.db.structures[structureID]
+	if !ok {
+		return new(skillz.Structure), errors.Wrapf(sql.ErrNoRows, prefixFormat, universeRepositoryIdentifier, "Structure")
+	}
+
+	return row(structure).(*skillz.Structure), nil
+
+}
+
+func (r *universeRepository) Structures(ctx context.Context) ([]*skillz.Structure, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var structures = make([]*skillz.Structure, 0, len(r.db.structures))
+	for _, structure := range r.db.structures {
+		structures = append(structures, row(structure).(*skillz.Structure))
+	}
+
+	sort.Slice(structures, func(i, j int) bool {
+		return structures[i].ID < structures[j].ID
+	})
+
+	return structures, nil
+
+}
+
+// CreateStructure upserts the structure. Unlike the other upserts, updated_at is not
+// updated when the structure already exists
+func (r *universeRepository) CreateStructure(ctx context.Context, structure *skillz.Structure) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	now := time.Now()
+	structure.CreatedAt = now
+	structure.UpdatedAt = now
+
+	r.db.structures[structure.ID] = upsert(r.db.structures[structure.ID], structure, columnUpdatedAt).(*skillz.Structure)
+
+	return nil
+
+}
+
+func (r *universeRepository) UpdateStructure(ctx context.Context, structure *skillz.Structure) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	structure.UpdatedAt = time.Now()
+
+	if existing, ok := r.db.structures[structure.ID]; ok {
+		r.db.structures[structure.ID] = replace(existing, structure).(*skillz.Structure)
+	}
+
+	return nil
+
+}
+
+func (r *universeRepository) Type(ctx context.Context, typeID uint) (*skillz.Type, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	item, ok := r.db.types[typeID]
+	if !ok {
+		return new(skillz.Type), errors.Wrapf(sql.ErrNoRows, prefixFormat, universeRepositoryIdentifier, "Type")
+	}
+
+	return row(item).(*skillz.Type), nil
+
+}
+
+func (r *universeRepository) Types(ctx context.Context, operators ...*skillz.Operator) ([]*skillz.Type, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var rows = make([]interface{}, 0, len(r.db.types))
+	for _, item := range r.db.types {
+		rows = append(rows, row(item))
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i].(*skillz.Type).ID < rows[j].(*skillz.Type).ID
+	})
+
+	rows = filter(rows, operators...)
+
+	var items = make([]*skillz.Type, 0, len(rows))
+	for _, item := range rows {
+		items = append(items, item.(*skillz.Type))
+	}
+
+	return items, nil
+
+}
+
+func (r *universeRepository) CreateType(ctx context.Context, item *skillz.Type) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	now := time.Now()
+	item.CreatedAt = now
+	item.UpdatedAt = now
+
+	r.db.types[item.ID] = upsert(r.db.types[item.ID], item).(*skillz.Type)
+
+	return nil
+
+}
+
+func (r *universeRepository) UpdateType(ctx context.Context, item *skillz.Type) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	item.UpdatedAt = time.Now()
+
+	if existing, ok := r.db.types[item.ID]; ok {
+		r.db.types[item.ID] = replace(existing, item).(*skillz.Type)
+	}
+
+	return nil
+
+}
+
+func (r *universeRepository) TypeDogmaAttributes(ctx context.Context, typeID uint) ([]*skillz.TypeDogmaAttribute, error) {
+	return r.TypeDogmaAttributesBulk(ctx, []uint{typeID})
+}
+
+func (r *universeRepository) TypeDogmaAttributesBulk(ctx context.Context, typeIDs []uint) ([]*skillz.TypeDogmaAttribute, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	ids := make(map[uint]bool, len(typeIDs))
+	for _, id := range typeIDs {
+		ids[id] = true
+	}
+
+	var typeAttributes = make([]*skillz.TypeDogmaAttribute, 0)
+	for _, attribute := range r.db.typeAttributes {
+		if ids[attribute.TypeID] {
+			typeAttributes = append(typeAttributes, row(attribute).(*skillz.TypeDogmaAttribute))
+		}
+	}
+
+	return typeAttributes, nil
+
+}
+
+func (r *universeRepository) CreateTypeDogmaAttributes(ctx context.Context, attributes []*skillz.TypeDogmaAttribute) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if len(attributes) == 0 {
+		return errors.Wrapf(errNoValues, prefixFormat, universeRepositoryIdentifier, "CreateTypeDogmaAttributes")
+	}
+
+	index := make(map[string]int, len(r.db.typeAttributes))
+	for i, attribute := range r.db.typeAttributes {
+		index[key(attribute.TypeID, attribute.AttributeID)] = i
+	}
+
+	now := time.Now()
+	for _, attribute := range attributes {
+		attribute.CreatedAt = now
+
+		k := key(attribute.TypeID, attribute.AttributeID)
+		if i, ok := index[k]; ok {
+			r.db.typeAttributes[i].Value = attribute.Value
+			continue
+		}
+
+		index[k] = len(r.db.typeAttributes)
+		r.db.typeAttributes = append(r.db.typeAttributes, row(attribute).(*skillz.TypeDogmaAttribute))
+	}
+
+	return nil
+
+}
+
+func (r *universeRepository) DeleteTypeDogmaAttributes(ctx context.Context, typeID uint) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	attributes := r.db.typeAttributes[:0]
+	for _, attribute := range r.db.typeAttributes {
+		if attribute.TypeID != typeID {
+			attributes = append(attributes, attribute)
+		}
+	}
+	r.db.typeAttributes = attributes
+
+	return nil
+
+}
+
+func (r *universeRepository) ShipFlightRequirements(ctx context.Context, shipID uint) ([]*skillz.ShipFlightRequirement, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var requirements = make([]*skillz.ShipFlightRequirement, 0)
+	for _, requirement := range r.db.requirements {
+		if requirement.ShipID == shipID {
+			requirements = append(requirements, row(requirement).(*skillz.ShipFlightRequirement))
+		}
+	}
+
+	return requirements, nil
+
+}
+
+func (r *universeRepository) CreateShipFlightRequirements(ctx context.Context, requirements []*skillz.ShipFlightRequirement) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if len(requirements) == 0 {
+		return errors.Wrapf(errNoValues, prefixFormat, universeRepositoryIdentifier, "CreateShipFlightRequirements")
+	}
+
+	index := make(map[string]int, len(r.db.requirements))
+	for i, requirement := range r.db.requirements {
+		index[key(requirement.ShipID, requirement.SkillID)] = i
+	}
+
+	now := time.Now()
+	for _, requirement := range requirements {
+		requirement.CreatedAt = now
+
+		k := key(requirement.ShipID, requirement.SkillID)
+		if i, ok := index[k]; ok {
+			r.db.requirements[i].GroupID = requirement.GroupID
+			r.db.requirements[i].MinimumSkillLevel = requirement.MinimumSkillLevel
+			continue
+		}
+
+		index[k] = len(r.db.requirements)
+		r.db.requirements = append(r.db.requirements, row(requirement).(*skillz.ShipFlightRequirement))
+	}
+
+	return nil
+
+}
+
+func (r *universeRepository) DeleteShipFlightRequirements(ctx context.Context, shipID uint) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	requirements := r.db.requirements[:0]
+	for _, requirement := range r.db.requirements {
+		if requirement.ShipID != shipID {
+			requirements = append(requirements, requirement)
+		}
+	}
+	r.db.requirements = requirements
+
+	return nil
+
+}
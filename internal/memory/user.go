@@ -0,0 +1,459 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/eveisesi/skillz"
+	"github.com/pkg/errors"
+	"github.com/volatiletech/null"
+)
+
+type userRepository struct {
+	db *DB
+}
+
+func NewUserRepository(db *DB) skillz.UserRepository {
+	return &userRepository{
+		db: db,
+	}
+}
+
+func (r *userRepository) User(ctx context.Context, id string) (*skillz.User, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	for _, user := range r.db.users {
+		if user.ID == id {
+			return userRow(user), nil
+		}
+	}
+
+	return new(skillz.User), errors.Wrapf(sql.ErrNoRows, prefixFormat, userRepositoryIdentifier, "User")
+
+}
+
+func (r *userRepository) UserByCharacterID(ctx context.Context, characterID uint64) (*skillz.User, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	for _, user := range r.db.users {
+		if user.CharacterID == characterID {
+			return userRow(user), nil
+		}
+	}
+
+	return new(skillz.User), errors.Wrapf(sql.ErrNoRows, prefixFormat, userRepositoryIdentifier, "UserByCharacterID")
+
+}
+
+func (r *userRepository) SearchUsers(ctx context.Context, q string) ([]*skillz.User, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var users = make([]*skillz.User, 0)
+	for _, user := range r.db.users {
+		character, ok := r.db.characters[user.CharacterID]
+		if ok && strings.Contains(strings.ToLower(character.Name), strings.ToLower(q)) {
+			users = append(users, userRow(user))
+		}
+	}
+
+	return users, nil
+
+}
+
+// CreateUser upserts the user by their id. The character of an existing user is not changed
+func (r *userRepository) CreateUser(ctx context.Context, user *skillz.User) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	now := time.Now()
+	user.CreatedAt = now
+	user.UpdatedAt = now
+
+	for i, existing := range r.db.users {
+		if existing.ID == user.ID {
+			r.db.users[i] = upsert(existing, user, columnCharacterID).(*skillz.User)
+			return nil
+		}
+	}
+
+	r.db.users = append(r.db.users, row(user).(*skillz.User))
+
+	return nil
+
+}
+
+// DeleteUser deletes the user along with every row that references them, as the foreign keys do in MySQL
+func (r *userRepository) DeleteUser(ctx context.Context, user *skillz.User) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	users := r.db.users[:0]
+	for _, existing := range r.db.users {
+		if existing.ID != user.ID {
+			users = append(users, existing)
+			continue
+		}
+
+		r.db.deleteUserRows(existing)
+	}
+	r.db.users = users
+
+	return nil
+
+}
+
+func (r *userRepository) UsersSortedByProcessedAtLimit(ctx context.Context) ([]*skillz.User, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var users = make([]*skillz.User, 0)
+	for _, user := range r.db.users {
+		if !user.Disabled {
+			users = append(users, user)
+		}
+	}
+
+	// NULLs sort first in ascending order
+	sort.SliceStable(users, func(i, j int) bool {
+		a, b := users[i].LastProcessed, users[j].LastProcessed
+		if !a.Valid || !b.Valid {
+			return !a.Valid && b.Valid
+		}
+		return a.Time.Before(b.Time)
+	})
+
+	for i, user := range users {
+		users[i] = userRow(user)
+	}
+
+	return users, nil
+
+}
+
+func (r *userRepository) NewUsersBySP(ctx context.Context) ([]*skillz.User, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	since := time.Now().UTC().AddDate(0, 0, -7).Truncate(24 * time.Hour)
+
+	var users = make([]*skillz.User, 0)
+	for _, user := range r.db.users {
+		settings, ok := r.db.settings[user.ID]
+		if !ok || settings.Visibility != skillz.VisibilityPublic || user.CreatedAt.Before(since) {
+			continue
+		}
+
+		users = append(users, userRow(user))
+	}
+
+	sort.SliceStable(users, func(i, j int) bool {
+		return users[i].CreatedAt.After(users[j].CreatedAt)
+	})
+
+	if len(users) > 50 {
+		users = users[:50]
+	}
+
+	return users, nil
+
+}
+
+func (r *userRepository) UsersSharingWithCorporation(ctx context.Context, corporationID uint, allianceID null.Uint) ([]*skillz.User, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var users = make([]*skillz.User, 0)
+	for _, user := range r.db.users {
+		if user.Disabled {
+			continue
+		}
+
+		settings, ok := r.db.settings[user.ID]
+		if !ok || !settings.ShareWithCorporation {
+			continue
+		}
+
+		character, ok := r.db.characters[user.CharacterID]
+		if !ok {
+			continue
+		}
+
+		if character.CorporationID == corporationID || (allianceID.Valid && character.AllianceID.Valid && character.AllianceID.Uint == allianceID.Uint) {
+			users = append(users, userRow(user))
+		}
+	}
+
+	return users, nil
+
+}
+
+func (r *userRepository) UserSettings(ctx context.Context, id string) (*skillz.UserSettings, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	settings, ok := r.db.settings[id]
+	if !ok {
+		return new(skillz.UserSettings), errors.Wrapf(sql.ErrNoRows, prefixFormat, userRepositoryIdentifier, "UserSettings")
+	}
+
+	return row(settings).(*skillz.UserSettings), nil
+
+}
+
+func (r *userRepository) CreateUserSettings(ctx context.Context, settings *skillz.UserSettings) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	now := time.Now()
+	settings.CreatedAt = now
+	settings.UpdatedAt = now
+
+	r.db.settings[settings.UserID] = upsert(r.db.settings[settings.UserID], settings).(*skillz.UserSettings)
+
+	return nil
+
+}
+
+func (r *userRepository) UserAccount(ctx context.Context, userID string) (*skillz.UserAccount, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	account, ok := r.db.accounts[userID]
+	if !ok {
+		return new(skillz.UserAccount), errors.Wrapf(sql.ErrNoRows, prefixFormat, userRepositoryIdentifier, "UserAccount")
+	}
+
+	return row(account).(*skillz.UserAccount), nil
+
+}
+
+func (r *userRepository) UserAccountsByAccountID(ctx context.Context, accountID string) ([]*skillz.UserAccount, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var accounts = make([]*skillz.UserAccount, 0)
+	for _, account := range r.db.accounts {
+		if account.AccountID == accountID {
+			accounts = append(accounts, row(account).(*skillz.UserAccount))
+		}
+	}
+
+	sort.Slice(accounts, func(i, j int) bool {
+		if !accounts[i].CreatedAt.Equal(accounts[j].CreatedAt) {
+			return accounts[i].CreatedAt.Before(accounts[j].CreatedAt)
+		}
+		return accounts[i].UserID < accounts[j].UserID
+	})
+
+	return accounts, nil
+
+}
+
+// CreateUserAccount upserts the account of the user. Relinking a user replaces
+// every column of their account, including created_at
+func (r *userRepository) CreateUserAccount(ctx context.Context, account *skillz.UserAccount) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	account.CreatedAt = time.Now()
+
+	r.db.accounts[account.UserID] = row(account).(*skillz.UserAccount)
+
+	return nil
+
+}
+
+func (r *userRepository) DeleteUserAccount(ctx context.Context, userID string) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	delete(r.db.accounts, userID)
+
+	return nil
+
+}
+
+func (r *userRepository) UserShareLink(ctx context.Context, token string) (*skillz.UserShareLink, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	for _, link := range r.db.shareLinks {
+		if link.Token == token {
+			return row(link).(*skillz.UserShareLink), nil
+		}
+	}
+
+	return new(skillz.UserShareLink), errors.Wrapf(sql.ErrNoRows, prefixFormat, userRepositoryIdentifier, "UserShareLink")
+
+}
+
+func (r *userRepository) UserShareLinks(ctx context.Context, userID string) ([]*skillz.UserShareLink, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var links = make([]*skillz.UserShareLink, 0)
+	for _, link := range r.db.shareLinks {
+		if link.UserID == userID {
+			links = append(links, row(link).(*skillz.UserShareLink))
+		}
+	}
+
+	sort.SliceStable(links, func(i, j int) bool {
+		return links[i].CreatedAt.After(links[j].CreatedAt)
+	})
+
+	return links, nil
+
+}
+
+func (r *userRepository) CreateUserShareLink(ctx context.Context, link *skillz.UserShareLink) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	link.CreatedAt = time.Now()
+
+	for _, existing := range r.db.shareLinks {
+		if existing.Token == link.Token {
+			return errors.Wrapf(ErrDuplicateKey, prefixFormat, userRepositoryIdentifier, "CreateUserShareLink")
+		}
+	}
+
+	r.db.shareLinks = append(r.db.shareLinks, row(link).(*skillz.UserShareLink))
+
+	return nil
+
+}
+
+// DeleteUserShareLink deletes the link when it belongs to the user, along with its views
+func (r *userRepository) DeleteUserShareLink(ctx context.Context, userID, token string) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	r.db.deleteUserShareLinks(func(link *skillz.UserShareLink) bool {
+		return link.UserID == userID && link.Token == token
+	})
+
+	return nil
+
+}
+
+func (r *userRepository) UserShareLinkViews(ctx context.Context, token string) ([]*skillz.UserShareLinkView, error) {
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var views = make([]*skillz.UserShareLinkView, 0)
+	for _, view := range r.db.shareViews {
+		if view.Token == token {
+			views = append(views, row(view).(*skillz.UserShareLinkView))
+		}
+	}
+
+	sort.SliceStable(views, func(i, j int) bool {
+		return views[i].ViewedAt.After(views[j].ViewedAt)
+	})
+
+	return views, nil
+
+}
+
+// CreateUserShareLinkView records the view. Like the MySQL repository, the id that
+// the view is stored with is not set on the view
+func (r *userRepository) CreateUserShareLinkView(ctx context.Context, view *skillz.UserShareLinkView) error {
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	view.ViewedAt = time.Now()
+
+	stored := row(view).(*skillz.UserShareLinkView)
+	stored.ID = r.db.nextID(tableUserShareLinkViews)
+
+	r.db.shareViews = append(r.db.shareViews, stored)
+
+	return nil
+
+}
+
+// userRow returns the user as it is selected. The last_processed column is written but never selected
+func userRow(user *skillz.User) *skillz.User {
+	u := row(user).(*skillz.User)
+	u.LastProcessed = null.Time{}
+	return u
+}
+
+// deleteUserRows deletes the rows that reference the user by their id or by their character
+func (db *DB) deleteUserRows(user *skillz.User) {
+
+	delete(db.settings, user.ID)
+	delete(db.accounts, user.ID)
+
+	db.deleteUserShareLinks(func(link *skillz.UserShareLink) bool {
+		return link.UserID == user.ID
+	})
+
+	for _, plan := range append([]*skillz.SkillPlan{}, db.plans...) {
+		if plan.UserID == user.ID {
+			db.deleteSkillPlan(plan.ID)
+		}
+	}
+
+	delete(db.attributes, user.CharacterID)
+	delete(db.meta, user.CharacterID)
+	db.deleteCharacterSkills(user.CharacterID)
+	db.deleteCharacterSkillQueue(user.CharacterID)
+	db.deleteCharacterFlyableShips(user.CharacterID)
+	db.deleteCharacterSkillSnapshots(user.CharacterID)
+	db.deleteCharacterSkillCompletions(user.CharacterID)
+	db.deleteCharacterImplants(user.CharacterID)
+	db.deleteCharacterJumpClones(user.CharacterID)
+	db.deleteCharacterContacts(user.CharacterID)
+
+}
+
+func (db *DB) deleteUserShareLinks(match func(link *skillz.UserShareLink) bool) {
+
+	tokens := make(map[string]bool)
+	links := db.shareLinks[:0]
+	for _, link := range db.shareLinks {
+		if match(link) {
+			tokens[link.Token] = true
+			continue
+		}
+		links = append(links, link)
+	}
+	db.shareLinks = links
+
+	views := db.shareViews[:0]
+	for _, view := range db.shareViews {
+		if !tokens[view.Token] {
+			views = append(views, view)
+		}
+	}
+	db.shareViews = views
+
+}
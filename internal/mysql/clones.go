@@ -11,13 +11,20 @@ import (
 )
 
 type cloneRepository struct {
-	db       QueryExecContext
-	implants tableConf
+	db                QueryExecContext
+	implants          tableConf
+	jumpClones        tableConf
+	jumpCloneImplants tableConf
 }
 
 const (
 	ImplantsImplantID string = "implant_id"
 	ImplantsSlot      string = "slot"
+
+	JumpCloneJumpCloneID  string = "jump_clone_id"
+	JumpCloneName         string = "name"
+	JumpCloneLocationID   string = "location_id"
+	JumpCloneLocationType string = "location_type"
 )
 
 func NewCloneRepository(db QueryExecContext) skillz.CloneRepository {
@@ -29,6 +36,19 @@ func NewCloneRepository(db QueryExecContext) skillz.CloneRepository {
 				ColumnCharacterID, ImplantsImplantID, ImplantsSlot, ColumnCreatedAt,
 			},
 		},
+		jumpClones: tableConf{
+			table: TableCharacterJumpClones,
+			columns: []string{
+				ColumnCharacterID, JumpCloneJumpCloneID, JumpCloneName,
+				JumpCloneLocationID, JumpCloneLocationType, ColumnCreatedAt,
+			},
+		},
+		jumpCloneImplants: tableConf{
+			table: TableCharacterJumpCloneImplants,
+			columns: []string{
+				ColumnCharacterID, JumpCloneJumpCloneID, ImplantsImplantID, ImplantsSlot, ColumnCreatedAt,
+			},
+		},
 	}
 }
 
@@ -80,3 +100,90 @@ func (r *cloneRepository) DeleteCharacterImplants(ctx context.Context, character
 	return err
 
 }
+
+func (r *cloneRepository) CharacterJumpClones(ctx context.Context, characterID uint64) ([]*skillz.CharacterJumpClone, error) {
+
+	query, args, err := sq.Select(r.jumpClones.columns...).
+		From(r.jumpClones.table).
+		Where(sq.Eq{ColumnCharacterID: characterID}).
+		OrderBy(fmt.Sprintf("%s %s", JumpCloneJumpCloneID, "ASC")).
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrapf(err, errorFFormat, cloneRepositoryIdentifier, "CharacterJumpClones", "failed to generate sql")
+	}
+
+	var clones = make([]*skillz.CharacterJumpClone, 0)
+	err = r.db.SelectContext(ctx, &clones, query, args...)
+	return clones, errors.Wrapf(err, prefixFormat, cloneRepositoryIdentifier, "CharacterJumpClones")
+
+}
+
+func (r *cloneRepository) CharacterJumpCloneImplants(ctx context.Context, characterID uint64) ([]*skillz.CharacterJumpCloneImplant, error) {
+
+	query, args, err := sq.Select(r.jumpCloneImplants.columns...).
+		From(r.jumpCloneImplants.table).
+		Where(sq.Eq{ColumnCharacterID: characterID}).
+		Where(sq.LtOrEq{ImplantsSlot: 5}).
+		OrderBy(fmt.Sprintf("%s %s", ImplantsSlot, "ASC")).
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrapf(err, errorFFormat, cloneRepositoryIdentifier, "CharacterJumpCloneImplants", "failed to generate sql")
+	}
+
+	var implants = make([]*skillz.CharacterJumpCloneImplant, 0)
+	err = r.db.SelectContext(ctx, &implants, query, args...)
+	return implants, errors.Wrapf(err, prefixFormat, cloneRepositoryIdentifier, "CharacterJumpCloneImplants")
+
+}
+
+func (r *cloneRepository) CreateCharacterJumpClones(ctx context.Context, clones []*skillz.CharacterJumpClone) error {
+
+	i := sq.Insert(r.jumpClones.table).Columns(r.jumpClones.columns...)
+	now := time.Now()
+	for _, clone := range clones {
+		clone.CreatedAt = now
+		i = i.Values(clone.CharacterID, clone.JumpCloneID, clone.Name, clone.LocationID, clone.LocationType, clone.CreatedAt)
+	}
+
+	query, args, err := i.ToSql()
+	if err != nil {
+		return errors.Wrapf(err, errorFFormat, cloneRepositoryIdentifier, "CreateCharacterJumpClones", "failed to generate sql")
+	}
+
+	_, err = r.db.ExecContext(ctx, query, args...)
+	return errors.Wrapf(err, prefixFormat, cloneRepositoryIdentifier, "CreateCharacterJumpClones")
+
+}
+
+func (r *cloneRepository) CreateCharacterJumpCloneImplants(ctx context.Context, implants []*skillz.CharacterJumpCloneImplant) error {
+
+	i := sq.Insert(r.jumpCloneImplants.table).Columns(r.jumpCloneImplants.columns...)
+	now := time.Now()
+	for _, implant := range implants {
+		implant.CreatedAt = now
+		i = i.Values(implant.CharacterID, implant.JumpCloneID, implant.ImplantID, implant.Slot, implant.CreatedAt)
+	}
+
+	query, args, err := i.ToSql()
+	if err != nil {
+		return errors.Wrapf(err, errorFFormat, cloneRepositoryIdentifier, "CreateCharacterJumpCloneImplants", "failed to generate sql")
+	}
+
+	_, err = r.db.ExecContext(ctx, query, args...)
+	return errors.Wrapf(err, prefixFormat, cloneRepositoryIdentifier, "CreateCharacterJumpCloneImplants")
+
+}
+
+// DeleteCharacterJumpClones deletes the character's jump clones. The implants of
+// the jump clones are deleted along with them by the foreign key
+func (r *cloneRepository) DeleteCharacterJumpClones(ctx context.Context, characterID uint64) error {
+
+	query, args, err := sq.Delete(r.jumpClones.table).Where(sq.Eq{ColumnCharacterID: characterID}).ToSql()
+	if err != nil {
+		return errors.Wrapf(err, errorFFormat, cloneRepositoryIdentifier, "DeleteCharacterJumpClones", "failed to generate sql")
+	}
+
+	_, err = r.db.ExecContext(ctx, query, args...)
+	return errors.Wrapf(err, prefixFormat, cloneRepositoryIdentifier, "DeleteCharacterJumpClones")
+
+}
@@ -15,11 +15,19 @@ const (
 	TableCharacterCorporationHistory string = "character_corporation_history"
 	TableCharacterFlyableShips       string = "character_flyable_ships"
 	TableCharacterImplants           string = "character_implants"
+	TableCharacterJumpClones         string = "character_jump_clones"
+	TableCharacterJumpCloneImplants  string = "character_jump_clone_implants"
 	TableCharacterSkillQueue         string = "character_skillqueue"
 	TableCharacterSkills             string = "character_skills"
 	TableCharacterSkillMeta          string = "character_skill_meta"
+	TableCharacterSkillSnapshots     string = "character_skill_snapshots"
+	TableCharacterSkillChanges       string = "character_skill_changes"
+	TableCharacterSkillCompletions   string = "character_skill_completions"
 	TableCorporations                string = "corporations"
 	TableCorporationAllianceHistory  string = "corporation_alliance_history"
+	TableDoctrines                   string = "doctrines"
+	TableDoctrineFits                string = "doctrine_fits"
+	TableDoctrineFitSkills           string = "doctrine_fit_skills"
 	TableEtags                       string = "etags"
 	TableFactions                    string = "factions"
 	TableMapConstellations           string = "map_constellations"
@@ -28,12 +36,18 @@ const (
 	TableMapStations                 string = "map_stations"
 	TableStructures                  string = "map_structures"
 	TableRaces                       string = "races"
+	TableShipFlightRequirements      string = "ship_flight_requirements"
+	TableSkillPlans                  string = "skill_plans"
+	TableSkillPlanEntries            string = "skill_plan_entries"
 	TableTypes                       string = "types"
 	TableTypeAttributes              string = "type_attributes"
 	TableTypeCategories              string = "type_categories"
 	TableTypeGroups                  string = "type_groups"
 	TableUsers                       string = "users"
+	TableUserAccounts                string = "user_accounts"
 	TableUserSettings                string = "user_settings"
+	TableUserShareLinks              string = "user_share_links"
+	TableUserShareLinkViews          string = "user_share_link_views"
 )
 
 const (
@@ -46,6 +60,7 @@ const (
 	contactRepositoryIdentifier     string = "ContactRepository"
 	cloneRepositoryIdentifier       string = "CloneRepository"
 	corporationRepositoryIdentifier string = "CorporationRepository"
+	doctrineRepositoryIdentifier    string = "DoctrineRepository"
 	etagRepositoryIdentifier        string = "ETagRepository"
 	skillsRepositoryIdentifier      string = "SkillsRepository"
 	universeRepositoryIdentifier    string = "UniverseRepository"
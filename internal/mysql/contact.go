@@ -33,20 +33,20 @@ func NewContactRepository(db QueryExecContext) skillz.ContactRepository {
 	}
 
 }
+
 func (r *contactRepository) CharacterContacts(ctx context.Context, characterID uint64) ([]*skillz.CharacterContact, error) {
 
 	query, args, err := sq.Select(r.contacts.columns...).
 		From(r.contacts.table).
 		Where(sq.Eq{ColumnCharacterID: characterID}).
-		Where(sq.NotEq{ContactContactType: "character"}).
 		ToSql()
 	if err != nil {
-		return nil, errors.Wrapf(err, errorFFormat, cloneRepositoryIdentifier, "CharacterContacts", "failed to generate sql")
+		return nil, errors.Wrapf(err, errorFFormat, contactRepositoryIdentifier, "CharacterContacts", "failed to generate sql")
 	}
 
 	var contacts = make([]*skillz.CharacterContact, 0)
 	err = r.db.SelectContext(ctx, &contacts, query, args...)
-	return contacts, errors.Wrapf(err, prefixFormat, cloneRepositoryIdentifier, "CharacterContacts")
+	return contacts, errors.Wrapf(err, prefixFormat, contactRepositoryIdentifier, "CharacterContacts")
 
 }
 
@@ -71,7 +71,7 @@ func (r *contactRepository) CreateCharacterContacts(ctx context.Context, contact
 	}
 
 	_, err = r.db.ExecContext(ctx, query, args...)
-	return errors.Wrapf(err, prefixFormat, cloneRepositoryIdentifier, "CreateCharacterContacts")
+	return errors.Wrapf(err, prefixFormat, contactRepositoryIdentifier, "CreateCharacterContacts")
 
 }
 
@@ -79,10 +79,10 @@ func (r *contactRepository) DeleteCharacterContacts(ctx context.Context, charact
 
 	query, args, err := sq.Delete(r.contacts.table).Where(sq.Eq{ColumnCharacterID: characterID}).ToSql()
 	if err != nil {
-		return errors.Wrapf(err, errorFFormat, cloneRepositoryIdentifier, "DeleteCharacterContacts", "failed to generate sql")
+		return errors.Wrapf(err, errorFFormat, contactRepositoryIdentifier, "DeleteCharacterContacts", "failed to generate sql")
 	}
 
 	_, err = r.db.ExecContext(ctx, query, args...)
-	return err
+	return errors.Wrapf(err, prefixFormat, contactRepositoryIdentifier, "DeleteCharacterContacts")
 
 }
@@ -0,0 +1,270 @@
+package mysql
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/eveisesi/skillz"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"github.com/volatiletech/null"
+)
+
+type doctrineRepository struct {
+	db        QueryExecContext
+	doctrines tableConf
+	fits      tableConf
+	fitSkills tableConf
+}
+
+const (
+	DoctrineID            string = "id"
+	DoctrineCorporationID string = "corporation_id"
+	DoctrineAllianceID    string = "alliance_id"
+	DoctrineName          string = "name"
+	DoctrineCreatedBy     string = "created_by"
+	DoctrineFitID         string = "id"
+	DoctrineFitDoctrineID string = "doctrine_id"
+	DoctrineFitName       string = "name"
+	DoctrineFitShipTypeID string = "ship_type_id"
+	DoctrineFitSkillFitID string = "fit_id"
+	DoctrineFitSkillID    string = "skill_id"
+	DoctrineFitSkillLevel string = "level"
+)
+
+func NewDoctrineRepository(db QueryExecContext) skillz.DoctrineRepository {
+	return &doctrineRepository{
+		db: db,
+		doctrines: tableConf{
+			table: TableDoctrines,
+			columns: []string{
+				DoctrineID, DoctrineCorporationID, DoctrineAllianceID,
+				DoctrineName, DoctrineCreatedBy,
+				ColumnCreatedAt, ColumnUpdatedAt,
+			},
+		},
+		fits: tableConf{
+			table: TableDoctrineFits,
+			columns: []string{
+				DoctrineFitID, DoctrineFitDoctrineID, DoctrineFitName,
+				DoctrineFitShipTypeID, ColumnCreatedAt,
+			},
+		},
+		fitSkills: tableConf{
+			table: TableDoctrineFitSkills,
+			columns: []string{
+				DoctrineFitSkillFitID, DoctrineFitSkillID, DoctrineFitSkillLevel,
+			},
+		},
+	}
+}
+
+func (r *doctrineRepository) Doctrine(ctx context.Context, id uint) (*skillz.Doctrine, error) {
+
+	query, args, err := sq.Select(r.doctrines.columns...).
+		From(r.doctrines.table).
+		Where(sq.Eq{DoctrineID: id}).
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrapf(err, errorFFormat, doctrineRepositoryIdentifier, "Doctrine", "failed to generate sql")
+	}
+
+	var doctrine = new(skillz.Doctrine)
+	err = r.db.GetContext(ctx, doctrine, query, args...)
+	return doctrine, errors.Wrapf(err, prefixFormat, doctrineRepositoryIdentifier, "Doctrine")
+
+}
+
+// Doctrines returns the doctrines of the corporation along with the alliance wide doctrines of the alliance
+func (r *doctrineRepository) Doctrines(ctx context.Context, corporationID uint, allianceID null.Uint) ([]*skillz.Doctrine, error) {
+
+	where := sq.Or{sq.Eq{DoctrineCorporationID: corporationID}}
+	if allianceID.Valid {
+		where = append(where, sq.Eq{DoctrineAllianceID: allianceID.Uint})
+	}
+
+	query, args, err := sq.Select(r.doctrines.columns...).
+		From(r.doctrines.table).
+		Where(where).
+		OrderBy(DoctrineName).
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrapf(err, errorFFormat, doctrineRepositoryIdentifier, "Doctrines", "failed to generate sql")
+	}
+
+	var doctrines = make([]*skillz.Doctrine, 0)
+	err = r.db.SelectContext(ctx, &doctrines, query, args...)
+	return doctrines, errors.Wrapf(err, prefixFormat, doctrineRepositoryIdentifier, "Doctrines")
+
+}
+
+func (r *doctrineRepository) CreateDoctrine(ctx context.Context, doctrine *skillz.Doctrine) error {
+
+	now := time.Now()
+	doctrine.CreatedAt = now
+	doctrine.UpdatedAt = now
+
+	query, args, err := sq.Insert(r.doctrines.table).SetMap(map[string]interface{}{
+		DoctrineCorporationID: doctrine.CorporationID,
+		DoctrineAllianceID:    doctrine.AllianceID,
+		DoctrineName:          doctrine.Name,
+		DoctrineCreatedBy:     doctrine.CreatedBy,
+		ColumnCreatedAt:       doctrine.CreatedAt,
+		ColumnUpdatedAt:       doctrine.UpdatedAt,
+	}).ToSql()
+	if err != nil {
+		return errors.Wrapf(err, errorFFormat, doctrineRepositoryIdentifier, "CreateDoctrine", "failed to generate sql")
+	}
+
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return errors.Wrapf(err, prefixFormat, doctrineRepositoryIdentifier, "CreateDoctrine")
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return errors.Wrapf(err, errorFFormat, doctrineRepositoryIdentifier, "CreateDoctrine", "failed to fetch last insert id")
+	}
+
+	doctrine.ID = uint(id)
+
+	return nil
+
+}
+
+func (r *doctrineRepository) DeleteDoctrine(ctx context.Context, id uint) error {
+
+	query, args, err := sq.Delete(r.doctrines.table).Where(sq.Eq{DoctrineID: id}).ToSql()
+	if err != nil {
+		return errors.Wrapf(err, errorFFormat, doctrineRepositoryIdentifier, "DeleteDoctrine", "failed to generate sql")
+	}
+
+	_, err = r.db.ExecContext(ctx, query, args...)
+	return errors.Wrapf(err, prefixFormat, doctrineRepositoryIdentifier, "DeleteDoctrine")
+
+}
+
+func (r *doctrineRepository) DoctrineFits(ctx context.Context, doctrineID uint) ([]*skillz.DoctrineFit, error) {
+
+	query, args, err := sq.Select(r.fits.columns...).
+		From(r.fits.table).
+		Where(sq.Eq{DoctrineFitDoctrineID: doctrineID}).
+		OrderBy(DoctrineFitID).
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrapf(err, errorFFormat, doctrineRepositoryIdentifier, "DoctrineFits", "failed to generate sql")
+	}
+
+	var fits = make([]*skillz.DoctrineFit, 0)
+	err = r.db.SelectContext(ctx, &fits, query, args...)
+	return fits, errors.Wrapf(err, prefixFormat, doctrineRepositoryIdentifier, "DoctrineFits")
+
+}
+
+func (r *doctrineRepository) CreateDoctrineFit(ctx context.Context, fit *skillz.DoctrineFit) error {
+
+	fit.CreatedAt = time.Now()
+
+	query, args, err := sq.Insert(r.fits.table).SetMap(map[string]interface{}{
+		DoctrineFitDoctrineID: fit.DoctrineID,
+		DoctrineFitName:       fit.Name,
+		DoctrineFitShipTypeID: fit.ShipTypeID,
+		ColumnCreatedAt:       fit.CreatedAt,
+	}).ToSql()
+	if err != nil {
+		return errors.Wrapf(err, errorFFormat, doctrineRepositoryIdentifier, "CreateDoctrineFit", "failed to generate sql")
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrapf(err, errorFFormat, doctrineRepositoryIdentifier, "CreateDoctrineFit", "failed to begin transaction")
+	}
+
+	// Rollback is a no-op once the transaction has been committed
+	defer func() { _ = tx.Rollback() }()
+
+	result, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return errors.Wrapf(err, prefixFormat, doctrineRepositoryIdentifier, "CreateDoctrineFit")
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return errors.Wrapf(err, errorFFormat, doctrineRepositoryIdentifier, "CreateDoctrineFit", "failed to fetch last insert id")
+	}
+
+	if len(fit.Requirements) > 0 {
+		skills := make([]*skillz.DoctrineFitSkill, 0, len(fit.Requirements))
+		for _, requirement := range fit.Requirements {
+			skills = append(skills, &skillz.DoctrineFitSkill{
+				FitID:   uint(id),
+				SkillID: requirement.SkillID,
+				Level:   requirement.Level,
+			})
+		}
+
+		err = r.createDoctrineFitSkills(ctx, tx, skills)
+		if err != nil {
+			return errors.Wrapf(err, prefixFormat, doctrineRepositoryIdentifier, "CreateDoctrineFit")
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.Wrapf(err, errorFFormat, doctrineRepositoryIdentifier, "CreateDoctrineFit", "failed to commit transaction")
+	}
+
+	fit.ID = uint(id)
+
+	return nil
+
+}
+
+func (r *doctrineRepository) DeleteDoctrineFit(ctx context.Context, doctrineID, fitID uint) error {
+
+	query, args, err := sq.Delete(r.fits.table).
+		Where(sq.Eq{DoctrineFitDoctrineID: doctrineID, DoctrineFitID: fitID}).
+		ToSql()
+	if err != nil {
+		return errors.Wrapf(err, errorFFormat, doctrineRepositoryIdentifier, "DeleteDoctrineFit", "failed to generate sql")
+	}
+
+	_, err = r.db.ExecContext(ctx, query, args...)
+	return errors.Wrapf(err, prefixFormat, doctrineRepositoryIdentifier, "DeleteDoctrineFit")
+
+}
+
+func (r *doctrineRepository) DoctrineFitSkills(ctx context.Context, fitIDs ...uint) ([]*skillz.DoctrineFitSkill, error) {
+
+	query, args, err := sq.Select(r.fitSkills.columns...).
+		From(r.fitSkills.table).
+		Where(sq.Eq{DoctrineFitSkillFitID: fitIDs}).
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrapf(err, errorFFormat, doctrineRepositoryIdentifier, "DoctrineFitSkills", "failed to generate sql")
+	}
+
+	var skills = make([]*skillz.DoctrineFitSkill, 0)
+	err = r.db.SelectContext(ctx, &skills, query, args...)
+	return skills, errors.Wrapf(err, prefixFormat, doctrineRepositoryIdentifier, "DoctrineFitSkills")
+
+}
+
+func (r *doctrineRepository) createDoctrineFitSkills(ctx context.Context, db sqlx.ExecerContext, skills []*skillz.DoctrineFitSkill) error {
+
+	i := sq.Insert(r.fitSkills.table).Columns(r.fitSkills.columns...)
+	for _, skill := range skills {
+		i = i.Values(skill.FitID, skill.SkillID, skill.Level)
+	}
+	i = i.Suffix(OnDuplicateKeyStmt(DoctrineFitSkillLevel))
+
+	query, args, err := i.ToSql()
+	if err != nil {
+		return errors.Wrapf(err, errorFFormat, doctrineRepositoryIdentifier, "createDoctrineFitSkills", "failed to generate sql")
+	}
+
+	_, err = db.ExecContext(ctx, query, args...)
+	return errors.Wrapf(err, prefixFormat, doctrineRepositoryIdentifier, "createDoctrineFitSkills")
+
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 
+	"github.com/jmoiron/sqlx"
 	"github.com/sirupsen/logrus"
 )
 
@@ -11,6 +12,7 @@ type QueryExecContext interface {
 	SelectContext(context.Context, interface{}, string, ...interface{}) error
 	GetContext(context.Context, interface{}, string, ...interface{}) error
 	ExecContext(context.Context, string, ...interface{}) (sql.Result, error)
+	BeginTxx(context.Context, *sql.TxOptions) (*sqlx.Tx, error)
 }
 
 type queryLogger struct {
@@ -39,3 +41,8 @@ func (s *queryLogger) ExecContext(ctx context.Context, query string, args ...int
 	s.logger.WithField("service", "mysql").Debug(query)
 	return s.queryExecr.ExecContext(ctx, query, args...)
 }
+
+func (s *queryLogger) BeginTxx(ctx context.Context, opts *sql.TxOptions) (*sqlx.Tx, error) {
+	s.logger.WithField("service", "mysql").Debug("BEGIN")
+	return s.queryExecr.BeginTxx(ctx, opts)
+}
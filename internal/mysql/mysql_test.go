@@ -0,0 +1,91 @@
+package mysql_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/eveisesi/skillz/internal/mysql"
+	"github.com/eveisesi/skillz/internal/repotest"
+	driver "github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+)
+
+// TestRepositories runs the conformance suite against the database at MYSQL_TEST_DSN, which must have
+// been migrated with skillz migrate up. The migrations reference the skillboard schema, so the database
+// must be named skillboard. Every table but the migrations table is truncated before each test
+func TestRepositories(t *testing.T) {
+
+	dsn := os.Getenv("MYSQL_TEST_DSN")
+	if dsn == "" {
+		t.Skip("MYSQL_TEST_DSN is not set")
+	}
+
+	config, err := driver.ParseDSN(dsn)
+	if err != nil {
+		t.Fatalf("failed to parse MYSQL_TEST_DSN: %s", err)
+	}
+
+	config.ParseTime = true
+	config.Loc = time.UTC
+
+	db, err := sqlx.Open("mysql", config.FormatDSN())
+	if err != nil {
+		t.Fatalf("failed to connect to mysql: %s", err)
+	}
+	defer db.Close()
+
+	repotest.Run(t, func(t *testing.T) *repotest.Repositories {
+		truncate(t, db)
+
+		return &repotest.Repositories{
+			Alliance:    mysql.NewAllianceRepository(db),
+			Character:   mysql.NewCharacterRepository(db),
+			Clone:       mysql.NewCloneRepository(db),
+			Contact:     mysql.NewContactRepository(db),
+			Corporation: mysql.NewCorporationRepository(db),
+			Etag:        mysql.NewETagRepository(db),
+			Skill:       mysql.NewSkillRepository(db),
+			Universe:    mysql.NewUniverseRepository(db),
+			User:        mysql.NewUserRepository(db),
+		}
+	})
+
+}
+
+func truncate(t *testing.T, db *sqlx.DB) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	// FOREIGN_KEY_CHECKS is a session variable, so the tables are truncated over a single connection
+	conn, err := db.Connx(ctx)
+	if err != nil {
+		t.Fatalf("failed to acquire a connection: %s", err)
+	}
+	defer conn.Close()
+
+	var tables []string
+	err = conn.SelectContext(ctx, &tables, "SHOW TABLES")
+	if err != nil {
+		t.Fatalf("failed to list tables: %s", err)
+	}
+
+	exec := func(query string) {
+		if _, err := conn.ExecContext(ctx, query); err != nil {
+			t.Fatalf("failed to execute %q: %s", query, err)
+		}
+	}
+
+	exec("SET FOREIGN_KEY_CHECKS = 0")
+	for _, table := range tables {
+		if table == "migrations" {
+			continue
+		}
+		exec(fmt.Sprintf("TRUNCATE TABLE `%s`", table))
+	}
+	exec("SET FOREIGN_KEY_CHECKS = 1")
+
+}
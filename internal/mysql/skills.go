@@ -6,6 +6,7 @@ import (
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/eveisesi/skillz"
+	"github.com/jmoiron/sqlx"
 	"github.com/pkg/errors"
 )
 
@@ -13,6 +14,9 @@ type skillRepository struct {
 	db                        QueryExecContext
 	attributes, flyable, meta tableConf
 	skills, queue             tableConf
+	plans, planEntries        tableConf
+	snapshots, changes        tableConf
+	completions               tableConf
 }
 
 const (
@@ -43,6 +47,30 @@ const (
 	AttributesAccruedRemapCooldownDate string = "accrued_remap_cooldown_date"
 
 	FlyableShipTypeID string = "ship_type_id"
+
+	PlanID     string = "id"
+	PlanUserID string = "user_id"
+	PlanName   string = "name"
+
+	PlanEntryPlanID   string = "plan_id"
+	PlanEntryPosition string = "position"
+	PlanEntrySkillID  string = "skill_id"
+	PlanEntryLevel    string = "level"
+
+	SnapshotID            string = "id"
+	SnapshotTotalSP       string = "total_sp"
+	SnapshotUnallocatedSP string = "unallocated_sp"
+
+	ChangeSnapshotID          string = "snapshot_id"
+	ChangeSkillID             string = "skill_id"
+	ChangePreviousSkillLevel  string = "previous_skill_level"
+	ChangeTrainedSkillLevel   string = "trained_skill_level"
+	ChangePreviousSkillpoints string = "previous_skillpoints"
+	ChangeSkillpointsInSkill  string = "skillpoints_in_skill"
+
+	CompletionSkillID     string = "skill_id"
+	CompletionLevel       string = "level"
+	CompletionCompletedAt string = "completed_at"
 )
 
 func NewSkillRepository(db QueryExecContext) skillz.CharacterSkillRepository {
@@ -94,6 +122,46 @@ func NewSkillRepository(db QueryExecContext) skillz.CharacterSkillRepository {
 				ColumnCharacterID, ColumnCreatedAt,
 			},
 		},
+		plans: tableConf{
+			table: TableSkillPlans,
+			columns: []string{
+				PlanID, PlanUserID, PlanName,
+				ColumnCreatedAt, ColumnUpdatedAt,
+			},
+		},
+		planEntries: tableConf{
+			table: TableSkillPlanEntries,
+			columns: []string{
+				PlanEntryPlanID, PlanEntryPosition,
+				PlanEntrySkillID, PlanEntryLevel,
+				ColumnCreatedAt,
+			},
+		},
+		snapshots: tableConf{
+			table: TableCharacterSkillSnapshots,
+			columns: []string{
+				SnapshotID, ColumnCharacterID,
+				SnapshotTotalSP, SnapshotUnallocatedSP,
+				ColumnCreatedAt,
+			},
+		},
+		changes: tableConf{
+			table: TableCharacterSkillChanges,
+			columns: []string{
+				ChangeSnapshotID, ColumnCharacterID, ChangeSkillID,
+				ChangePreviousSkillLevel, ChangeTrainedSkillLevel,
+				ChangePreviousSkillpoints, ChangeSkillpointsInSkill,
+				ColumnCreatedAt,
+			},
+		},
+		completions: tableConf{
+			table: TableCharacterSkillCompletions,
+			columns: []string{
+				ColumnCharacterID, CompletionSkillID,
+				CompletionLevel, CompletionCompletedAt,
+				ColumnCreatedAt,
+			},
+		},
 	}
 
 }
@@ -411,3 +479,351 @@ func (r *skillRepository) DeleteCharacterSkillQueue(ctx context.Context, charact
 	return errors.Wrapf(err, prefixFormat, skillsRepositoryIdentifier, "DeleteCharacterSkillQueue")
 
 }
+
+func (r *skillRepository) SkillPlan(ctx context.Context, id uint) (*skillz.SkillPlan, error) {
+
+	query, args, err := sq.Select(r.plans.columns...).
+		From(r.plans.table).
+		Where(sq.Eq{PlanID: id}).
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrapf(err, errorFFormat, skillsRepositoryIdentifier, "SkillPlan", "failed to generate sql")
+	}
+
+	var plan = new(skillz.SkillPlan)
+	err = r.db.GetContext(ctx, plan, query, args...)
+	return plan, errors.Wrapf(err, prefixFormat, skillsRepositoryIdentifier, "SkillPlan")
+
+}
+
+const planNameAscOrderByStmt = "name ASC"
+
+func (r *skillRepository) SkillPlansByUserID(ctx context.Context, userID string) ([]*skillz.SkillPlan, error) {
+
+	query, args, err := sq.Select(r.plans.columns...).
+		From(r.plans.table).
+		Where(sq.Eq{PlanUserID: userID}).
+		OrderBy(planNameAscOrderByStmt).
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrapf(err, errorFFormat, skillsRepositoryIdentifier, "SkillPlansByUserID", "failed to generate sql")
+	}
+
+	var plans = make([]*skillz.SkillPlan, 0, 10)
+	err = r.db.SelectContext(ctx, &plans, query, args...)
+	return plans, errors.Wrapf(err, prefixFormat, skillsRepositoryIdentifier, "SkillPlansByUserID")
+
+}
+
+func (r *skillRepository) CreateSkillPlan(ctx context.Context, plan *skillz.SkillPlan) error {
+
+	now := time.Now()
+	plan.CreatedAt = now
+	plan.UpdatedAt = now
+
+	query, args, err := sq.Insert(r.plans.table).SetMap(map[string]interface{}{
+		PlanUserID:      plan.UserID,
+		PlanName:        plan.Name,
+		ColumnCreatedAt: plan.CreatedAt,
+		ColumnUpdatedAt: plan.UpdatedAt,
+	}).ToSql()
+	if err != nil {
+		return errors.Wrapf(err, errorFFormat, skillsRepositoryIdentifier, "CreateSkillPlan", "failed to generate sql")
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrapf(err, errorFFormat, skillsRepositoryIdentifier, "CreateSkillPlan", "failed to begin transaction")
+	}
+
+	// Rollback is a no-op once the transaction has been committed
+	defer func() { _ = tx.Rollback() }()
+
+	result, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return errors.Wrapf(err, prefixFormat, skillsRepositoryIdentifier, "CreateSkillPlan")
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return errors.Wrapf(err, errorFFormat, skillsRepositoryIdentifier, "CreateSkillPlan", "failed to fetch last insert id")
+	}
+
+	if len(plan.Entries) > 0 {
+		for _, entry := range plan.Entries {
+			entry.PlanID = uint(id)
+		}
+
+		err = r.createSkillPlanEntries(ctx, tx, plan.Entries)
+		if err != nil {
+			return errors.Wrapf(err, prefixFormat, skillsRepositoryIdentifier, "CreateSkillPlan")
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.Wrapf(err, errorFFormat, skillsRepositoryIdentifier, "CreateSkillPlan", "failed to commit transaction")
+	}
+
+	plan.ID = uint(id)
+
+	return nil
+
+}
+
+func (r *skillRepository) UpdateSkillPlan(ctx context.Context, plan *skillz.SkillPlan) error {
+
+	plan.UpdatedAt = time.Now()
+	query, args, err := sq.Update(r.plans.table).SetMap(map[string]interface{}{
+		PlanName:        plan.Name,
+		ColumnUpdatedAt: plan.UpdatedAt,
+	}).Where(sq.Eq{PlanID: plan.ID}).ToSql()
+	if err != nil {
+		return errors.Wrapf(err, errorFFormat, skillsRepositoryIdentifier, "UpdateSkillPlan", "failed to generate sql")
+	}
+
+	_, err = r.db.ExecContext(ctx, query, args...)
+	return errors.Wrapf(err, prefixFormat, skillsRepositoryIdentifier, "UpdateSkillPlan")
+
+}
+
+func (r *skillRepository) DeleteSkillPlan(ctx context.Context, id uint) error {
+
+	query, args, err := sq.Delete(r.plans.table).Where(sq.Eq{PlanID: id}).ToSql()
+	if err != nil {
+		return errors.Wrapf(err, errorFFormat, skillsRepositoryIdentifier, "DeleteSkillPlan", "failed to generate sql")
+	}
+
+	_, err = r.db.ExecContext(ctx, query, args...)
+	return errors.Wrapf(err, prefixFormat, skillsRepositoryIdentifier, "DeleteSkillPlan")
+
+}
+
+const planPositionAscOrderByStmt = "position ASC"
+
+func (r *skillRepository) SkillPlanEntries(ctx context.Context, planID uint) ([]*skillz.SkillPlanEntry, error) {
+
+	query, args, err := sq.Select(r.planEntries.columns...).
+		From(r.planEntries.table).
+		Where(sq.Eq{PlanEntryPlanID: planID}).
+		OrderBy(planPositionAscOrderByStmt).
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrapf(err, errorFFormat, skillsRepositoryIdentifier, "SkillPlanEntries", "failed to generate sql")
+	}
+
+	var entries = make([]*skillz.SkillPlanEntry, 0, 50)
+	err = r.db.SelectContext(ctx, &entries, query, args...)
+	return entries, errors.Wrapf(err, prefixFormat, skillsRepositoryIdentifier, "SkillPlanEntries")
+
+}
+
+func (r *skillRepository) CreateSkillPlanEntries(ctx context.Context, entries []*skillz.SkillPlanEntry) error {
+	return errors.Wrapf(r.createSkillPlanEntries(ctx, r.db, entries), prefixFormat, skillsRepositoryIdentifier, "CreateSkillPlanEntries")
+}
+
+func (r *skillRepository) DeleteSkillPlanEntries(ctx context.Context, planID uint) error {
+	return errors.Wrapf(r.deleteSkillPlanEntries(ctx, r.db, planID), prefixFormat, skillsRepositoryIdentifier, "DeleteSkillPlanEntries")
+}
+
+func (r *skillRepository) ReplaceSkillPlanEntries(ctx context.Context, planID uint, entries []*skillz.SkillPlanEntry) error {
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrapf(err, errorFFormat, skillsRepositoryIdentifier, "ReplaceSkillPlanEntries", "failed to begin transaction")
+	}
+
+	// Rollback is a no-op once the transaction has been committed
+	defer func() { _ = tx.Rollback() }()
+
+	err = r.deleteSkillPlanEntries(ctx, tx, planID)
+	if err != nil {
+		return errors.Wrapf(err, prefixFormat, skillsRepositoryIdentifier, "ReplaceSkillPlanEntries")
+	}
+
+	if len(entries) > 0 {
+		err = r.createSkillPlanEntries(ctx, tx, entries)
+		if err != nil {
+			return errors.Wrapf(err, prefixFormat, skillsRepositoryIdentifier, "ReplaceSkillPlanEntries")
+		}
+	}
+
+	return errors.Wrapf(tx.Commit(), errorFFormat, skillsRepositoryIdentifier, "ReplaceSkillPlanEntries", "failed to commit transaction")
+
+}
+
+func (r *skillRepository) createSkillPlanEntries(ctx context.Context, db sqlx.ExecerContext, entries []*skillz.SkillPlanEntry) error {
+
+	now := time.Now()
+
+	i := sq.Insert(r.planEntries.table).Columns(r.planEntries.columns...)
+	for _, entry := range entries {
+		entry.CreatedAt = now
+
+		i = i.Values(
+			entry.PlanID, entry.Position,
+			entry.SkillID, entry.Level,
+			entry.CreatedAt,
+		)
+	}
+
+	query, args, err := i.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed to generate sql")
+	}
+
+	_, err = db.ExecContext(ctx, query, args...)
+	return err
+
+}
+
+func (r *skillRepository) deleteSkillPlanEntries(ctx context.Context, db sqlx.ExecerContext, planID uint) error {
+
+	query, args, err := sq.Delete(r.planEntries.table).
+		Where(sq.Eq{PlanEntryPlanID: planID}).
+		ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed to generate sql")
+	}
+
+	_, err = db.ExecContext(ctx, query, args...)
+	return err
+
+}
+
+func (r *skillRepository) CharacterSkillSnapshots(ctx context.Context, characterID uint64, from, to time.Time) ([]*skillz.CharacterSkillSnapshot, error) {
+
+	query, args, err := sq.Select(r.snapshots.columns...).
+		From(r.snapshots.table).
+		Where(sq.Eq{ColumnCharacterID: characterID}).
+		Where(sq.GtOrEq{ColumnCreatedAt: from}).
+		Where(sq.LtOrEq{ColumnCreatedAt: to}).
+		OrderBy(ColumnCreatedAt).
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrapf(err, errorFFormat, skillsRepositoryIdentifier, "CharacterSkillSnapshots", "failed to generate sql")
+	}
+
+	var snapshots = make([]*skillz.CharacterSkillSnapshot, 0)
+	err = r.db.SelectContext(ctx, &snapshots, query, args...)
+	return snapshots, errors.Wrapf(err, prefixFormat, skillsRepositoryIdentifier, "CharacterSkillSnapshots")
+
+}
+
+func (r *skillRepository) CreateCharacterSkillSnapshot(ctx context.Context, snapshot *skillz.CharacterSkillSnapshot) error {
+
+	snapshot.CreatedAt = time.Now()
+
+	query, args, err := sq.Insert(r.snapshots.table).SetMap(map[string]interface{}{
+		ColumnCharacterID:     snapshot.CharacterID,
+		SnapshotTotalSP:       snapshot.TotalSP,
+		SnapshotUnallocatedSP: snapshot.UnallocatedSP,
+		ColumnCreatedAt:       snapshot.CreatedAt,
+	}).ToSql()
+	if err != nil {
+		return errors.Wrapf(err, errorFFormat, skillsRepositoryIdentifier, "CreateCharacterSkillSnapshot", "failed to generate sql")
+	}
+
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return errors.Wrapf(err, prefixFormat, skillsRepositoryIdentifier, "CreateCharacterSkillSnapshot")
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return errors.Wrapf(err, errorFFormat, skillsRepositoryIdentifier, "CreateCharacterSkillSnapshot", "failed to fetch last insert id")
+	}
+
+	snapshot.ID = uint64(id)
+
+	return nil
+
+}
+
+func (r *skillRepository) CharacterSkillChanges(ctx context.Context, characterID uint64, from, to time.Time) ([]*skillz.CharacterSkillChange, error) {
+
+	query, args, err := sq.Select(r.changes.columns...).
+		From(r.changes.table).
+		Where(sq.Eq{ColumnCharacterID: characterID}).
+		Where(sq.GtOrEq{ColumnCreatedAt: from}).
+		Where(sq.LtOrEq{ColumnCreatedAt: to}).
+		OrderBy(ColumnCreatedAt, ChangeSkillID).
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrapf(err, errorFFormat, skillsRepositoryIdentifier, "CharacterSkillChanges", "failed to generate sql")
+	}
+
+	var changes = make([]*skillz.CharacterSkillChange, 0)
+	err = r.db.SelectContext(ctx, &changes, query, args...)
+	return changes, errors.Wrapf(err, prefixFormat, skillsRepositoryIdentifier, "CharacterSkillChanges")
+
+}
+
+func (r *skillRepository) CreateCharacterSkillChanges(ctx context.Context, changes []*skillz.CharacterSkillChange) error {
+
+	now := time.Now()
+
+	i := sq.Insert(r.changes.table).Columns(r.changes.columns...)
+	for _, change := range changes {
+		change.CreatedAt = now
+		i = i.Values(
+			change.SnapshotID, change.CharacterID, change.SkillID,
+			change.PreviousSkillLevel, change.TrainedSkillLevel,
+			change.PreviousSkillpoints, change.SkillpointsInSkill,
+			change.CreatedAt,
+		)
+	}
+
+	query, args, err := i.ToSql()
+	if err != nil {
+		return errors.Wrapf(err, errorFFormat, skillsRepositoryIdentifier, "CreateCharacterSkillChanges", "failed to generate sql")
+	}
+
+	_, err = r.db.ExecContext(ctx, query, args...)
+	return errors.Wrapf(err, prefixFormat, skillsRepositoryIdentifier, "CreateCharacterSkillChanges")
+
+}
+
+func (r *skillRepository) CharacterSkillCompletions(ctx context.Context, characterID uint64, limit uint64) ([]*skillz.CharacterSkillCompletion, error) {
+
+	query, args, err := sq.Select(r.completions.columns...).
+		From(r.completions.table).
+		Where(sq.Eq{ColumnCharacterID: characterID}).
+		OrderBy(CompletionCompletedAt + " DESC").
+		Limit(limit).
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrapf(err, errorFFormat, skillsRepositoryIdentifier, "CharacterSkillCompletions", "failed to generate sql")
+	}
+
+	var completions = make([]*skillz.CharacterSkillCompletion, 0)
+	err = r.db.SelectContext(ctx, &completions, query, args...)
+	return completions, errors.Wrapf(err, prefixFormat, skillsRepositoryIdentifier, "CharacterSkillCompletions")
+
+}
+
+// CreateCharacterSkillCompletions ignores completions that have already been recorded, so
+// the same completion can be detected on consecutive runs without being duplicated
+func (r *skillRepository) CreateCharacterSkillCompletions(ctx context.Context, completions []*skillz.CharacterSkillCompletion) error {
+
+	now := time.Now()
+
+	i := sq.Insert(r.completions.table).Options("IGNORE").Columns(r.completions.columns...)
+	for _, completion := range completions {
+		completion.CreatedAt = now
+		i = i.Values(
+			completion.CharacterID, completion.SkillID,
+			completion.Level, completion.CompletedAt,
+			completion.CreatedAt,
+		)
+	}
+
+	query, args, err := i.ToSql()
+	if err != nil {
+		return errors.Wrapf(err, errorFFormat, skillsRepositoryIdentifier, "CreateCharacterSkillCompletions", "failed to generate sql")
+	}
+
+	_, err = r.db.ExecContext(ctx, query, args...)
+	return errors.Wrapf(err, prefixFormat, skillsRepositoryIdentifier, "CreateCharacterSkillCompletions")
+
+}
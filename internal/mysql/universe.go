@@ -21,6 +21,8 @@ type universeRepository struct {
 	stations, structures tableConf
 
 	categories, groups, types, typeAttributes tableConf
+
+	shipFlightRequirements tableConf
 }
 
 const (
@@ -103,6 +105,11 @@ const (
 	TypeDogmaAttributesTypeID     string = "type_id"
 	TypeDogmaAttributeAttributeID string = "attribute_id"
 	TypeDogmaAttributeValue       string = "value"
+
+	ShipFlightRequirementGroupID           string = "group_id"
+	ShipFlightRequirementShipID            string = "ship_id"
+	ShipFlightRequirementSkillID           string = "skill_id"
+	ShipFlightRequirementMinimumSkillLevel string = "minimum_skill_level"
 )
 
 func NewUniverseRepository(db QueryExecContext) skillz.UniverseRepository {
@@ -254,6 +261,14 @@ func NewUniverseRepository(db QueryExecContext) skillz.UniverseRepository {
 				TypeDogmaAttributeValue, ColumnCreatedAt,
 			},
 		},
+		shipFlightRequirements: tableConf{
+			table: TableShipFlightRequirements,
+			columns: []string{
+				ShipFlightRequirementGroupID, ShipFlightRequirementShipID,
+				ShipFlightRequirementSkillID, ShipFlightRequirementMinimumSkillLevel,
+				ColumnCreatedAt,
+			},
+		},
 	}
 }
 
@@ -1249,3 +1264,48 @@ func (r *universeRepository) DeleteTypeDogmaAttributes(ctx context.Context, type
 	return errors.Wrapf(err, prefixFormat, universeRepositoryIdentifier, "DeleteTypeDogmaAttributes")
 
 }
+
+func (r *universeRepository) ShipFlightRequirements(ctx context.Context, shipID uint) ([]*skillz.ShipFlightRequirement, error) {
+
+	query, args, err := sq.Select(r.shipFlightRequirements.columns...).From(r.shipFlightRequirements.table).
+		Where(sq.Eq{ShipFlightRequirementShipID: shipID}).ToSql()
+	if err != nil {
+		return nil, errors.Wrapf(err, errorFFormat, universeRepositoryIdentifier, "ShipFlightRequirements", "failed to generate sql")
+	}
+
+	var requirements = make([]*skillz.ShipFlightRequirement, 0)
+	err = r.db.SelectContext(ctx, &requirements, query, args...)
+	return requirements, errors.Wrapf(err, prefixFormat, universeRepositoryIdentifier, "ShipFlightRequirements")
+
+}
+
+func (r *universeRepository) CreateShipFlightRequirements(ctx context.Context, requirements []*skillz.ShipFlightRequirement) error {
+
+	now := time.Now()
+	i := sq.Insert(r.shipFlightRequirements.table).Columns(r.shipFlightRequirements.columns...)
+	for _, requirement := range requirements {
+		requirement.CreatedAt = now
+		i = i.Values(requirement.GroupID, requirement.ShipID, requirement.SkillID, requirement.MinimumSkillLevel, requirement.CreatedAt)
+	}
+
+	query, args, err := i.Suffix(OnDuplicateKeyStmt(ShipFlightRequirementGroupID, ShipFlightRequirementMinimumSkillLevel)).ToSql()
+	if err != nil {
+		return errors.Wrapf(err, errorFFormat, universeRepositoryIdentifier, "CreateShipFlightRequirements", "failed to generate sql")
+	}
+
+	_, err = r.db.ExecContext(ctx, query, args...)
+	return errors.Wrapf(err, prefixFormat, universeRepositoryIdentifier, "CreateShipFlightRequirements")
+
+}
+
+func (r *universeRepository) DeleteShipFlightRequirements(ctx context.Context, shipID uint) error {
+
+	query, args, err := sq.Delete(r.shipFlightRequirements.table).Where(sq.Eq{ShipFlightRequirementShipID: shipID}).ToSql()
+	if err != nil {
+		return errors.Wrapf(err, errorFFormat, universeRepositoryIdentifier, "DeleteShipFlightRequirements", "failed to generate sql")
+	}
+
+	_, err = r.db.ExecContext(ctx, query, args...)
+	return errors.Wrapf(err, prefixFormat, universeRepositoryIdentifier, "DeleteShipFlightRequirements")
+
+}
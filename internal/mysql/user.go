@@ -8,12 +8,16 @@ import (
 	sq "github.com/Masterminds/squirrel"
 	"github.com/eveisesi/skillz"
 	"github.com/pkg/errors"
+	"github.com/volatiletech/null"
 )
 
 type userRepository struct {
 	db       QueryExecContext
 	users    tableConf
 	settings tableConf
+	accounts tableConf
+	shares   tableConf
+	views    tableConf
 }
 
 const (
@@ -39,6 +43,25 @@ const (
 	SettingsHideFlyable     = "hide_flyable"
 	SettingsHideAttributes  = "hide_attributes"
 	SettingsHideImplants    = "hide_implants"
+	SettingsHideStandings   = "hide_standings"
+	SettingsShareWithCorp   = "share_with_corporation"
+	AccountUserID           = "user_id"
+	AccountAccountID        = "account_id"
+	AccountOwnerHash        = "owner_hash"
+	ShareToken              = "token"
+	ShareUserID             = "user_id"
+	ShareLabel              = "label"
+	ShareSkills             = "share_skills"
+	ShareQueue              = "share_queue"
+	ShareImplants           = "share_implants"
+	ShareFlyable            = "share_flyable"
+	ShareExpiresAt          = "expires_at"
+	ShareViewID             = "id"
+	ShareViewToken          = "token"
+	ShareViewViewerID       = "viewer_id"
+	ShareViewIPAddress      = "ip_address"
+	ShareViewUserAgent      = "user_agent"
+	ShareViewViewedAt       = "viewed_at"
 )
 
 func NewUserRepository(db QueryExecContext) skillz.UserRepository {
@@ -62,9 +85,31 @@ func NewUserRepository(db QueryExecContext) skillz.UserRepository {
 				SettingsVisibilityToken, SettingsHideQueue,
 				SettingsHideFlyable, SettingsHideSkills,
 				SettingsHideAttributes, SettingsHideImplants,
+				SettingsHideStandings, SettingsShareWithCorp,
 				ColumnCreatedAt, ColumnUpdatedAt,
 			},
 		},
+		accounts: tableConf{
+			table: TableUserAccounts,
+			columns: []string{
+				AccountUserID, AccountAccountID, AccountOwnerHash, ColumnCreatedAt,
+			},
+		},
+		shares: tableConf{
+			table: TableUserShareLinks,
+			columns: []string{
+				ShareToken, ShareUserID, ShareLabel,
+				ShareSkills, ShareQueue, ShareImplants, ShareFlyable,
+				ShareExpiresAt, ColumnCreatedAt,
+			},
+		},
+		views: tableConf{
+			table: TableUserShareLinkViews,
+			columns: []string{
+				ShareViewID, ShareViewToken, ShareViewViewerID,
+				ShareViewIPAddress, ShareViewUserAgent, ShareViewViewedAt,
+			},
+		},
 	}
 }
 
@@ -233,6 +278,38 @@ func (r *userRepository) NewUsersBySP(ctx context.Context) ([]*skillz.User, erro
 
 }
 
+func (r *userRepository) UsersSharingWithCorporation(ctx context.Context, corporationID uint, allianceID null.Uint) ([]*skillz.User, error) {
+
+	columns := make([]string, 0, len(r.users.columns))
+	for _, c := range r.users.columns {
+		columns = append(columns, fmt.Sprintf("users.%s", c))
+	}
+
+	affiliation := sq.Or{sq.Eq{fmt.Sprintf("characters.%s", CharacterCorporationID): corporationID}}
+	if allianceID.Valid {
+		affiliation = append(affiliation, sq.Eq{fmt.Sprintf("characters.%s", CharacterAllianceID): allianceID.Uint})
+	}
+
+	query, args, err := sq.Select(columns...).
+		From(r.users.table).
+		InnerJoin(userSettingsInnerJoin).
+		InnerJoin(fmt.Sprintf("%s characters on characters.%s = users.%s", TableCharacters, CharacterID, ColumnCharacterID)).
+		Where(sq.Eq{
+			fmt.Sprintf("users.%s", UserDisabled):             0,
+			fmt.Sprintf("settings.%s", SettingsShareWithCorp): 1,
+		}).
+		Where(affiliation).
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrapf(err, errorFFormat, userRepositoryIdentifier, "UsersSharingWithCorporation", "failed to generate sql")
+	}
+
+	var users = make([]*skillz.User, 0)
+	err = r.db.SelectContext(ctx, &users, query, args...)
+	return users, errors.Wrapf(err, prefixFormat, userRepositoryIdentifier, "UsersSharingWithCorporation")
+
+}
+
 func (r *userRepository) UserSettings(ctx context.Context, id string) (*skillz.UserSettings, error) {
 
 	query, args, err := sq.Select(r.settings.columns...).
@@ -264,6 +341,8 @@ func (r *userRepository) CreateUserSettings(ctx context.Context, settings *skill
 		SettingsHideSkills:      settings.HideSkills,
 		SettingsHideAttributes:  settings.HideAttributes,
 		SettingsHideImplants:    settings.HideImplants,
+		SettingsHideStandings:   settings.HideStandings,
+		SettingsShareWithCorp:   settings.ShareWithCorporation,
 		ColumnCreatedAt:         settings.CreatedAt,
 		ColumnUpdatedAt:         settings.UpdatedAt,
 	}).
@@ -271,7 +350,8 @@ func (r *userRepository) CreateUserSettings(ctx context.Context, settings *skill
 			SettingsVisibility, SettingsVisibilityToken,
 			SettingsHideQueue, SettingsHideFlyable,
 			SettingsHideSkills, SettingsHideAttributes,
-			SettingsHideImplants, ColumnUpdatedAt,
+			SettingsHideImplants, SettingsHideStandings,
+			SettingsShareWithCorp, ColumnUpdatedAt,
 		)).
 		ToSql()
 	if err != nil {
@@ -282,3 +362,177 @@ func (r *userRepository) CreateUserSettings(ctx context.Context, settings *skill
 	return errors.Wrapf(err, prefixFormat, userRepositoryIdentifier, "CreateUserSettings")
 
 }
+
+func (r *userRepository) UserAccount(ctx context.Context, userID string) (*skillz.UserAccount, error) {
+
+	query, args, err := sq.Select(r.accounts.columns...).
+		From(r.accounts.table).
+		Where(sq.Eq{AccountUserID: userID}).
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrapf(err, errorFFormat, userRepositoryIdentifier, "UserAccount", "failed to generate sql")
+	}
+
+	var account = new(skillz.UserAccount)
+	err = r.db.GetContext(ctx, account, query, args...)
+	return account, errors.Wrapf(err, prefixFormat, userRepositoryIdentifier, "UserAccount")
+
+}
+
+func (r *userRepository) UserAccountsByAccountID(ctx context.Context, accountID string) ([]*skillz.UserAccount, error) {
+
+	query, args, err := sq.Select(r.accounts.columns...).
+		From(r.accounts.table).
+		Where(sq.Eq{AccountAccountID: accountID}).
+		OrderBy(fmt.Sprintf("%s %s", ColumnCreatedAt, "ASC")).
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrapf(err, errorFFormat, userRepositoryIdentifier, "UserAccountsByAccountID", "failed to generate sql")
+	}
+
+	var accounts = make([]*skillz.UserAccount, 0)
+	err = r.db.SelectContext(ctx, &accounts, query, args...)
+	return accounts, errors.Wrapf(err, prefixFormat, userRepositoryIdentifier, "UserAccountsByAccountID")
+
+}
+
+func (r *userRepository) CreateUserAccount(ctx context.Context, account *skillz.UserAccount) error {
+
+	account.CreatedAt = time.Now()
+
+	query, args, err := sq.Insert(r.accounts.table).SetMap(map[string]interface{}{
+		AccountUserID:    account.UserID,
+		AccountAccountID: account.AccountID,
+		AccountOwnerHash: account.OwnerHash,
+		ColumnCreatedAt:  account.CreatedAt,
+	}).
+		Suffix(OnDuplicateKeyStmt(
+			AccountAccountID, AccountOwnerHash, ColumnCreatedAt,
+		)).
+		ToSql()
+	if err != nil {
+		return errors.Wrapf(err, errorFFormat, userRepositoryIdentifier, "CreateUserAccount", "failed to generate sql")
+	}
+
+	_, err = r.db.ExecContext(ctx, query, args...)
+	return errors.Wrapf(err, prefixFormat, userRepositoryIdentifier, "CreateUserAccount")
+
+}
+
+func (r *userRepository) DeleteUserAccount(ctx context.Context, userID string) error {
+
+	query, args, err := sq.Delete(r.accounts.table).Where(sq.Eq{AccountUserID: userID}).ToSql()
+	if err != nil {
+		return errors.Wrapf(err, errorFFormat, userRepositoryIdentifier, "DeleteUserAccount", "failed to generate sql")
+	}
+
+	_, err = r.db.ExecContext(ctx, query, args...)
+	return errors.Wrapf(err, prefixFormat, userRepositoryIdentifier, "DeleteUserAccount")
+
+}
+
+func (r *userRepository) UserShareLink(ctx context.Context, token string) (*skillz.UserShareLink, error) {
+
+	query, args, err := sq.Select(r.shares.columns...).
+		From(r.shares.table).
+		Where(sq.Eq{ShareToken: token}).
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrapf(err, errorFFormat, userRepositoryIdentifier, "UserShareLink", "failed to generate sql")
+	}
+
+	var link = new(skillz.UserShareLink)
+	err = r.db.GetContext(ctx, link, query, args...)
+	return link, errors.Wrapf(err, prefixFormat, userRepositoryIdentifier, "UserShareLink")
+
+}
+
+func (r *userRepository) UserShareLinks(ctx context.Context, userID string) ([]*skillz.UserShareLink, error) {
+
+	query, args, err := sq.Select(r.shares.columns...).
+		From(r.shares.table).
+		Where(sq.Eq{ShareUserID: userID}).
+		OrderBy(fmt.Sprintf("%s %s", ColumnCreatedAt, "DESC")).
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrapf(err, errorFFormat, userRepositoryIdentifier, "UserShareLinks", "failed to generate sql")
+	}
+
+	var links = make([]*skillz.UserShareLink, 0)
+	err = r.db.SelectContext(ctx, &links, query, args...)
+	return links, errors.Wrapf(err, prefixFormat, userRepositoryIdentifier, "UserShareLinks")
+
+}
+
+func (r *userRepository) CreateUserShareLink(ctx context.Context, link *skillz.UserShareLink) error {
+
+	link.CreatedAt = time.Now()
+
+	query, args, err := sq.Insert(r.shares.table).SetMap(map[string]interface{}{
+		ShareToken:      link.Token,
+		ShareUserID:     link.UserID,
+		ShareLabel:      link.Label,
+		ShareSkills:     link.ShareSkills,
+		ShareQueue:      link.ShareQueue,
+		ShareImplants:   link.ShareImplants,
+		ShareFlyable:    link.ShareFlyable,
+		ShareExpiresAt:  link.ExpiresAt,
+		ColumnCreatedAt: link.CreatedAt,
+	}).ToSql()
+	if err != nil {
+		return errors.Wrapf(err, errorFFormat, userRepositoryIdentifier, "CreateUserShareLink", "failed to generate sql")
+	}
+
+	_, err = r.db.ExecContext(ctx, query, args...)
+	return errors.Wrapf(err, prefixFormat, userRepositoryIdentifier, "CreateUserShareLink")
+
+}
+
+func (r *userRepository) DeleteUserShareLink(ctx context.Context, userID, token string) error {
+
+	query, args, err := sq.Delete(r.shares.table).Where(sq.Eq{ShareUserID: userID, ShareToken: token}).ToSql()
+	if err != nil {
+		return errors.Wrapf(err, errorFFormat, userRepositoryIdentifier, "DeleteUserShareLink", "failed to generate sql")
+	}
+
+	_, err = r.db.ExecContext(ctx, query, args...)
+	return errors.Wrapf(err, prefixFormat, userRepositoryIdentifier, "DeleteUserShareLink")
+
+}
+
+func (r *userRepository) UserShareLinkViews(ctx context.Context, token string) ([]*skillz.UserShareLinkView, error) {
+
+	query, args, err := sq.Select(r.views.columns...).
+		From(r.views.table).
+		Where(sq.Eq{ShareViewToken: token}).
+		OrderBy(fmt.Sprintf("%s %s", ShareViewViewedAt, "DESC")).
+		ToSql()
+	if err != nil {
+		return nil, errors.Wrapf(err, errorFFormat, userRepositoryIdentifier, "UserShareLinkViews", "failed to generate sql")
+	}
+
+	var views = make([]*skillz.UserShareLinkView, 0)
+	err = r.db.SelectContext(ctx, &views, query, args...)
+	return views, errors.Wrapf(err, prefixFormat, userRepositoryIdentifier, "UserShareLinkViews")
+
+}
+
+func (r *userRepository) CreateUserShareLinkView(ctx context.Context, view *skillz.UserShareLinkView) error {
+
+	view.ViewedAt = time.Now()
+
+	query, args, err := sq.Insert(r.views.table).SetMap(map[string]interface{}{
+		ShareViewToken:     view.Token,
+		ShareViewViewerID:  view.ViewerID,
+		ShareViewIPAddress: view.IPAddress,
+		ShareViewUserAgent: view.UserAgent,
+		ShareViewViewedAt:  view.ViewedAt,
+	}).ToSql()
+	if err != nil {
+		return errors.Wrapf(err, errorFFormat, userRepositoryIdentifier, "CreateUserShareLinkView", "failed to generate sql")
+	}
+
+	_, err = r.db.ExecContext(ctx, query, args...)
+	return errors.Wrapf(err, prefixFormat, userRepositoryIdentifier, "CreateUserShareLinkView")
+
+}
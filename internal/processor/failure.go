@@ -0,0 +1,177 @@
+package processor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/eveisesi/skillz/internal"
+	"github.com/eveisesi/skillz/internal/esi"
+	"github.com/go-redis/redis/v8"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+)
+
+const (
+	// maxAttempts is the number of consecutive transient failures after which a user is dead lettered
+	maxAttempts = 8
+	// baseBackoff is how long a user waits before their first retry. The wait doubles with each attempt
+	baseBackoff = time.Minute
+	maxBackoff  = time.Hour * 6
+)
+
+// DeadLetter is a user that failed to be processed too many times in a row and has been
+// removed from the update queue until an admin requeues them
+type DeadLetter struct {
+	UserID   string    `json:"user_id"`
+	Attempts int64     `json:"attempts"`
+	Error    string    `json:"error"`
+	FailedAt time.Time `json:"failed_at"`
+}
+
+// isPermanent reports whether the failure will not go away by retrying, i.e. the user has revoked
+// the application's access to their character or the token is missing a scope that is required
+func isPermanent(err error) bool {
+
+	var retrieveErr *oauth2.RetrieveError
+	if errors.As(err, &retrieveErr) && retrieveErr.Response != nil {
+		switch retrieveErr.Response.StatusCode {
+		case http.StatusBadRequest, http.StatusUnauthorized:
+			return true
+		}
+	}
+
+	var esiErr *esi.Error
+	if errors.As(err, &esiErr) {
+		return esiErr.TokenRejected()
+	}
+
+	return false
+
+}
+
+// backoff returns how long a user waits before being retried after the provided number of attempts
+func backoff(attempts int64) time.Duration {
+
+	wait := baseBackoff
+	for i := int64(1); i < attempts; i++ {
+		wait *= 2
+		if wait >= maxBackoff {
+			return maxBackoff
+		}
+	}
+
+	return wait
+
+}
+
+// retry records a transient failure of the user and schedules them for a retry with an exponential
+// backoff. The user is dead lettered instead once they have failed maxAttempts times in a row
+func (s *Service) retry(ctx context.Context, userID string, cause error) error {
+
+	attempts, err := s.redis.HIncrBy(ctx, internal.FailedQueue, userID, 1).Result()
+	if err != nil {
+		return errors.Wrap(err, "failed to record user failure")
+	}
+
+	entry := s.logger.WithField("userID", userID).WithField("attempts", attempts)
+
+	if attempts >= maxAttempts {
+		entry.WithError(cause).Error("user failed too many times, dead lettering user")
+		return s.deadLetter(ctx, &DeadLetter{
+			UserID:   userID,
+			Attempts: attempts,
+			Error:    cause.Error(),
+			FailedAt: time.Now(),
+		})
+	}
+
+	next := time.Now().Add(backoff(attempts))
+	err = s.redis.ZAdd(ctx, internal.UpdateQueue, &redis.Z{Score: float64(next.Unix()), Member: userID}).Err()
+	if err != nil {
+		return errors.Wrap(err, "failed to push user id to update queue")
+	}
+
+	entry.WithField("next", next).Info("scheduled user retry")
+
+	return nil
+
+}
+
+// resetFailures clears the failures recorded for the user after the user has been processed successfully
+func (s *Service) resetFailures(ctx context.Context, userID string) error {
+	err := s.redis.HDel(ctx, internal.FailedQueue, userID).Err()
+	return errors.Wrap(err, "failed to reset user failures")
+}
+
+func (s *Service) deadLetter(ctx context.Context, letter *DeadLetter) error {
+
+	data, err := json.Marshal(letter)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode dead letter")
+	}
+
+	err = s.redis.HSet(ctx, internal.DeadQueue, letter.UserID, data).Err()
+	if err != nil {
+		return errors.Wrap(err, "failed to push user to dead letter queue")
+	}
+
+	return s.resetFailures(ctx, letter.UserID)
+
+}
+
+// DeadLetters returns the users on the dead letter queue, most recently failed first
+func (s *Service) DeadLetters(ctx context.Context) ([]*DeadLetter, error) {
+
+	results, err := s.redis.HGetAll(ctx, internal.DeadQueue).Result()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch dead letter queue")
+	}
+
+	letters := make([]*DeadLetter, 0, len(results))
+	for userID, data := range results {
+		var letter = new(DeadLetter)
+		err = json.Unmarshal([]byte(data), letter)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decode dead letter for user %s", userID)
+		}
+
+		letters = append(letters, letter)
+	}
+
+	sort.Slice(letters, func(i, j int) bool {
+		return letters[i].FailedAt.After(letters[j].FailedAt)
+	})
+
+	return letters, nil
+
+}
+
+// Requeue moves the users off of the dead letter queue and pushes them onto the update queue as due
+// immediately. Users that are not on the dead letter queue are ignored. The number of users requeued is returned
+func (s *Service) Requeue(ctx context.Context, userIDs ...string) (int, error) {
+
+	var requeued int
+	for _, userID := range userIDs {
+		removed, err := s.redis.HDel(ctx, internal.DeadQueue, userID).Result()
+		if err != nil {
+			return requeued, errors.Wrap(err, "failed to remove user from dead letter queue")
+		}
+
+		if removed == 0 {
+			continue
+		}
+
+		err = s.redis.ZAdd(ctx, internal.UpdateQueue, &redis.Z{Score: float64(time.Now().Unix()), Member: userID}).Err()
+		if err != nil {
+			return requeued, errors.Wrap(err, "failed to push user id to update queue")
+		}
+
+		requeued++
+	}
+
+	return requeued, nil
+
+}
@@ -0,0 +1,166 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/eveisesi/skillz/internal"
+	"github.com/go-redis/redis/v8"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+)
+
+var ErrUserLeased = errors.New("user is being processed by another worker")
+
+const (
+	// leaseTTL is how long a lease on a user is held without being renewed. Leases are renewed
+	// while the user is being processed, so the TTL only bounds how long a user stays locked
+	// after the worker processing them has died
+	leaseTTL = time.Minute * 2
+	// leaseRenewInterval is how often the lease on a user is renewed while the user is being processed
+	leaseRenewInterval = leaseTTL / 3
+)
+
+// releaseLease deletes the lease only when it is still held by the provided token
+var releaseLease = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// renewLease extends the lease only when it is still held by the provided token
+var renewLease = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// lease is a lock on a user held by a single worker. While held, the user is tracked on the updating
+// queue with the lease's expiry as the score so that the user can be recovered if the worker dies
+type lease struct {
+	userID string
+	token  string
+}
+
+func (s *Service) acquireLease(ctx context.Context, userID string) (*lease, error) {
+
+	l := &lease{userID: userID, token: uuid.Must(uuid.NewV4()).String()}
+
+	ok, err := s.redis.SetNX(ctx, fmt.Sprintf(internal.UserLease, userID), l.token, leaseTTL).Result()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to acquire user lease")
+	}
+
+	if !ok {
+		return nil, ErrUserLeased
+	}
+
+	err = s.redis.ZAdd(ctx, internal.UpdatingQueue, &redis.Z{Score: float64(time.Now().Add(leaseTTL).Unix()), Member: userID}).Err()
+	if err != nil {
+		_ = s.releaseLease(ctx, l)
+		return nil, errors.Wrap(err, "failed to push user id to updating queue")
+	}
+
+	return l, nil
+
+}
+
+func (s *Service) renewLease(ctx context.Context, l *lease) error {
+
+	renewed, err := renewLease.Run(ctx, s.redis, []string{fmt.Sprintf(internal.UserLease, l.userID)}, l.token, leaseTTL.Milliseconds()).Int()
+	if err != nil {
+		return errors.Wrap(err, "failed to renew user lease")
+	}
+
+	if renewed == 0 {
+		return ErrUserLeased
+	}
+
+	err = s.redis.ZAdd(ctx, internal.UpdatingQueue, &redis.Z{Score: float64(time.Now().Add(leaseTTL).Unix()), Member: l.userID}).Err()
+	return errors.Wrap(err, "failed to update user id on updating queue")
+
+}
+
+func (s *Service) releaseLease(ctx context.Context, l *lease) error {
+
+	err := s.redis.ZRem(ctx, internal.UpdatingQueue, l.userID).Err()
+	if err != nil {
+		return errors.Wrap(err, "failed to remove user id from updating queue")
+	}
+
+	err = releaseLease.Run(ctx, s.redis, []string{fmt.Sprintf(internal.UserLease, l.userID)}, l.token).Err()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return errors.Wrap(err, "failed to release user lease")
+	}
+
+	return nil
+
+}
+
+// holdLease renews the lease until the context is cancelled
+func (s *Service) holdLease(ctx context.Context, l *lease) {
+
+	ticker := time.NewTicker(leaseRenewInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := s.renewLease(context.Background(), l)
+			if err != nil {
+				s.logger.WithError(err).WithField("userID", l.userID).Error("failed to renew user lease")
+			}
+		}
+	}
+
+}
+
+// recoverExpiredLeases pushes the users on the updating queue whose lease has expired, i.e. the worker
+// processing them died before finishing, back onto the update queue as due immediately
+func (s *Service) recoverExpiredLeases(ctx context.Context) error {
+
+	expired, err := s.redis.ZRangeByScore(ctx, internal.UpdatingQueue, &redis.ZRangeBy{
+		Min: "-inf",
+		Max: fmt.Sprintf("%d", time.Now().Unix()),
+	}).Result()
+	if err != nil {
+		return errors.Wrap(err, "failed to fetch expired leases from updating queue")
+	}
+
+	for _, userID := range expired {
+		exists, err := s.redis.Exists(ctx, fmt.Sprintf(internal.UserLease, userID)).Result()
+		if err != nil {
+			return errors.Wrap(err, "failed to check user lease")
+		}
+
+		// The lease was renewed after the range was fetched
+		if exists > 0 {
+			continue
+		}
+
+		// Only the worker that removes the user from the updating queue recovers them
+		removed, err := s.redis.ZRem(ctx, internal.UpdatingQueue, userID).Result()
+		if err != nil {
+			return errors.Wrap(err, "failed to remove user id from updating queue")
+		}
+
+		if removed == 0 {
+			continue
+		}
+
+		err = s.redis.ZAddNX(ctx, internal.UpdateQueue, &redis.Z{Score: float64(time.Now().Unix()), Member: userID}).Err()
+		if err != nil {
+			return errors.Wrap(err, "failed to push user id to update queue")
+		}
+
+		s.logger.WithField("userID", userID).Info("recovered user with expired lease")
+	}
+
+	return nil
+
+}
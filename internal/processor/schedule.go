@@ -0,0 +1,198 @@
+package processor
+
+import (
+	"context"
+	"time"
+
+	"github.com/eveisesi/skillz"
+	"github.com/eveisesi/skillz/internal"
+	"github.com/eveisesi/skillz/internal/esi"
+	"github.com/go-redis/redis/v8"
+	"github.com/pkg/errors"
+	"github.com/volatiletech/null"
+)
+
+const (
+	// minRefreshInterval prevents a user from being refreshed in a tight loop when ESI reports
+	// a cache expiry that has already passed
+	minRefreshInterval = time.Minute * 5
+	// maxRefreshInterval is how long a user waits for a refresh when none of their endpoints have been cached yet
+	maxRefreshInterval = time.Hour * 24
+	// maxIdleInterval is the longest the processor sleeps before checking the update queue again,
+	// so that users pushed onto the queue by a login or a manual refresh are picked up quickly
+	maxIdleInterval = time.Second * 5
+)
+
+// scopeEndpoints are the character endpoints that the scope processors request for each scope
+var scopeEndpoints = map[skillz.Scope][]esi.EndpointID{
+	skillz.ReadSkillsV1:     {esi.GetCharacterSkills, esi.GetCharacterAttributes},
+	skillz.ReadSkillQueueV1: {esi.GetCharacterSkillQueue},
+	skillz.ReadImplantsV1:   {esi.GetCharacterImplants},
+	skillz.ReadClonesV1:     {esi.GetCharacterClones},
+	skillz.ReadContactsV1:   {esi.GetCharacterContacts},
+}
+
+// NextUpdate returns when the user is next due to be refreshed. This is the earliest of the times that
+// ESI's cache of the endpoints covered by the user's scopes expires and the time that the skill
+// currently in training finishes, bound by minRefreshInterval and maxRefreshInterval
+func (s *Service) NextUpdate(ctx context.Context, user *skillz.User) (time.Time, error) {
+
+	var now = time.Now()
+	var next = now.Add(maxRefreshInterval)
+	var training bool
+
+	for _, scope := range user.Scopes {
+		if scope == skillz.ReadSkillQueueV1 {
+			training = true
+		}
+
+		for _, endpoint := range scopeEndpoints[scope] {
+			// Every page of a paged endpoint expires at once, so the first page stands in for the
+			// endpoint. The page is ignored by endpoints that are not paged
+			_, etag, err := s.esi.Etag(ctx, endpoint, &esi.Params{CharacterID: null.Uint64From(user.CharacterID), Page: null.UintFrom(1)})
+			if err != nil {
+				return time.Time{}, errors.Wrap(err, "failed to fetch etag for cache expiry")
+			}
+
+			if etag != nil && etag.CachedUntil.Before(next) {
+				next = etag.CachedUntil
+			}
+		}
+	}
+
+	if training {
+		summary, err := s.skills.SkillQueue(ctx, user.CharacterID)
+		if err != nil {
+			return time.Time{}, errors.Wrap(err, "failed to fetch skill queue for finish date")
+		}
+
+		for _, position := range summary.Queue {
+			if !position.FinishDate.Valid || !position.FinishDate.Time.After(now) {
+				continue
+			}
+
+			if position.FinishDate.Time.Before(next) {
+				next = position.FinishDate.Time
+			}
+			break
+		}
+	}
+
+	if min := now.Add(minRefreshInterval); next.Before(min) {
+		next = min
+	}
+
+	return next, nil
+
+}
+
+// schedule pushes the user onto the update queue with the time they are next due to be refreshed as the score
+func (s *Service) schedule(ctx context.Context, user *skillz.User) error {
+
+	next, err := s.NextUpdate(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	err = s.redis.ZAdd(ctx, internal.UpdateQueue, &redis.Z{Score: float64(next.Unix()), Member: user.ID}).Err()
+	if err != nil {
+		return errors.Wrap(err, "failed to push user id to update queue")
+	}
+
+	s.logger.WithField("userID", user.ID).WithField("next", next).Debug("scheduled user update")
+
+	return nil
+
+}
+
+// scheduleUpdatableUsers makes sure that every enabled user is on the update queue, e.g. after the queue
+// has been flushed, without touching the schedule of the users already on it. Users are refreshed by the
+// workers as their data expires, so this only needs to happen when the processor starts
+func (s *Service) scheduleUpdatableUsers(ctx context.Context) {
+
+	users, err := s.user.ProcessUpdatableUsers(ctx)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to fetch updatable users")
+		return
+	}
+
+	added, err := s.ScheduleMissingUsers(ctx, users)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to schedule missing users")
+		return
+	}
+
+	s.logger.WithField("count", len(users)).WithField("added", added).Info("scheduled missing users")
+
+}
+
+// ScheduleMissingUsers pushes the users that are not on the update queue onto it as due immediately.
+// Users already on the queue keep their scheduled time and dead lettered users are skipped
+func (s *Service) ScheduleMissingUsers(ctx context.Context, users []*skillz.User) (int64, error) {
+
+	dead, err := s.redis.HKeys(ctx, internal.DeadQueue).Result()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to fetch dead letter queue")
+	}
+
+	var skip = make(map[string]bool, len(dead))
+	for _, userID := range dead {
+		skip[userID] = true
+	}
+
+	var score = float64(time.Now().Unix())
+	var members = make([]*redis.Z, 0, len(users))
+	for _, user := range users {
+		if skip[user.ID] {
+			continue
+		}
+
+		members = append(members, &redis.Z{Score: score, Member: user.ID})
+	}
+
+	if len(members) == 0 {
+		return 0, nil
+	}
+
+	added, err := s.redis.ZAddNX(ctx, internal.UpdateQueue, members...).Result()
+	return added, errors.Wrap(err, "failed to push user ids to update queue")
+
+}
+
+// nextDueUser claims the user that is due to be refreshed the soonest by removing them from the update
+// queue. When no user is due yet, nextDueUser sleeps until the earliest due time, maxIdleInterval at most or
+// the context is cancelled, and returns nil
+func (s *Service) nextDueUser(ctx context.Context) (*redis.Z, error) {
+
+	results, err := s.redis.ZRangeWithScores(ctx, internal.UpdateQueue, 0, 0).Result()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch next user from update queue")
+	}
+
+	if len(results) == 0 {
+		sleep(ctx, maxIdleInterval)
+		return nil, nil
+	}
+
+	next := results[0]
+	if wait := time.Until(time.Unix(int64(next.Score), 0)); wait > 0 {
+		if wait > maxIdleInterval {
+			wait = maxIdleInterval
+		}
+		sleep(ctx, wait)
+		return nil, nil
+	}
+
+	// Another processor may have claimed the user between fetching and removing them
+	removed, err := s.redis.ZRem(ctx, internal.UpdateQueue, next.Member).Result()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to claim user from update queue")
+	}
+
+	if removed == 0 {
+		return nil, nil
+	}
+
+	return &next, nil
+
+}
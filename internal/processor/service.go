@@ -2,10 +2,12 @@ package processor
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/eveisesi/skillz"
-	"github.com/eveisesi/skillz/internal"
+	"github.com/eveisesi/skillz/internal/esi"
+	"github.com/eveisesi/skillz/internal/skill"
 	"github.com/eveisesi/skillz/internal/user/v2"
 	"github.com/go-redis/redis/v8"
 	"github.com/newrelic/go-agent/v3/newrelic"
@@ -18,18 +20,22 @@ type Service struct {
 	redis    *redis.Client
 	newrelic *newrelic.Application
 
-	user user.API
+	esi    esi.EtagAPI
+	user   user.API
+	skills skill.API
 
 	processors skillz.ScopeProcessors
 }
 
-func New(logger *logrus.Logger, redisClient *redis.Client, newrelic *newrelic.Application, user user.API, processors skillz.ScopeProcessors) *Service {
+func New(logger *logrus.Logger, redisClient *redis.Client, newrelic *newrelic.Application, esi esi.EtagAPI, user user.API, skills skill.API, processors skillz.ScopeProcessors) *Service {
 	return &Service{
 		logger:   logger,
 		redis:    redisClient,
 		newrelic: newrelic,
 
-		user: user,
+		esi:    esi,
+		user:   user,
+		skills: skills,
 
 		processors: processors,
 	}
@@ -43,49 +49,134 @@ func (s *Service) downtime() bool {
 	return now.After(startDT) && now.Before(endDT)
 }
 
-func (s *Service) Run() error {
+// Run schedules the users that are missing from the update queue, then starts the workers that process
+// the users on the update queue along with the recovery of users whose lease has expired, and blocks until
+// the context is cancelled. Workers finish the user they are processing before returning, so cancelling
+// the context drains the pool gracefully
+func (s *Service) Run(ctx context.Context, workers int) error {
 
-	s.logger.Info("Processor has started....")
+	if workers < 1 {
+		workers = 1
+	}
+
+	s.scheduleUpdatableUsers(ctx)
+
+	s.logger.WithField("workers", workers).Info("Processor has started....")
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg = new(sync.WaitGroup)
+	var errs = make(chan error, workers+1)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.recoverLeases(ctx)
+	}()
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			err := s.work(ctx, worker)
+			if err != nil {
+				errs <- errors.Wrapf(err, "worker %d stopped", worker)
+				// A worker only stops on an error when redis is unavailable,
+				// so the remaining workers are stopped as well
+				cancel()
+			}
+		}(i)
+	}
+
+	wg.Wait()
+	close(errs)
+
+	s.logger.Info("Processor has stopped....")
+
+	return <-errs
+
+}
+
+// work processes users from the update queue as they become due until the context is cancelled
+func (s *Service) work(ctx context.Context, worker int) error {
 
 	for {
 
+		if ctx.Err() != nil {
+			return nil
+		}
+
 		if s.downtime() {
 			s.logger.Info("sleeping for downtime")
-			time.Sleep(time.Minute)
+			sleep(ctx, time.Minute)
 			continue
 		}
 
-		var entry = logrus.NewEntry(s.logger)
-		var ctx context.Context = context.Background()
-		result, err := s.redis.BZPopMin(ctx, 0, internal.UpdateQueue).Result()
-		if err != nil && !errors.Is(err, redis.Nil) {
+		result, err := s.nextDueUser(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
 			return err
 		}
 
+		if result == nil {
+			continue
+		}
+
 		userID, ok := result.Member.(string)
 		if !ok {
 			s.logger.Errorf("unexpected value for member, expected string, got %T", result.Member)
 			continue
 		}
 
-		entry = entry.WithField("userID", userID)
+		entry := s.logger.WithField("userID", userID).WithField("worker", worker)
 
-		_, err = s.redis.ZAdd(ctx, internal.UpdatingQueue, &result.Z).Result()
-		if err != nil {
-			return err
+		// The user is processed with a context that is not cancelled on
+		// shutdown so that a user is never left half processed
+		err = s.processUser(context.Background(), userID)
+		if errors.Is(err, ErrUserLeased) {
+			entry.Debug("user is already being processed")
+			continue
 		}
 
-		err = s.processUser(context.Background(), userID)
 		if err != nil {
 			entry.WithError(err).Error("failed to process user id")
-			continue
 		}
 
-		_, err = s.redis.ZRem(ctx, internal.UpdatingQueue, result.Member).Result()
-		if err != nil {
-			return err
+	}
+
+}
+
+// recoverLeases periodically recovers the users whose lease has expired until the context is cancelled
+func (s *Service) recoverLeases(ctx context.Context) {
+
+	for {
+		err := s.recoverExpiredLeases(ctx)
+		if err != nil && ctx.Err() == nil {
+			s.logger.WithError(err).Error("failed to recover expired leases")
 		}
 
+		if !sleep(ctx, leaseTTL) {
+			return
+		}
+	}
+
+}
+
+// sleep pauses for the duration or until the context is cancelled, and
+// reports whether the full duration elapsed
+func sleep(ctx context.Context, d time.Duration) bool {
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
 	}
 
 }
@@ -98,21 +189,50 @@ func (s *Service) processUser(ctx context.Context, userID string) error {
 
 	ctx = newrelic.NewContext(ctx, txn)
 
-	user, err := s.user.User(ctx, userID)
+	u, err := s.user.User(ctx, userID)
 	if err != nil {
 		err = errors.Wrap(err, "failed to fetch user from data store")
 		txn.NoticeError(err)
+
+		// Deleted users are left off of the queue
+		if !errors.Is(err, user.ErrUserNotFound) {
+			if rerr := s.retry(ctx, userID, err); rerr != nil {
+				s.logger.WithError(rerr).Error("failed to schedule user")
+			}
+		}
+
 		return err
 	}
 
-	return s.ProcessUser(ctx, user)
+	return s.ProcessUser(ctx, u)
 
 }
 
-func (s *Service) ProcessUser(ctx context.Context, user *skillz.User) error {
+// ProcessUser refreshes the user's data from ESI while holding a lease on the user, so that a user is never
+// processed by two workers at once. ErrUserLeased is returned when another worker holds the lease. Users that
+// are processed successfully are scheduled for their next refresh on the update queue. Users that fail permanently
+// are disabled and left off of the queue, while transient failures are retried with a backoff until the user is dead lettered
+func (s *Service) ProcessUser(ctx context.Context, user *skillz.User) (err error) {
 
 	txn := newrelic.FromContext(ctx)
 
+	l, err := s.acquireLease(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	leaseCtx, cancel := context.WithCancel(ctx)
+	go s.holdLease(leaseCtx, l)
+
+	defer func() {
+		cancel()
+		err := s.releaseLease(context.Background(), l)
+		if err != nil {
+			txn.NoticeError(err)
+			s.logger.WithError(err).Error("failed to release user lease")
+		}
+	}()
+
 	defer func() {
 		user.IsNew = false
 		user.LastProcessed.SetValid(time.Now())
@@ -124,13 +244,33 @@ func (s *Service) ProcessUser(ctx context.Context, user *skillz.User) error {
 		}
 	}()
 
-	err := s.user.ValidateCurrentToken(ctx, user)
+	defer func() {
+		var outcome error
+		switch {
+		case err == nil:
+			outcome = s.resetFailures(ctx, user.ID)
+			if outcome == nil {
+				outcome = s.schedule(ctx, user)
+			}
+		case isPermanent(err):
+			user.Disabled = true
+			user.DisabledReason.SetValid(err.Error())
+			user.DisabledTimestamp.SetValid(time.Now())
+			outcome = s.resetFailures(ctx, user.ID)
+		default:
+			outcome = s.retry(ctx, user.ID, err)
+		}
+
+		if outcome != nil {
+			txn.NoticeError(errors.Wrap(outcome, "failed to schedule user"))
+			s.logger.WithError(outcome).Error("failed to schedule user")
+		}
+	}()
+
+	err = s.user.ValidateCurrentToken(ctx, user)
 	if err != nil {
 		err = errors.Wrap(err, "failed to validate token")
 		txn.NoticeError(err)
-		user.Disabled = true
-		user.DisabledReason.SetValid(err.Error())
-		user.DisabledTimestamp.SetValid(time.Now())
 		return err
 	}
 
@@ -155,9 +295,6 @@ func (s *Service) ProcessUser(ctx context.Context, user *skillz.User) error {
 		if err != nil {
 			err = errors.Wrap(err, "processor failed to process user")
 			txn.NoticeError(err)
-			user.Disabled = true
-			user.DisabledReason.SetValid(err.Error())
-			user.DisabledTimestamp.SetValid(time.Now())
 			return err
 		}
 	}
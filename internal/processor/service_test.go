@@ -0,0 +1,235 @@
+package processor_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/eveisesi/skillz"
+	"github.com/eveisesi/skillz/internal"
+	"github.com/eveisesi/skillz/internal/alliance"
+	"github.com/eveisesi/skillz/internal/auth"
+	"github.com/eveisesi/skillz/internal/character"
+	"github.com/eveisesi/skillz/internal/clone"
+	"github.com/eveisesi/skillz/internal/contact"
+	"github.com/eveisesi/skillz/internal/corporation"
+	"github.com/eveisesi/skillz/internal/esitest"
+	"github.com/eveisesi/skillz/internal/memory"
+	"github.com/eveisesi/skillz/internal/processor"
+	"github.com/eveisesi/skillz/internal/skill"
+	"github.com/eveisesi/skillz/internal/universe"
+	user "github.com/eveisesi/skillz/internal/user/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/pkg/errors"
+)
+
+// processorFunc adapts a function to a skillz.Processor
+type processorFunc func(ctx context.Context, user *skillz.User) error
+
+func (f processorFunc) Process(ctx context.Context, user *skillz.User) error {
+	return f(ctx, user)
+}
+
+type harness struct {
+	server  *esitest.Server
+	redis   *redis.Client
+	auth    *auth.Service
+	user    *user.Service
+	skills  skillz.CharacterSkillRepository
+	service *processor.Service
+}
+
+// newHarness wires the processor against the fake ESI and SSO and the in-memory repositories. The extra
+// processors run after the clone, skill and contact processors
+func newHarness(t *testing.T, extra ...skillz.Processor) *harness {
+	t.Helper()
+
+	env := esitest.NewHarness(t)
+
+	skillsRepo := memory.NewSkillRepository(env.DB)
+
+	character := character.New(env.Logger, env.Cache, env.ESI, env.ETag, memory.NewCharacterRepository(env.DB))
+	corporation := corporation.New(env.Logger, env.Cache, env.ESI, env.ETag, memory.NewCorporationRepository(env.DB))
+	alliance := alliance.New(env.Logger, env.Cache, env.ESI, env.ETag, memory.NewAllianceRepository(env.DB))
+
+	auth := auth.New(skillz.Production, env.Server.Client(), env.Cache, env.Server.OAuth2Config(env.Server.URL+"/callback"), env.Server.BaseURI())
+	universe := universe.New(env.Logger, env.Cache, env.ESI, memory.NewUniverseRepository(env.DB))
+	clone := clone.New(env.Logger, env.Cache, env.ETag, env.ESI, universe, memory.NewCloneRepository(env.DB))
+	contact := contact.New(env.Logger, env.Cache, env.ETag, env.ESI, character, corporation, alliance, memory.NewContactRepository(env.DB))
+	skills := skill.New(env.Logger, env.Cache, env.ESI, universe, clone, skillsRepo)
+
+	user := user.New(env.Redis, env.Logger, env.Cache, auth, alliance, character, corporation, skills, clone, contact, memory.NewUserRepository(env.DB))
+
+	processors := append(skillz.ScopeProcessors{clone, skills, contact}, extra...)
+
+	return &harness{
+		server:  env.Server,
+		redis:   env.Redis,
+		auth:    auth,
+		user:    user,
+		skills:  skillsRepo,
+		service: processor.New(env.Logger, env.Redis, nil, env.ESI, user, skills, processors),
+	}
+
+}
+
+// login runs the fixture character through the SSO with the scopes, the way the callback handler does
+func (h *harness) login(t *testing.T, scopes ...skillz.Scope) *skillz.User {
+	t.Helper()
+
+	var ctx = context.Background()
+
+	attempt, err := h.auth.InitializeAttempt(ctx)
+	if err != nil {
+		t.Fatalf("failed to initialize auth attempt: %s", err)
+	}
+
+	u, err := h.user.Login(ctx, h.server.Code(esitest.CharacterID, scopes...), attempt.State)
+	if err != nil {
+		t.Fatalf("failed to login: %s", err)
+	}
+
+	return u
+}
+
+func TestProcessUser(t *testing.T) {
+
+	var ctx = context.Background()
+
+	h := newHarness(t)
+	u := h.login(t, skillz.ReadSkillsV1, skillz.ReadSkillQueueV1, skillz.ReadImplantsV1, skillz.ReadContactsV1)
+
+	err := h.service.ProcessUser(ctx, u)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	stored, err := h.user.User(ctx, u.ID)
+	if err != nil {
+		t.Fatalf("failed to fetch user: %s", err)
+	}
+	if stored.IsNew || stored.IsProcessing {
+		t.Errorf("expected the user to be marked as processed")
+	}
+
+	meta, err := h.skills.CharacterSkillMeta(ctx, esitest.CharacterID)
+	if err != nil || meta.TotalSP != 310670 {
+		t.Errorf("expected the user's skills to be stored, got %v", err)
+	}
+
+	next, err := h.redis.ZScore(ctx, internal.UpdateQueue, u.ID).Result()
+	if err != nil {
+		t.Fatalf("expected the user to be scheduled, got %s", err)
+	}
+	if next <= float64(time.Now().Unix()) {
+		t.Errorf("expected the user to be scheduled in the future, got %s", time.Unix(int64(next), 0))
+	}
+
+	leased, err := h.redis.ZScore(ctx, internal.UpdatingQueue, u.ID).Result()
+	if !errors.Is(err, redis.Nil) {
+		t.Errorf("expected the lease to be released, got %v at %v", err, leased)
+	}
+
+}
+
+func TestProcessUserLeased(t *testing.T) {
+
+	var ctx = context.Background()
+	var h *harness
+	var nested error
+
+	// The nested call runs while the outer call holds the lease on the user
+	h = newHarness(t, processorFunc(func(ctx context.Context, user *skillz.User) error {
+		nested = h.service.ProcessUser(ctx, user)
+		return nil
+	}))
+
+	u := h.login(t, skillz.ReadSkillsV1)
+
+	err := h.service.ProcessUser(ctx, u)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !errors.Is(nested, processor.ErrUserLeased) {
+		t.Errorf("expected ErrUserLeased while the user is being processed, got %v", nested)
+	}
+
+}
+
+func TestProcessUserTokenRejected(t *testing.T) {
+
+	var ctx = context.Background()
+
+	h := newHarness(t)
+	h.server.SetStatus("/v4/characters/90000001/skills/", http.StatusUnauthorized)
+
+	u := h.login(t, skillz.ReadSkillsV1)
+
+	// Workers pop the user off of the update queue before processing them
+	err := h.redis.ZRem(ctx, internal.UpdateQueue, u.ID).Err()
+	if err != nil {
+		t.Fatalf("failed to pop user: %s", err)
+	}
+
+	err = h.service.ProcessUser(ctx, u)
+	if err == nil {
+		t.Fatal("expected an error when esi rejects the token, got nil")
+	}
+
+	stored, err := h.user.User(ctx, u.ID)
+	if err != nil {
+		t.Fatalf("failed to fetch user: %s", err)
+	}
+	if !stored.Disabled || !stored.DisabledReason.Valid {
+		t.Error("expected the user to be disabled when esi rejects their token")
+	}
+
+	_, err = h.redis.ZScore(ctx, internal.UpdateQueue, u.ID).Result()
+	if !errors.Is(err, redis.Nil) {
+		t.Errorf("expected a disabled user to be left off of the update queue, got %v", err)
+	}
+
+}
+
+func TestRunSchedulesMissingUsers(t *testing.T) {
+
+	h := newHarness(t)
+	u := h.login(t, skillz.ReadSkillsV1)
+
+	// The user is left off of the update queue, e.g. after the queue has been flushed
+	err := h.redis.ZRem(context.Background(), internal.UpdateQueue, u.ID).Err()
+	if err != nil {
+		t.Fatalf("failed to pop user: %s", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- h.service.Run(ctx, 1) }()
+
+	// A worker may have already picked the user up from the update queue and leased them
+	var scheduled bool
+	for deadline := time.Now().Add(5 * time.Second); !scheduled && time.Now().Before(deadline); {
+		for _, queue := range []string{internal.UpdateQueue, internal.UpdatingQueue} {
+			_, err = h.redis.ZScore(context.Background(), queue, u.ID).Result()
+			scheduled = scheduled || err == nil
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	cancel()
+
+	err = <-done
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !scheduled {
+		t.Error("expected the processor to schedule the user that is missing from the update queue")
+	}
+
+}
@@ -0,0 +1,120 @@
+package repotest
+
+import (
+	"testing"
+
+	"github.com/eveisesi/skillz"
+	"github.com/volatiletech/null"
+)
+
+var cloneTests = []test{
+	{"CharacterImplantsSortedBySlot", func(t *testing.T, r *Repositories) {
+		seedUser(t, r, "user-1", 90000001)
+		seedTypes(t, r, 300, 9941, 9942, 9943)
+
+		implants := []*skillz.CharacterImplant{
+			{CharacterID: 90000001, ImplantID: 9942, Slot: 2},
+			{CharacterID: 90000001, ImplantID: 9941, Slot: 1},
+			{CharacterID: 90000001, ImplantID: 9943, Slot: 7},
+		}
+		requireNoError(t, r.Clone.CreateCharacterImplants(ctx, implants))
+		requireError(t, r.Clone.CreateCharacterImplants(ctx, implants[:1]))
+		requireError(t, r.Clone.CreateCharacterImplants(ctx, []*skillz.CharacterImplant{}))
+
+		got, err := r.Clone.CharacterImplants(ctx, 90000001)
+		requireNoError(t, err)
+		// Implants in slots above 5 are hardwirings, which are not selected
+		requireLen(t, "CharacterImplants", 2, len(got))
+		requireEqual(t, "Slot", uint(1), got[0].Slot)
+		requireEqual(t, "ImplantID", uint(9941), got[0].ImplantID)
+		requireEqual(t, "Slot", uint(2), got[1].Slot)
+
+		requireNoError(t, r.Clone.DeleteCharacterImplants(ctx, 90000001))
+
+		got, err = r.Clone.CharacterImplants(ctx, 90000001)
+		requireNoError(t, err)
+		requireLen(t, "CharacterImplants", 0, len(got))
+	}},
+	{"CharacterJumpClones", func(t *testing.T, r *Repositories) {
+		seedUser(t, r, "user-1", 90000001)
+		seedTypes(t, r, 300, 9941, 9942)
+
+		clones := []*skillz.CharacterJumpClone{
+			{CharacterID: 90000001, JumpCloneID: 2, LocationID: 1022734985679, LocationType: skillz.StructureLocationType},
+			{CharacterID: 90000001, JumpCloneID: 1, Name: null.StringFrom("Jita"), LocationID: 60003760, LocationType: skillz.StationLocationType},
+		}
+		requireNoError(t, r.Clone.CreateCharacterJumpClones(ctx, clones))
+		requireError(t, r.Clone.CreateCharacterJumpClones(ctx, clones[:1]))
+
+		implants := []*skillz.CharacterJumpCloneImplant{
+			{CharacterID: 90000001, JumpCloneID: 1, ImplantID: 9942, Slot: 2},
+			{CharacterID: 90000001, JumpCloneID: 2, ImplantID: 9941, Slot: 1},
+		}
+		requireNoError(t, r.Clone.CreateCharacterJumpCloneImplants(ctx, implants))
+
+		got, err := r.Clone.CharacterJumpClones(ctx, 90000001)
+		requireNoError(t, err)
+		requireLen(t, "CharacterJumpClones", 2, len(got))
+		requireEqual(t, "JumpCloneID", uint(1), got[0].JumpCloneID)
+		requireEqual(t, "Name", null.StringFrom("Jita"), got[0].Name)
+		requireEqual(t, "LocationType", skillz.StationLocationType, got[0].LocationType)
+		requireEqual(t, "Name", false, got[1].Name.Valid)
+		requireEqual(t, "LocationID", uint64(1022734985679), got[1].LocationID)
+
+		gotImplants, err := r.Clone.CharacterJumpCloneImplants(ctx, 90000001)
+		requireNoError(t, err)
+		requireLen(t, "CharacterJumpCloneImplants", 2, len(gotImplants))
+		requireEqual(t, "Slot", uint(1), gotImplants[0].Slot)
+		requireEqual(t, "JumpCloneID", uint(2), gotImplants[0].JumpCloneID)
+
+		// Deleting the clones deletes their implants along with them
+		requireNoError(t, r.Clone.DeleteCharacterJumpClones(ctx, 90000001))
+
+		got, err = r.Clone.CharacterJumpClones(ctx, 90000001)
+		requireNoError(t, err)
+		requireLen(t, "CharacterJumpClones", 0, len(got))
+
+		gotImplants, err = r.Clone.CharacterJumpCloneImplants(ctx, 90000001)
+		requireNoError(t, err)
+		requireLen(t, "CharacterJumpCloneImplants", 0, len(gotImplants))
+	}},
+}
+
+var contactTests = []test{
+	{"CharacterContacts", func(t *testing.T, r *Repositories) {
+		seedUser(t, r, "user-1", 90000001)
+		seedUser(t, r, "user-2", 90000002)
+
+		contacts := []*skillz.CharacterContact{
+			{CharacterID: 90000001, ContactID: 90000002, ContactType: skillz.CharacterContactType, Standing: 10},
+			{CharacterID: 90000001, ContactID: 98000001, ContactType: skillz.CorporationContactType, Standing: -5.5},
+			{CharacterID: 90000002, ContactID: 90000001, ContactType: skillz.CharacterContactType, Standing: 5},
+		}
+		requireNoError(t, r.Contact.CreateCharacterContacts(ctx, contacts))
+		requireError(t, r.Contact.CreateCharacterContacts(ctx, contacts[:1]))
+
+		got, err := r.Contact.CharacterContacts(ctx, 90000001)
+		requireNoError(t, err)
+		requireLen(t, "CharacterContacts", 2, len(got))
+
+		byID := make(map[uint]*skillz.CharacterContact)
+		for _, contact := range got {
+			byID[contact.ContactID] = contact
+		}
+		if byID[98000001] == nil {
+			t.Fatal("expected the corporation contact to be returned")
+		}
+		requireEqual(t, "ContactType", skillz.CorporationContactType, byID[98000001].ContactType)
+		requireEqual(t, "Standing", -5.5, byID[98000001].Standing)
+
+		requireNoError(t, r.Contact.DeleteCharacterContacts(ctx, 90000001))
+
+		got, err = r.Contact.CharacterContacts(ctx, 90000001)
+		requireNoError(t, err)
+		requireLen(t, "CharacterContacts", 0, len(got))
+
+		got, err = r.Contact.CharacterContacts(ctx, 90000002)
+		requireNoError(t, err)
+		requireLen(t, "CharacterContacts", 1, len(got))
+	}},
+}
@@ -0,0 +1,156 @@
+package repotest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/eveisesi/skillz"
+	"github.com/volatiletech/null"
+)
+
+var allianceTests = []test{
+	{"AllianceNotFound", func(t *testing.T, r *Repositories) {
+		alliance, err := r.Alliance.Alliance(ctx, 99000001)
+		requireNoRows(t, err)
+		if alliance == nil {
+			t.Fatal("expected an empty alliance, got nil")
+		}
+	}},
+	{"CreateAndUpdateAlliance", func(t *testing.T, r *Repositories) {
+		alliance := &skillz.Alliance{
+			ID:                    99000001,
+			Name:                  "Fixture Alliance",
+			Ticker:                "FIXA",
+			DateFounded:           epoch,
+			CreatorID:             90000001,
+			CreatorCorporationID:  98000001,
+			ExecutorCorporationID: 98000001,
+		}
+		requireNoError(t, r.Alliance.CreateAlliance(ctx, alliance))
+		requireError(t, r.Alliance.CreateAlliance(ctx, alliance))
+
+		got, err := r.Alliance.Alliance(ctx, alliance.ID)
+		requireNoError(t, err)
+		requireEqual(t, "Name", alliance.Name, got.Name)
+		requireEqual(t, "ExecutorCorporationID", alliance.ExecutorCorporationID, got.ExecutorCorporationID)
+		requireTime(t, "DateFounded", alliance.DateFounded, got.DateFounded)
+		requireRecent(t, "CreatedAt", got.CreatedAt)
+
+		alliance.Name = "Renamed Alliance"
+		alliance.IsClosed = true
+		requireNoError(t, r.Alliance.UpdateAlliance(ctx, alliance))
+
+		updated, err := r.Alliance.Alliance(ctx, alliance.ID)
+		requireNoError(t, err)
+		requireEqual(t, "Name", alliance.Name, updated.Name)
+		requireEqual(t, "IsClosed", true, updated.IsClosed)
+		requireTime(t, "CreatedAt", got.CreatedAt, updated.CreatedAt)
+	}},
+}
+
+var characterTests = []test{
+	{"CharacterNotFound", func(t *testing.T, r *Repositories) {
+		_, err := r.Character.Character(ctx, 90000001)
+		requireNoRows(t, err)
+	}},
+	{"CreateAndUpdateCharacter", func(t *testing.T, r *Repositories) {
+		character := &skillz.Character{
+			ID:             90000001,
+			Name:           "Fixture Pilot",
+			CorporationID:  98000001,
+			AllianceID:     null.UintFrom(99000001),
+			SecurityStatus: null.Float64From(2.5),
+			Gender:         "female",
+			Birthday:       epoch.AddDate(-5, 0, 0),
+			BloodlineID:    4,
+			RaceID:         1,
+		}
+		requireNoError(t, r.Character.CreateCharacter(ctx, character))
+		requireError(t, r.Character.CreateCharacter(ctx, character))
+
+		got, err := r.Character.Character(ctx, character.ID)
+		requireNoError(t, err)
+		requireEqual(t, "Name", character.Name, got.Name)
+		requireEqual(t, "AllianceID", character.AllianceID, got.AllianceID)
+		requireEqual(t, "SecurityStatus", character.SecurityStatus, got.SecurityStatus)
+		requireEqual(t, "Title", false, got.Title.Valid)
+		requireTime(t, "Birthday", character.Birthday, got.Birthday)
+
+		character.CorporationID = 98000002
+		character.AllianceID = null.Uint{}
+		requireNoError(t, r.Character.UpdateCharacter(ctx, character))
+
+		updated, err := r.Character.Character(ctx, character.ID)
+		requireNoError(t, err)
+		requireEqual(t, "CorporationID", character.CorporationID, updated.CorporationID)
+		requireEqual(t, "AllianceID", false, updated.AllianceID.Valid)
+		requireTime(t, "CreatedAt", got.CreatedAt, updated.CreatedAt)
+	}},
+}
+
+var corporationTests = []test{
+	{"CorporationNotFound", func(t *testing.T, r *Repositories) {
+		_, err := r.Corporation.Corporation(ctx, 98000001)
+		requireNoRows(t, err)
+	}},
+	{"CreateAndUpdateCorporation", func(t *testing.T, r *Repositories) {
+		corporation := &skillz.Corporation{
+			ID:          98000001,
+			Name:        "Fixture Corporation",
+			Ticker:      "FIXC",
+			CeoID:       90000001,
+			CreatorID:   90000001,
+			AllianceID:  null.UintFrom(99000001),
+			DateFounded: null.TimeFrom(epoch.AddDate(-2, 0, 0)),
+			MemberCount: 12,
+			TaxRate:     0.25,
+			URL:         null.StringFrom("https://example.com"),
+			WarEligible: true,
+		}
+		requireNoError(t, r.Corporation.CreateCorporation(ctx, corporation))
+		requireError(t, r.Corporation.CreateCorporation(ctx, corporation))
+
+		got, err := r.Corporation.Corporation(ctx, corporation.ID)
+		requireNoError(t, err)
+		requireEqual(t, "Name", corporation.Name, got.Name)
+		requireEqual(t, "TaxRate", corporation.TaxRate, got.TaxRate)
+		requireEqual(t, "URL", corporation.URL, got.URL)
+		requireEqual(t, "WarEligible", true, got.WarEligible)
+		requireEqual(t, "HomeStationID", false, got.HomeStationID.Valid)
+		requireTime(t, "DateFounded", corporation.DateFounded.Time, got.DateFounded.Time)
+
+		corporation.MemberCount = 13
+		corporation.WarEligible = false
+		requireNoError(t, r.Corporation.UpdateCorporation(ctx, corporation))
+
+		updated, err := r.Corporation.Corporation(ctx, corporation.ID)
+		requireNoError(t, err)
+		requireEqual(t, "MemberCount", corporation.MemberCount, updated.MemberCount)
+		requireEqual(t, "WarEligible", false, updated.WarEligible)
+		requireTime(t, "CreatedAt", got.CreatedAt, updated.CreatedAt)
+	}},
+}
+
+var etagTests = []test{
+	{"EtagNotFound", func(t *testing.T, r *Repositories) {
+		_, err := r.Etag.Etag(ctx, "/v4/characters/90000001/skills/")
+		requireNoRows(t, err)
+	}},
+	{"InsertEtagUpserts", func(t *testing.T, r *Repositories) {
+		etag := &skillz.Etag{
+			Path:        "/v4/characters/90000001/skills/",
+			Etag:        "first",
+			CachedUntil: epoch,
+		}
+		requireNoError(t, r.Etag.InsertEtag(ctx, etag))
+
+		etag.Etag = "second"
+		etag.CachedUntil = epoch.Add(5 * time.Minute)
+		requireNoError(t, r.Etag.InsertEtag(ctx, etag))
+
+		got, err := r.Etag.Etag(ctx, etag.Path)
+		requireNoError(t, err)
+		requireEqual(t, "Etag", "second", got.Etag)
+		requireTime(t, "CachedUntil", etag.CachedUntil, got.CachedUntil)
+	}},
+}
@@ -0,0 +1,157 @@
+// Package repotest is a conformance suite for the implementations of the skillz repositories. The suite
+// runs the same tests against every implementation, so that the in-memory repositories keep behaving
+// like the MySQL ones. Only the tables that are created by the migrations are covered
+package repotest
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/eveisesi/skillz"
+	"github.com/pkg/errors"
+	"github.com/volatiletech/null"
+)
+
+// Repositories are the implementations under test. They must share a single store
+type Repositories struct {
+	Alliance    skillz.AllianceRepository
+	Character   skillz.CharacterRepository
+	Clone       skillz.CloneRepository
+	Contact     skillz.ContactRepository
+	Corporation skillz.CorporationRepository
+	Etag        skillz.EtagRepository
+	Skill       skillz.CharacterSkillRepository
+	Universe    skillz.UniverseRepository
+	User        skillz.UserRepository
+}
+
+type test struct {
+	name string
+	run  func(t *testing.T, r *Repositories)
+}
+
+// Run runs the suite. newRepositories is called once for every test and must return repositories over an empty store
+func Run(t *testing.T, newRepositories func(t *testing.T) *Repositories) {
+
+	var tests = make([]test, 0)
+	tests = append(tests, allianceTests...)
+	tests = append(tests, characterTests...)
+	tests = append(tests, corporationTests...)
+	tests = append(tests, etagTests...)
+	tests = append(tests, cloneTests...)
+	tests = append(tests, contactTests...)
+	tests = append(tests, skillTests...)
+	tests = append(tests, universeTests...)
+	tests = append(tests, userTests...)
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.run(t, newRepositories(t))
+		})
+	}
+
+}
+
+var ctx = context.Background()
+
+// epoch is a whole second, so that it survives the round trip through a DATETIME column unchanged
+var epoch = time.Date(2022, time.October, 1, 12, 0, 0, 0, time.UTC)
+
+func requireNoError(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
+func requireError(t *testing.T, err error) {
+	t.Helper()
+	if err == nil {
+		t.Fatal("expected an error, got nil")
+	}
+}
+
+func requireNoRows(t *testing.T, err error) {
+	t.Helper()
+	if errors.Cause(err) != sql.ErrNoRows {
+		t.Fatalf("expected sql.ErrNoRows, got %v", err)
+	}
+}
+
+func requireEqual(t *testing.T, field string, want, got interface{}) {
+	t.Helper()
+	if want != got {
+		t.Fatalf("%s: expected %v, got %v", field, want, got)
+	}
+}
+
+func requireLen(t *testing.T, field string, want, got int) {
+	t.Helper()
+	if want != got {
+		t.Fatalf("%s: expected %d rows, got %d", field, want, got)
+	}
+}
+
+// requireTime compares times at the precision of a DATETIME column
+func requireTime(t *testing.T, field string, want, got time.Time) {
+	t.Helper()
+	if !want.Round(time.Second).Equal(got.Round(time.Second)) {
+		t.Fatalf("%s: expected %s, got %s", field, want, got)
+	}
+}
+
+// requireRecent asserts that a timestamp set by a repository is close to now
+func requireRecent(t *testing.T, field string, got time.Time) {
+	t.Helper()
+	if d := time.Since(got); d < -time.Minute || d > time.Minute {
+		t.Fatalf("%s: expected a recent time, got %s", field, got)
+	}
+}
+
+// seedUser creates a user for the character, which the character tables reference
+func seedUser(t *testing.T, r *Repositories, id string, characterID uint64) *skillz.User {
+	t.Helper()
+
+	user := &skillz.User{
+		ID:           id,
+		CharacterID:  characterID,
+		AccessToken:  "access-" + id,
+		RefreshToken: "refresh-" + id,
+		Expires:      epoch.Add(time.Hour),
+		OwnerHash:    "hash-" + id,
+		Scopes:       skillz.UserScopes{skillz.ReadSkillsV1, skillz.ReadSkillQueueV1},
+		IsNew:        true,
+		LastLogin:    epoch,
+	}
+
+	requireNoError(t, r.User.CreateUser(ctx, user))
+
+	return user
+}
+
+// seedTypes creates the types, along with their group and category, which the tables of skills,
+// ships and implants reference
+func seedTypes(t *testing.T, r *Repositories, groupID uint, typeIDs ...uint) {
+	t.Helper()
+
+	requireNoError(t, r.Universe.CreateCategory(ctx, &skillz.Category{ID: 16, Name: "Skill", Published: true}))
+	requireNoError(t, r.Universe.CreateGroup(ctx, &skillz.Group{ID: groupID, Name: "Spaceship Command", Published: true, CategoryID: 16}))
+	for _, id := range typeIDs {
+		requireNoError(t, r.Universe.CreateType(ctx, newType(id, "Type", groupID)))
+	}
+}
+
+// newType returns a type with the columns that may not be NULL set
+func newType(id uint, name string, groupID uint) *skillz.Type {
+	return &skillz.Type{
+		ID:             id,
+		Name:           name,
+		GroupID:        groupID,
+		Published:      true,
+		Mass:           null.Float64From(0),
+		PackagedVolume: null.Float64From(0),
+	}
+}
@@ -0,0 +1,326 @@
+package repotest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/eveisesi/skillz"
+	"github.com/volatiletech/null"
+)
+
+var skillTests = []test{
+	{"CharacterAttributesUpsert", func(t *testing.T, r *Repositories) {
+		seedUser(t, r, "user-1", 90000001)
+
+		_, err := r.Skill.CharacterAttributes(ctx, 90000001)
+		requireNoRows(t, err)
+
+		attributes := &skillz.CharacterAttributes{
+			CharacterID:   90000001,
+			Charisma:      17,
+			Intelligence:  27,
+			Memory:        21,
+			Perception:    17,
+			Willpower:     17,
+			BonusRemaps:   null.UintFrom(1),
+			LastRemapDate: null.TimeFrom(epoch),
+		}
+		requireNoError(t, r.Skill.CreateCharacterAttributes(ctx, attributes))
+
+		got, err := r.Skill.CharacterAttributes(ctx, 90000001)
+		requireNoError(t, err)
+
+		attributes.Intelligence = 21
+		attributes.Memory = 27
+		requireNoError(t, r.Skill.CreateCharacterAttributes(ctx, attributes))
+
+		updated, err := r.Skill.CharacterAttributes(ctx, 90000001)
+		requireNoError(t, err)
+		requireEqual(t, "Intelligence", uint(21), updated.Intelligence)
+		requireEqual(t, "Memory", uint(27), updated.Memory)
+		requireEqual(t, "BonusRemaps", null.UintFrom(1), updated.BonusRemaps)
+		requireEqual(t, "AccruedRemapCooldownDate", false, updated.AccruedRemapCooldownDate.Valid)
+		requireTime(t, "LastRemapDate", epoch, updated.LastRemapDate.Time)
+		requireTime(t, "CreatedAt", got.CreatedAt, updated.CreatedAt)
+
+		requireNoError(t, r.Skill.DeleteCharacterAttributes(ctx, 90000001))
+		_, err = r.Skill.CharacterAttributes(ctx, 90000001)
+		requireNoRows(t, err)
+	}},
+	{"CharacterSkillMetaUpsert", func(t *testing.T, r *Repositories) {
+		seedUser(t, r, "user-1", 90000001)
+
+		_, err := r.Skill.CharacterSkillMeta(ctx, 90000001)
+		requireNoRows(t, err)
+
+		meta := &skillz.CharacterSkillMeta{CharacterID: 90000001, TotalSP: 5000000}
+		requireNoError(t, r.Skill.CreateCharacterSkillMeta(ctx, meta))
+
+		meta.TotalSP = 5500000
+		meta.UnallocatedSP = null.UintFrom(250000)
+		requireNoError(t, r.Skill.CreateCharacterSkillMeta(ctx, meta))
+
+		got, err := r.Skill.CharacterSkillMeta(ctx, 90000001)
+		requireNoError(t, err)
+		requireEqual(t, "TotalSP", uint(5500000), got.TotalSP)
+		requireEqual(t, "UnallocatedSP", null.UintFrom(250000), got.UnallocatedSP)
+
+		requireNoError(t, r.Skill.DeleteCharacterSkillMeta(ctx, 90000001))
+		_, err = r.Skill.CharacterSkillMeta(ctx, 90000001)
+		requireNoRows(t, err)
+	}},
+	{"CharacterSkillsUpsert", func(t *testing.T, r *Repositories) {
+		seedUser(t, r, "user-1", 90000001)
+
+		requireError(t, r.Skill.CreateCharacterSkills(ctx, []*skillz.CharacterSkill{}))
+
+		skills := []*skillz.CharacterSkill{
+			{CharacterID: 90000001, SkillID: 3327, ActiveSkillLevel: 3, TrainedSkillLevel: 3, SkillpointsInSkill: 8000},
+			{CharacterID: 90000001, SkillID: 3300, ActiveSkillLevel: 1, TrainedSkillLevel: 1, SkillpointsInSkill: 250},
+		}
+		requireNoError(t, r.Skill.CreateCharacterSkills(ctx, skills))
+
+		skills[0].ActiveSkillLevel = 4
+		skills[0].TrainedSkillLevel = 4
+		skills[0].SkillpointsInSkill = 45255
+		requireNoError(t, r.Skill.CreateCharacterSkills(ctx, skills[:1]))
+
+		got, err := r.Skill.CharacterSkills(ctx, 90000001)
+		requireNoError(t, err)
+		requireLen(t, "CharacterSkills", 2, len(got))
+
+		for _, skill := range got {
+			if skill.SkillID != 3327 {
+				continue
+			}
+			requireEqual(t, "TrainedSkillLevel", uint(4), skill.TrainedSkillLevel)
+			requireEqual(t, "SkillpointsInSkill", uint(45255), skill.SkillpointsInSkill)
+		}
+
+		requireNoError(t, r.Skill.DeleteCharacterSkills(ctx, 90000001))
+		got, err = r.Skill.CharacterSkills(ctx, 90000001)
+		requireNoError(t, err)
+		requireLen(t, "CharacterSkills", 0, len(got))
+	}},
+	{"CharacterSkillQueueSortedByPosition", func(t *testing.T, r *Repositories) {
+		seedUser(t, r, "user-1", 90000001)
+
+		positions := []*skillz.CharacterSkillQueue{
+			{CharacterID: 90000001, QueuePosition: 1, SkillID: 3300, FinishedLevel: 2},
+			{
+				CharacterID:     90000001,
+				QueuePosition:   0,
+				SkillID:         3327,
+				FinishedLevel:   5,
+				TrainingStartSp: null.UintFrom(45255),
+				LevelStartSp:    null.UintFrom(45255),
+				LevelEndSp:      null.UintFrom(256000),
+				StartDate:       null.TimeFrom(epoch),
+				FinishDate:      null.TimeFrom(epoch.Add(72 * time.Hour)),
+			},
+		}
+		requireNoError(t, r.Skill.CreateCharacterSkillQueue(ctx, positions))
+		requireError(t, r.Skill.CreateCharacterSkillQueue(ctx, positions[:1]))
+
+		got, err := r.Skill.CharacterSkillQueue(ctx, 90000001)
+		requireNoError(t, err)
+		requireLen(t, "CharacterSkillQueue", 2, len(got))
+		requireEqual(t, "QueuePosition", uint(0), got[0].QueuePosition)
+		requireEqual(t, "LevelEndSp", null.UintFrom(256000), got[0].LevelEndSp)
+		requireTime(t, "FinishDate", epoch.Add(72*time.Hour), got[0].FinishDate.Time)
+		requireEqual(t, "QueuePosition", uint(1), got[1].QueuePosition)
+		requireEqual(t, "StartDate", false, got[1].StartDate.Valid)
+
+		requireNoError(t, r.Skill.DeleteCharacterSkillQueue(ctx, 90000001))
+		got, err = r.Skill.CharacterSkillQueue(ctx, 90000001)
+		requireNoError(t, err)
+		requireLen(t, "CharacterSkillQueue", 0, len(got))
+	}},
+	{"CharacterFlyableShips", func(t *testing.T, r *Repositories) {
+		seedUser(t, r, "user-1", 90000001)
+		seedTypes(t, r, 25, 587, 603)
+
+		ships := []*skillz.CharacterFlyableShip{
+			{CharacterID: 90000001, ShipTypeID: 587},
+			{CharacterID: 90000001, ShipTypeID: 603},
+		}
+		requireNoError(t, r.Skill.CreateCharacterFlyableShips(ctx, ships))
+		requireError(t, r.Skill.CreateCharacterFlyableShips(ctx, ships[:1]))
+
+		got, err := r.Skill.CharacterFlyableShips(ctx, 90000001)
+		requireNoError(t, err)
+		requireLen(t, "CharacterFlyableShips", 2, len(got))
+
+		requireNoError(t, r.Skill.DeleteCharacterFlyableShips(ctx, 90000001))
+		got, err = r.Skill.CharacterFlyableShips(ctx, 90000001)
+		requireNoError(t, err)
+		requireLen(t, "CharacterFlyableShips", 0, len(got))
+	}},
+	{"SkillPlans", func(t *testing.T, r *Repositories) {
+		seedUser(t, r, "user-1", 90000001)
+		seedTypes(t, r, 257, 3327, 3300)
+
+		_, err := r.Skill.SkillPlan(ctx, 1)
+		requireNoRows(t, err)
+
+		zeta := &skillz.SkillPlan{UserID: "user-1", Name: "Zeta"}
+		requireNoError(t, r.Skill.CreateSkillPlan(ctx, zeta))
+		alpha := &skillz.SkillPlan{UserID: "user-1", Name: "Alpha"}
+		requireNoError(t, r.Skill.CreateSkillPlan(ctx, alpha))
+		if zeta.ID == 0 || alpha.ID == 0 || zeta.ID == alpha.ID {
+			t.Fatalf("expected distinct ids to be assigned to the plans, got %d and %d", zeta.ID, alpha.ID)
+		}
+
+		// A plan is created along with its entries, or not at all
+		omega := &skillz.SkillPlan{UserID: "user-1", Name: "Omega", Entries: []*skillz.SkillPlanEntry{
+			{Position: 1, SkillID: 3327, Level: 1},
+			{Position: 1, SkillID: 3300, Level: 1},
+		}}
+		requireError(t, r.Skill.CreateSkillPlan(ctx, omega))
+
+		omega.Entries = omega.Entries[:1]
+		requireNoError(t, r.Skill.CreateSkillPlan(ctx, omega))
+		gotEntries, err := r.Skill.SkillPlanEntries(ctx, omega.ID)
+		requireNoError(t, err)
+		requireLen(t, "SkillPlanEntries", 1, len(gotEntries))
+		requireEqual(t, "PlanID", omega.ID, gotEntries[0].PlanID)
+		requireNoError(t, r.Skill.DeleteSkillPlan(ctx, omega.ID))
+
+		plans, err := r.Skill.SkillPlansByUserID(ctx, "user-1")
+		requireNoError(t, err)
+		requireLen(t, "SkillPlansByUserID", 2, len(plans))
+		requireEqual(t, "Name", "Alpha", plans[0].Name)
+		requireEqual(t, "ID", alpha.ID, plans[0].ID)
+
+		zeta.Name = "Beta"
+		zeta.UserID = "user-2"
+		requireNoError(t, r.Skill.UpdateSkillPlan(ctx, zeta))
+
+		plan, err := r.Skill.SkillPlan(ctx, zeta.ID)
+		requireNoError(t, err)
+		requireEqual(t, "Name", "Beta", plan.Name)
+		// Only the name of a plan is updated
+		requireEqual(t, "UserID", "user-1", plan.UserID)
+
+		entries := []*skillz.SkillPlanEntry{
+			{PlanID: zeta.ID, Position: 1, SkillID: 3327, Level: 4},
+			{PlanID: zeta.ID, Position: 0, SkillID: 3300, Level: 3},
+		}
+		requireNoError(t, r.Skill.CreateSkillPlanEntries(ctx, entries))
+
+		entries[0].Level = 5
+		requireError(t, r.Skill.CreateSkillPlanEntries(ctx, entries[:1]))
+
+		gotEntries, err = r.Skill.SkillPlanEntries(ctx, zeta.ID)
+		requireNoError(t, err)
+		requireLen(t, "SkillPlanEntries", 2, len(gotEntries))
+		requireEqual(t, "Position", uint(0), gotEntries[0].Position)
+		requireEqual(t, "SkillID", uint(3300), gotEntries[0].SkillID)
+		requireEqual(t, "Level", uint(4), gotEntries[1].Level)
+
+		// Entries that collide leave the entries of the plan untouched
+		requireError(t, r.Skill.ReplaceSkillPlanEntries(ctx, zeta.ID, []*skillz.SkillPlanEntry{
+			{PlanID: zeta.ID, Position: 1, SkillID: 3402, Level: 1},
+			{PlanID: zeta.ID, Position: 1, SkillID: 3300, Level: 1},
+		}))
+		gotEntries, err = r.Skill.SkillPlanEntries(ctx, zeta.ID)
+		requireNoError(t, err)
+		requireLen(t, "SkillPlanEntries", 2, len(gotEntries))
+
+		requireNoError(t, r.Skill.DeleteSkillPlanEntries(ctx, zeta.ID))
+		gotEntries, err = r.Skill.SkillPlanEntries(ctx, zeta.ID)
+		requireNoError(t, err)
+		requireLen(t, "SkillPlanEntries", 0, len(gotEntries))
+
+		requireNoError(t, r.Skill.CreateSkillPlanEntries(ctx, entries))
+		requireNoError(t, r.Skill.ReplaceSkillPlanEntries(ctx, zeta.ID, []*skillz.SkillPlanEntry{
+			{PlanID: zeta.ID, Position: 1, SkillID: 3402, Level: 1},
+		}))
+		gotEntries, err = r.Skill.SkillPlanEntries(ctx, zeta.ID)
+		requireNoError(t, err)
+		requireLen(t, "SkillPlanEntries", 1, len(gotEntries))
+		requireEqual(t, "SkillID", uint(3402), gotEntries[0].SkillID)
+
+		requireNoError(t, r.Skill.ReplaceSkillPlanEntries(ctx, zeta.ID, nil))
+		gotEntries, err = r.Skill.SkillPlanEntries(ctx, zeta.ID)
+		requireNoError(t, err)
+		requireLen(t, "SkillPlanEntries", 0, len(gotEntries))
+
+		// Deleting a plan deletes its entries along with it
+		requireNoError(t, r.Skill.CreateSkillPlanEntries(ctx, entries))
+		requireNoError(t, r.Skill.DeleteSkillPlan(ctx, zeta.ID))
+
+		_, err = r.Skill.SkillPlan(ctx, zeta.ID)
+		requireNoRows(t, err)
+
+		gotEntries, err = r.Skill.SkillPlanEntries(ctx, zeta.ID)
+		requireNoError(t, err)
+		requireLen(t, "SkillPlanEntries", 0, len(gotEntries))
+	}},
+	{"CharacterSkillSnapshots", func(t *testing.T, r *Repositories) {
+		seedUser(t, r, "user-1", 90000001)
+		seedUser(t, r, "user-2", 90000002)
+
+		snapshot := &skillz.CharacterSkillSnapshot{CharacterID: 90000001, TotalSP: 5000000, UnallocatedSP: null.UintFrom(1000)}
+		requireNoError(t, r.Skill.CreateCharacterSkillSnapshot(ctx, snapshot))
+		other := &skillz.CharacterSkillSnapshot{CharacterID: 90000002, TotalSP: 1000000}
+		requireNoError(t, r.Skill.CreateCharacterSkillSnapshot(ctx, other))
+		if snapshot.ID == 0 || snapshot.ID == other.ID {
+			t.Fatalf("expected distinct ids to be assigned to the snapshots, got %d and %d", snapshot.ID, other.ID)
+		}
+
+		changes := []*skillz.CharacterSkillChange{
+			{SnapshotID: snapshot.ID, CharacterID: 90000001, SkillID: 3327, PreviousSkillLevel: 3, TrainedSkillLevel: 4, PreviousSkillpoints: 8000, SkillpointsInSkill: 45255},
+			{SnapshotID: snapshot.ID, CharacterID: 90000001, SkillID: 3300, PreviousSkillLevel: 1, TrainedSkillLevel: 2, PreviousSkillpoints: 250, SkillpointsInSkill: 1415},
+		}
+		requireNoError(t, r.Skill.CreateCharacterSkillChanges(ctx, changes))
+		requireError(t, r.Skill.CreateCharacterSkillChanges(ctx, changes[:1]))
+
+		from, to := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+
+		snapshots, err := r.Skill.CharacterSkillSnapshots(ctx, 90000001, from, to)
+		requireNoError(t, err)
+		requireLen(t, "CharacterSkillSnapshots", 1, len(snapshots))
+		requireEqual(t, "ID", snapshot.ID, snapshots[0].ID)
+		requireEqual(t, "UnallocatedSP", null.UintFrom(1000), snapshots[0].UnallocatedSP)
+		requireRecent(t, "CreatedAt", snapshots[0].CreatedAt)
+
+		snapshots, err = r.Skill.CharacterSkillSnapshots(ctx, 90000001, epoch, epoch.Add(time.Hour))
+		requireNoError(t, err)
+		requireLen(t, "CharacterSkillSnapshots", 0, len(snapshots))
+
+		gotChanges, err := r.Skill.CharacterSkillChanges(ctx, 90000001, from, to)
+		requireNoError(t, err)
+		requireLen(t, "CharacterSkillChanges", 2, len(gotChanges))
+		requireEqual(t, "SkillID", uint(3300), gotChanges[0].SkillID)
+		requireEqual(t, "SkillID", uint(3327), gotChanges[1].SkillID)
+		requireEqual(t, "SkillpointsInSkill", uint(45255), gotChanges[1].SkillpointsInSkill)
+
+		gotChanges, err = r.Skill.CharacterSkillChanges(ctx, 90000002, from, to)
+		requireNoError(t, err)
+		requireLen(t, "CharacterSkillChanges", 0, len(gotChanges))
+	}},
+	{"CharacterSkillCompletions", func(t *testing.T, r *Repositories) {
+		seedUser(t, r, "user-1", 90000001)
+
+		completions := []*skillz.CharacterSkillCompletion{
+			{CharacterID: 90000001, SkillID: 3327, Level: 3, CompletedAt: epoch},
+			{CharacterID: 90000001, SkillID: 3327, Level: 4, CompletedAt: epoch.Add(48 * time.Hour)},
+			{CharacterID: 90000001, SkillID: 3300, Level: 2, CompletedAt: epoch.Add(24 * time.Hour)},
+		}
+		requireNoError(t, r.Skill.CreateCharacterSkillCompletions(ctx, completions))
+		// Completions that were already recorded are ignored
+		requireNoError(t, r.Skill.CreateCharacterSkillCompletions(ctx, completions))
+
+		got, err := r.Skill.CharacterSkillCompletions(ctx, 90000001, 10)
+		requireNoError(t, err)
+		requireLen(t, "CharacterSkillCompletions", 3, len(got))
+
+		got, err = r.Skill.CharacterSkillCompletions(ctx, 90000001, 2)
+		requireNoError(t, err)
+		requireLen(t, "CharacterSkillCompletions", 2, len(got))
+		requireEqual(t, "Level", uint(4), got[0].Level)
+		requireEqual(t, "SkillID", uint(3300), got[1].SkillID)
+		requireTime(t, "CompletedAt", epoch.Add(24*time.Hour), got[1].CompletedAt)
+	}},
+}
@@ -0,0 +1,192 @@
+package repotest
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/eveisesi/skillz"
+	"github.com/volatiletech/null"
+)
+
+var universeTests = []test{
+	{"CategoryUpsert", func(t *testing.T, r *Repositories) {
+		_, err := r.Universe.Category(ctx, 6)
+		requireNoRows(t, err)
+
+		category := &skillz.Category{ID: 6, Name: "Ship", Published: false}
+		requireNoError(t, r.Universe.CreateCategory(ctx, category))
+
+		category.Published = true
+		requireNoError(t, r.Universe.CreateCategory(ctx, category))
+
+		got, err := r.Universe.Category(ctx, 6)
+		requireNoError(t, err)
+		requireEqual(t, "Name", "Ship", got.Name)
+		requireEqual(t, "Published", true, got.Published)
+
+		categories, err := r.Universe.Categories(ctx)
+		requireNoError(t, err)
+		requireLen(t, "Categories", 1, len(categories))
+	}},
+	{"GroupsWithOperators", func(t *testing.T, r *Repositories) {
+		_, err := r.Universe.Group(ctx, 25)
+		requireNoRows(t, err)
+
+		groups := []*skillz.Group{
+			{ID: 25, Name: "Frigate", Published: true, CategoryID: 6},
+			{ID: 26, Name: "Cruiser", Published: true, CategoryID: 6},
+			{ID: 257, Name: "Spaceship Command", Published: true, CategoryID: 16},
+		}
+		for _, group := range groups {
+			requireNoError(t, r.Universe.CreateGroup(ctx, group))
+		}
+
+		got, err := r.Universe.Group(ctx, 26)
+		requireNoError(t, err)
+		requireEqual(t, "Name", "Cruiser", got.Name)
+
+		all, err := r.Universe.Groups(ctx)
+		requireNoError(t, err)
+		requireIDs(t, "Groups", []uint{25, 26, 257}, groupIDs(all))
+
+		ships, err := r.Universe.Groups(ctx, skillz.NewEqualOperator("category_id", 6))
+		requireNoError(t, err)
+		requireIDs(t, "Groups", []uint{25, 26}, groupIDs(ships))
+
+		in, err := r.Universe.Groups(ctx, skillz.NewInOperator("id", []interface{}{25, 257, 999}))
+		requireNoError(t, err)
+		requireIDs(t, "Groups", []uint{25, 257}, groupIDs(in))
+
+		like, err := r.Universe.Groups(ctx, skillz.NewLikeOperator("name", "command"))
+		requireNoError(t, err)
+		requireIDs(t, "Groups", []uint{257}, groupIDs(like))
+	}},
+	{"TypesWithOperators", func(t *testing.T, r *Repositories) {
+		_, err := r.Universe.Type(ctx, 587)
+		requireNoRows(t, err)
+
+		items := []*skillz.Type{newType(587, "Rifter", 25), newType(603, "Merlin", 25), newType(620, "Osprey", 26)}
+		items[2].MarketGroupID = null.UintFrom(75)
+		for _, item := range items {
+			requireNoError(t, r.Universe.CreateType(ctx, item))
+		}
+
+		items[0].Name = "Rifter II"
+		items[0].Volume = 27289
+		requireNoError(t, r.Universe.CreateType(ctx, items[0]))
+
+		got, err := r.Universe.Type(ctx, 587)
+		requireNoError(t, err)
+		requireEqual(t, "Name", "Rifter II", got.Name)
+		requireEqual(t, "Volume", float64(27289), got.Volume)
+		requireEqual(t, "MarketGroupID", false, got.MarketGroupID.Valid)
+
+		frigates, err := r.Universe.Types(ctx, skillz.NewInOperator("group_id", []interface{}{uint(25)}))
+		requireNoError(t, err)
+		requireIDs(t, "Types", []uint{587, 603}, typeIDs(frigates))
+
+		named, err := r.Universe.Types(ctx, skillz.NewInOperator("name", []interface{}{"Merlin", "Osprey"}))
+		requireNoError(t, err)
+		requireIDs(t, "Types", []uint{603, 620}, typeIDs(named))
+
+		// LIKE is case insensitive under the collation of the column
+		like, err := r.Universe.Types(ctx, skillz.NewLikeOperator("name", "rifter"))
+		requireNoError(t, err)
+		requireIDs(t, "Types", []uint{587}, typeIDs(like))
+
+		combined, err := r.Universe.Types(ctx,
+			skillz.NewEqualOperator("group_id", 25),
+			skillz.NewNotEqualOperator("id", 587),
+		)
+		requireNoError(t, err)
+		requireIDs(t, "Types", []uint{603}, typeIDs(combined))
+	}},
+	{"TypeDogmaAttributes", func(t *testing.T, r *Repositories) {
+		seedTypes(t, r, 257, 3327, 3300)
+
+		attributes := []*skillz.TypeDogmaAttribute{
+			{TypeID: 3327, AttributeID: skillz.SkillRankAttributeID, Value: 2},
+			{TypeID: 3327, AttributeID: skillz.SkillPrimaryAttributeAttributeID, Value: float64(skillz.PerceptionAttributeID)},
+			{TypeID: 3300, AttributeID: skillz.SkillRankAttributeID, Value: 1},
+		}
+		requireNoError(t, r.Universe.CreateTypeDogmaAttributes(ctx, attributes))
+
+		attributes[0].Value = 4.5
+		requireNoError(t, r.Universe.CreateTypeDogmaAttributes(ctx, attributes[:1]))
+
+		got, err := r.Universe.TypeDogmaAttributes(ctx, 3327)
+		requireNoError(t, err)
+		requireLen(t, "TypeDogmaAttributes", 2, len(got))
+		for _, attribute := range got {
+			if attribute.AttributeID == skillz.SkillRankAttributeID {
+				requireEqual(t, "Value", 4.5, attribute.Value)
+			}
+		}
+
+		bulk, err := r.Universe.TypeDogmaAttributesBulk(ctx, []uint{3327, 3300})
+		requireNoError(t, err)
+		requireLen(t, "TypeDogmaAttributesBulk", 3, len(bulk))
+
+		requireNoError(t, r.Universe.DeleteTypeDogmaAttributes(ctx, 3327))
+		got, err = r.Universe.TypeDogmaAttributes(ctx, 3327)
+		requireNoError(t, err)
+		requireLen(t, "TypeDogmaAttributes", 0, len(got))
+	}},
+	{"ShipFlightRequirements", func(t *testing.T, r *Repositories) {
+		seedTypes(t, r, 257, 587, 3327, 3329)
+
+		requirements := []*skillz.ShipFlightRequirement{
+			{GroupID: 257, ShipID: 587, SkillID: 3327, MinimumSkillLevel: 1},
+			{GroupID: 257, ShipID: 587, SkillID: 3329, MinimumSkillLevel: 1},
+		}
+		requireNoError(t, r.Universe.CreateShipFlightRequirements(ctx, requirements))
+
+		requirements[1].MinimumSkillLevel = 3
+		requireNoError(t, r.Universe.CreateShipFlightRequirements(ctx, requirements[1:]))
+
+		got, err := r.Universe.ShipFlightRequirements(ctx, 587)
+		requireNoError(t, err)
+		requireLen(t, "ShipFlightRequirements", 2, len(got))
+		for _, requirement := range got {
+			if requirement.SkillID == 3329 {
+				requireEqual(t, "MinimumSkillLevel", uint(3), requirement.MinimumSkillLevel)
+			}
+		}
+
+		requireNoError(t, r.Universe.DeleteShipFlightRequirements(ctx, 587))
+		got, err = r.Universe.ShipFlightRequirements(ctx, 587)
+		requireNoError(t, err)
+		requireLen(t, "ShipFlightRequirements", 0, len(got))
+	}},
+}
+
+// requireIDs compares the ids regardless of their order, since the queries do not order them
+func requireIDs(t *testing.T, field string, want, got []uint) {
+	t.Helper()
+
+	sort.Slice(got, func(i, j int) bool { return got[i] < got[j] })
+	if len(want) != len(got) {
+		t.Fatalf("%s: expected %v, got %v", field, want, got)
+	}
+	for i := range want {
+		if want[i] != got[i] {
+			t.Fatalf("%s: expected %v, got %v", field, want, got)
+		}
+	}
+}
+
+func groupIDs(groups []*skillz.Group) []uint {
+	ids := make([]uint, 0, len(groups))
+	for _, group := range groups {
+		ids = append(ids, group.ID)
+	}
+	return ids
+}
+
+func typeIDs(items []*skillz.Type) []uint {
+	ids := make([]uint, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	return ids
+}
@@ -0,0 +1,305 @@
+package repotest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/eveisesi/skillz"
+	"github.com/volatiletech/null"
+)
+
+var userTests = []test{
+	{"UserNotFound", func(t *testing.T, r *Repositories) {
+		_, err := r.User.User(ctx, "user-1")
+		requireNoRows(t, err)
+
+		_, err = r.User.UserByCharacterID(ctx, 90000001)
+		requireNoRows(t, err)
+
+		_, err = r.User.UserSettings(ctx, "user-1")
+		requireNoRows(t, err)
+
+		_, err = r.User.UserAccount(ctx, "user-1")
+		requireNoRows(t, err)
+
+		_, err = r.User.UserShareLink(ctx, "token")
+		requireNoRows(t, err)
+	}},
+	{"CreateUserUpserts", func(t *testing.T, r *Repositories) {
+		user := seedUser(t, r, "user-1", 90000001)
+
+		got, err := r.User.User(ctx, "user-1")
+		requireNoError(t, err)
+		requireEqual(t, "CharacterID", user.CharacterID, got.CharacterID)
+		requireEqual(t, "OwnerHash", user.OwnerHash, got.OwnerHash)
+		requireEqual(t, "IsNew", true, got.IsNew)
+		requireLen(t, "Scopes", 2, len(got.Scopes))
+		requireTime(t, "Expires", user.Expires, got.Expires)
+
+		user.CharacterID = 90000002
+		user.AccessToken = "rotated"
+		user.IsNew = false
+		user.Disabled = true
+		user.DisabledReason = null.StringFrom("token revoked")
+		requireNoError(t, r.User.CreateUser(ctx, user))
+
+		updated, err := r.User.UserByCharacterID(ctx, 90000001)
+		requireNoError(t, err)
+		// The character of an existing user is never changed
+		requireEqual(t, "CharacterID", uint64(90000001), updated.CharacterID)
+		requireEqual(t, "AccessToken", "rotated", updated.AccessToken)
+		requireEqual(t, "IsNew", false, updated.IsNew)
+		requireEqual(t, "Disabled", true, updated.Disabled)
+		requireEqual(t, "DisabledReason", user.DisabledReason, updated.DisabledReason)
+		requireTime(t, "CreatedAt", got.CreatedAt, updated.CreatedAt)
+
+		_, err = r.User.UserByCharacterID(ctx, 90000002)
+		requireNoRows(t, err)
+	}},
+	{"SearchUsers", func(t *testing.T, r *Repositories) {
+		seedUser(t, r, "user-1", 90000001)
+		seedUser(t, r, "user-2", 90000002)
+		seedCharacter(t, r, 90000001, "Fixture Pilot", 98000001, null.Uint{})
+		seedCharacter(t, r, 90000002, "Other Capsuleer", 98000001, null.Uint{})
+
+		users, err := r.User.SearchUsers(ctx, "pilot")
+		requireNoError(t, err)
+		requireLen(t, "SearchUsers", 1, len(users))
+		requireEqual(t, "ID", "user-1", users[0].ID)
+
+		users, err = r.User.SearchUsers(ctx, "nobody")
+		requireNoError(t, err)
+		requireLen(t, "SearchUsers", 0, len(users))
+	}},
+	{"UsersSortedByProcessedAt", func(t *testing.T, r *Repositories) {
+		processed := seedUser(t, r, "user-1", 90000001)
+		seedUser(t, r, "user-2", 90000002)
+		earlier := seedUser(t, r, "user-3", 90000003)
+		disabled := seedUser(t, r, "user-4", 90000004)
+
+		processed.LastProcessed = null.TimeFrom(epoch.Add(time.Hour))
+		requireNoError(t, r.User.CreateUser(ctx, processed))
+		earlier.LastProcessed = null.TimeFrom(epoch)
+		requireNoError(t, r.User.CreateUser(ctx, earlier))
+		disabled.Disabled = true
+		requireNoError(t, r.User.CreateUser(ctx, disabled))
+
+		users, err := r.User.UsersSortedByProcessedAtLimit(ctx)
+		requireNoError(t, err)
+		requireLen(t, "UsersSortedByProcessedAtLimit", 3, len(users))
+		// Users that have never been processed come first
+		requireEqual(t, "ID", "user-2", users[0].ID)
+		requireEqual(t, "ID", "user-3", users[1].ID)
+		requireEqual(t, "ID", "user-1", users[2].ID)
+	}},
+	{"UserSettingsUpsert", func(t *testing.T, r *Repositories) {
+		seedUser(t, r, "user-1", 90000001)
+
+		settings := &skillz.UserSettings{UserID: "user-1", Visibility: skillz.VisibilityPrivate, VisibilityToken: "secret", HideQueue: true}
+		requireNoError(t, r.User.CreateUserSettings(ctx, settings))
+
+		got, err := r.User.UserSettings(ctx, "user-1")
+		requireNoError(t, err)
+		requireEqual(t, "Visibility", skillz.VisibilityPrivate, got.Visibility)
+		requireEqual(t, "HideQueue", true, got.HideQueue)
+
+		settings.Visibility = skillz.VisibilityPublic
+		settings.HideQueue = false
+		settings.HideStandings = true
+		settings.ShareWithCorporation = true
+		requireNoError(t, r.User.CreateUserSettings(ctx, settings))
+
+		updated, err := r.User.UserSettings(ctx, "user-1")
+		requireNoError(t, err)
+		requireEqual(t, "Visibility", skillz.VisibilityPublic, updated.Visibility)
+		requireEqual(t, "VisibilityToken", "secret", updated.VisibilityToken)
+		requireEqual(t, "HideQueue", false, updated.HideQueue)
+		requireEqual(t, "HideStandings", true, updated.HideStandings)
+		requireEqual(t, "ShareWithCorporation", true, updated.ShareWithCorporation)
+		requireTime(t, "CreatedAt", got.CreatedAt, updated.CreatedAt)
+	}},
+	{"NewUsersBySP", func(t *testing.T, r *Repositories) {
+		seedUser(t, r, "user-1", 90000001)
+		seedUser(t, r, "user-2", 90000002)
+		seedUser(t, r, "user-3", 90000003)
+
+		requireNoError(t, r.User.CreateUserSettings(ctx, &skillz.UserSettings{UserID: "user-1", Visibility: skillz.VisibilityPublic}))
+		requireNoError(t, r.User.CreateUserSettings(ctx, &skillz.UserSettings{UserID: "user-2", Visibility: skillz.VisibilityPrivate}))
+
+		// Only the new users whose skillboards are public are listed
+		users, err := r.User.NewUsersBySP(ctx)
+		requireNoError(t, err)
+		requireLen(t, "NewUsersBySP", 1, len(users))
+		requireEqual(t, "ID", "user-1", users[0].ID)
+	}},
+	{"UsersSharingWithCorporation", func(t *testing.T, r *Repositories) {
+		for i, id := range []string{"user-1", "user-2", "user-3", "user-4", "user-5"} {
+			seedUser(t, r, id, 90000001+uint64(i))
+		}
+		seedCharacter(t, r, 90000001, "Member", 98000001, null.Uint{})
+		seedCharacter(t, r, 90000002, "Ally", 98000002, null.UintFrom(99000001))
+		seedCharacter(t, r, 90000003, "Private Member", 98000001, null.Uint{})
+		seedCharacter(t, r, 90000004, "Stranger", 98000003, null.UintFrom(99000002))
+
+		share := func(userID string, share bool) {
+			requireNoError(t, r.User.CreateUserSettings(ctx, &skillz.UserSettings{UserID: userID, Visibility: skillz.VisibilityPrivate, ShareWithCorporation: share}))
+		}
+		share("user-1", true)
+		share("user-2", true)
+		share("user-3", false)
+		share("user-4", true)
+		// user-5 shares but has no character
+		share("user-5", true)
+
+		users, err := r.User.UsersSharingWithCorporation(ctx, 98000001, null.Uint{})
+		requireNoError(t, err)
+		requireLen(t, "UsersSharingWithCorporation", 1, len(users))
+		requireEqual(t, "ID", "user-1", users[0].ID)
+
+		users, err = r.User.UsersSharingWithCorporation(ctx, 98000001, null.UintFrom(99000001))
+		requireNoError(t, err)
+		requireLen(t, "UsersSharingWithCorporation", 2, len(users))
+	}},
+	{"UserAccounts", func(t *testing.T, r *Repositories) {
+		seedUser(t, r, "user-1", 90000001)
+		seedUser(t, r, "user-2", 90000002)
+
+		requireNoError(t, r.User.CreateUserAccount(ctx, &skillz.UserAccount{UserID: "user-1", AccountID: "account-1", OwnerHash: "hash-user-1"}))
+		requireNoError(t, r.User.CreateUserAccount(ctx, &skillz.UserAccount{UserID: "user-2", AccountID: "account-1", OwnerHash: "hash-user-2"}))
+
+		accounts, err := r.User.UserAccountsByAccountID(ctx, "account-1")
+		requireNoError(t, err)
+		requireLen(t, "UserAccountsByAccountID", 2, len(accounts))
+
+		// Linking a user again moves them to the other account
+		requireNoError(t, r.User.CreateUserAccount(ctx, &skillz.UserAccount{UserID: "user-2", AccountID: "account-2", OwnerHash: "rotated"}))
+
+		account, err := r.User.UserAccount(ctx, "user-2")
+		requireNoError(t, err)
+		requireEqual(t, "AccountID", "account-2", account.AccountID)
+		requireEqual(t, "OwnerHash", "rotated", account.OwnerHash)
+
+		accounts, err = r.User.UserAccountsByAccountID(ctx, "account-1")
+		requireNoError(t, err)
+		requireLen(t, "UserAccountsByAccountID", 1, len(accounts))
+
+		requireNoError(t, r.User.DeleteUserAccount(ctx, "user-2"))
+		_, err = r.User.UserAccount(ctx, "user-2")
+		requireNoRows(t, err)
+	}},
+	{"UserShareLinks", func(t *testing.T, r *Repositories) {
+		seedUser(t, r, "user-1", 90000001)
+		seedUser(t, r, "user-2", 90000002)
+
+		link := &skillz.UserShareLink{
+			Token:       "token-1",
+			UserID:      "user-1",
+			Label:       "Recruiter",
+			ShareSkills: true,
+			ShareQueue:  true,
+			ExpiresAt:   epoch.AddDate(1, 0, 0),
+		}
+		requireNoError(t, r.User.CreateUserShareLink(ctx, link))
+		requireError(t, r.User.CreateUserShareLink(ctx, link))
+		requireNoError(t, r.User.CreateUserShareLink(ctx, &skillz.UserShareLink{Token: "token-2", UserID: "user-1", Label: "Friend", ExpiresAt: epoch}))
+
+		got, err := r.User.UserShareLink(ctx, "token-1")
+		requireNoError(t, err)
+		requireEqual(t, "Label", "Recruiter", got.Label)
+		requireEqual(t, "ShareQueue", true, got.ShareQueue)
+		requireEqual(t, "ShareImplants", false, got.ShareImplants)
+		requireTime(t, "ExpiresAt", link.ExpiresAt, got.ExpiresAt)
+
+		links, err := r.User.UserShareLinks(ctx, "user-1")
+		requireNoError(t, err)
+		requireLen(t, "UserShareLinks", 2, len(links))
+
+		requireNoError(t, r.User.CreateUserShareLinkView(ctx, &skillz.UserShareLinkView{Token: "token-1", IPAddress: "127.0.0.1", UserAgent: "Mozilla/5.0"}))
+		view := &skillz.UserShareLinkView{Token: "token-1", ViewerID: null.StringFrom("user-2"), IPAddress: "127.0.0.2", UserAgent: "Mozilla/5.0"}
+		requireNoError(t, r.User.CreateUserShareLinkView(ctx, view))
+		requireRecent(t, "ViewedAt", view.ViewedAt)
+
+		views, err := r.User.UserShareLinkViews(ctx, "token-1")
+		requireNoError(t, err)
+		requireLen(t, "UserShareLinkViews", 2, len(views))
+		if views[0].ID == 0 || views[0].ID == views[1].ID {
+			t.Fatalf("expected distinct ids to be assigned to the views, got %d and %d", views[0].ID, views[1].ID)
+		}
+
+		// A link is only deleted by the user that it belongs to
+		requireNoError(t, r.User.DeleteUserShareLink(ctx, "user-2", "token-1"))
+		_, err = r.User.UserShareLink(ctx, "token-1")
+		requireNoError(t, err)
+
+		requireNoError(t, r.User.DeleteUserShareLink(ctx, "user-1", "token-1"))
+		_, err = r.User.UserShareLink(ctx, "token-1")
+		requireNoRows(t, err)
+
+		views, err = r.User.UserShareLinkViews(ctx, "token-1")
+		requireNoError(t, err)
+		requireLen(t, "UserShareLinkViews", 0, len(views))
+	}},
+	{"DeleteUserCascades", func(t *testing.T, r *Repositories) {
+		user := seedUser(t, r, "user-1", 90000001)
+		seedUser(t, r, "user-2", 90000002)
+		seedTypes(t, r, 257, 3327)
+
+		requireNoError(t, r.User.CreateUserSettings(ctx, &skillz.UserSettings{UserID: "user-1", Visibility: skillz.VisibilityPublic}))
+		requireNoError(t, r.User.CreateUserAccount(ctx, &skillz.UserAccount{UserID: "user-1", AccountID: "account-1", OwnerHash: "hash-user-1"}))
+		requireNoError(t, r.User.CreateUserShareLink(ctx, &skillz.UserShareLink{Token: "token-1", UserID: "user-1", Label: "Recruiter", ExpiresAt: epoch}))
+
+		plan := &skillz.SkillPlan{UserID: "user-1", Name: "Plan"}
+		requireNoError(t, r.Skill.CreateSkillPlan(ctx, plan))
+		requireNoError(t, r.Skill.CreateSkillPlanEntries(ctx, []*skillz.SkillPlanEntry{{PlanID: plan.ID, Position: 0, SkillID: 3327, Level: 1}}))
+
+		for _, characterID := range []uint64{90000001, 90000002} {
+			requireNoError(t, r.Skill.CreateCharacterSkills(ctx, []*skillz.CharacterSkill{{CharacterID: characterID, SkillID: 3327, ActiveSkillLevel: 1, TrainedSkillLevel: 1, SkillpointsInSkill: 250}}))
+			requireNoError(t, r.Skill.CreateCharacterSkillMeta(ctx, &skillz.CharacterSkillMeta{CharacterID: characterID, TotalSP: 250}))
+		}
+
+		requireNoError(t, r.User.DeleteUser(ctx, user))
+
+		_, err := r.User.User(ctx, "user-1")
+		requireNoRows(t, err)
+		_, err = r.User.UserSettings(ctx, "user-1")
+		requireNoRows(t, err)
+		_, err = r.User.UserAccount(ctx, "user-1")
+		requireNoRows(t, err)
+		_, err = r.User.UserShareLink(ctx, "token-1")
+		requireNoRows(t, err)
+		_, err = r.Skill.SkillPlan(ctx, plan.ID)
+		requireNoRows(t, err)
+		_, err = r.Skill.CharacterSkillMeta(ctx, 90000001)
+		requireNoRows(t, err)
+
+		entries, err := r.Skill.SkillPlanEntries(ctx, plan.ID)
+		requireNoError(t, err)
+		requireLen(t, "SkillPlanEntries", 0, len(entries))
+
+		skills, err := r.Skill.CharacterSkills(ctx, 90000001)
+		requireNoError(t, err)
+		requireLen(t, "CharacterSkills", 0, len(skills))
+
+		// The rows of other users are left alone
+		skills, err = r.Skill.CharacterSkills(ctx, 90000002)
+		requireNoError(t, err)
+		requireLen(t, "CharacterSkills", 1, len(skills))
+		_, err = r.Skill.CharacterSkillMeta(ctx, 90000002)
+		requireNoError(t, err)
+	}},
+}
+
+func seedCharacter(t *testing.T, r *Repositories, id uint64, name string, corporationID uint, allianceID null.Uint) {
+	t.Helper()
+
+	requireNoError(t, r.Character.CreateCharacter(ctx, &skillz.Character{
+		ID:            id,
+		Name:          name,
+		CorporationID: corporationID,
+		AllianceID:    allianceID,
+		Gender:        "male",
+		Birthday:      epoch,
+		BloodlineID:   1,
+		RaceID:        1,
+	}))
+}
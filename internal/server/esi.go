@@ -0,0 +1,27 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/newrelic/go-agent/v3/newrelic"
+	"github.com/pkg/errors"
+)
+
+// handleGetESIErrorBudget returns ESI's error budget shared by the processes making requests to ESI.
+// The budget is null when ESI has not reported one for the current window
+func (s *Server) handleGetESIErrorBudget(w http.ResponseWriter, r *http.Request) {
+
+	var ctx = r.Context()
+	var nr = newrelic.FromContext(ctx)
+
+	budget, err := s.esi.ErrorBudget(ctx)
+	if err != nil {
+		nr.NoticeError(err)
+		s.logger.WithError(err).Error("failed to fetch esi error budget")
+		s.writeError(ctx, w, http.StatusInternalServerError, errors.New("failed to fetch esi error budget"))
+		return
+	}
+
+	s.writeResponse(ctx, w, http.StatusOK, budget)
+
+}
@@ -0,0 +1,60 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/eveisesi/skillz"
+	"github.com/eveisesi/skillz/internal"
+	"github.com/eveisesi/skillz/internal/fitting"
+	"github.com/newrelic/go-agent/v3/newrelic"
+	"github.com/pkg/errors"
+)
+
+// fittingCheckRequest accepts either a fitting in the EFT format or
+// one or more fittings in the format of the ESI fittings endpoint
+type fittingCheckRequest struct {
+	EFT string          `json:"eft"`
+	ESI json.RawMessage `json:"esi"`
+}
+
+func (s *Server) handlePostFittingCheck(w http.ResponseWriter, r *http.Request) {
+
+	var ctx = r.Context()
+	var user = internal.UserFromContext(ctx)
+
+	var body = new(fittingCheckRequest)
+	err := json.NewDecoder(r.Body).Decode(body)
+	if err != nil {
+		s.writeError(ctx, w, http.StatusBadRequest, errors.New("failed to decode request body"))
+		return
+	}
+
+	var reports []*skillz.FittingReport
+	switch {
+	case body.EFT != "":
+		var report *skillz.FittingReport
+		report, err = s.fittings.CheckEFT(ctx, user.CharacterID, body.EFT)
+		if report != nil {
+			reports = []*skillz.FittingReport{report}
+		}
+	case len(body.ESI) > 0:
+		reports, err = s.fittings.CheckESI(ctx, user.CharacterID, body.ESI)
+	default:
+		err = errors.Wrap(fitting.ErrInvalidFitting, "one of eft or esi is required")
+	}
+	if err != nil {
+		if errors.Is(err, fitting.ErrInvalidFitting) {
+			s.writeError(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		newrelic.FromContext(ctx).NoticeError(err)
+		s.logger.WithError(err).Error("failed to check fitting")
+		s.writeError(ctx, w, http.StatusInternalServerError, errors.New("failed to check fitting"))
+		return
+	}
+
+	s.writeResponse(ctx, w, http.StatusOK, reports)
+
+}
@@ -3,10 +3,13 @@ package server
 import (
 	"fmt"
 	"net/http"
+	"strings"
 
+	"github.com/eveisesi/skillz/internal"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/newrelic/go-agent/v3/newrelic"
+	"github.com/pkg/errors"
 )
 
 func (s *Server) monitoring(next http.Handler) http.Handler {
@@ -38,3 +41,40 @@ func (s *Server) monitoring(next http.Handler) http.Handler {
 func (s *Server) requestLogger() func(next http.Handler) http.Handler {
 	return middleware.RequestLogger(&structuredLogger{s.logger})
 }
+
+// authorize authenticates the request using the ESI access token provided as a Bearer token
+// in the Authorization header. The request is rejected if the token is invalid or the character
+// of the token has not registered with us
+func (s *Server) authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		var ctx = r.Context()
+
+		header := r.Header.Get("Authorization")
+		if !strings.HasPrefix(header, "Bearer ") {
+			s.writeError(ctx, w, http.StatusUnauthorized, errors.New("missing bearer token in authorization header"))
+			return
+		}
+
+		token, err := s.auth.ParseAndVerifyESIToken(ctx, strings.TrimPrefix(header, "Bearer "))
+		if err != nil {
+			s.writeError(ctx, w, http.StatusUnauthorized, errors.New("failed to verify bearer token"))
+			return
+		}
+
+		user, err := s.users.UserFromToken(ctx, token)
+		if err != nil {
+			s.logger.WithError(err).Error("failed to fetch user for token")
+			s.writeError(ctx, w, http.StatusUnauthorized, errors.New("failed to fetch user for bearer token"))
+			return
+		}
+
+		if user.IsNew {
+			s.writeError(ctx, w, http.StatusUnauthorized, errors.New("character of bearer token is not registered"))
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(internal.ContextWithUser(ctx, user)))
+
+	})
+}
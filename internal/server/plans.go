@@ -0,0 +1,243 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/eveisesi/skillz"
+	"github.com/eveisesi/skillz/internal"
+	"github.com/eveisesi/skillz/internal/skill"
+	"github.com/go-chi/chi/v5"
+	"github.com/newrelic/go-agent/v3/newrelic"
+	"github.com/pkg/errors"
+)
+
+type skillPlanRequest struct {
+	Name    string                   `json:"name"`
+	Entries []*skillz.SkillPlanEntry `json:"entries"`
+}
+
+type skillPlanOrderRequest struct {
+	Order []uint `json:"order"`
+}
+
+func (s *Server) handleGetSkillPlans(w http.ResponseWriter, r *http.Request) {
+
+	var ctx = r.Context()
+	var user = internal.UserFromContext(ctx)
+
+	plans, err := s.skills.SkillPlans(ctx, user)
+	if err != nil {
+		s.handleSkillPlanError(w, r, err)
+		return
+	}
+
+	s.writeResponse(ctx, w, http.StatusOK, plans)
+
+}
+
+func (s *Server) handlePostSkillPlans(w http.ResponseWriter, r *http.Request) {
+
+	var ctx = r.Context()
+	var user = internal.UserFromContext(ctx)
+
+	var body = new(skillPlanRequest)
+	err := json.NewDecoder(r.Body).Decode(body)
+	if err != nil {
+		s.writeError(ctx, w, http.StatusBadRequest, errors.New("failed to decode request body"))
+		return
+	}
+
+	plan, err := s.skills.CreateSkillPlan(ctx, user, body.Name, body.Entries)
+	if err != nil {
+		s.handleSkillPlanError(w, r, err)
+		return
+	}
+
+	s.writeResponse(ctx, w, http.StatusCreated, plan)
+
+}
+
+func (s *Server) handleGetSkillPlan(w http.ResponseWriter, r *http.Request) {
+
+	var ctx = r.Context()
+	var user = internal.UserFromContext(ctx)
+
+	planID, err := strconv.ParseUint(chi.URLParam(r, "planID"), 10, 32)
+	if err != nil {
+		s.writeError(ctx, w, http.StatusBadRequest, errors.New("invalid plan id"))
+		return
+	}
+
+	plan, err := s.skills.SkillPlan(ctx, user, uint(planID))
+	if err != nil {
+		s.handleSkillPlanError(w, r, err)
+		return
+	}
+
+	s.writeResponse(ctx, w, http.StatusOK, plan)
+
+}
+
+func (s *Server) handlePatchSkillPlan(w http.ResponseWriter, r *http.Request) {
+
+	var ctx = r.Context()
+	var user = internal.UserFromContext(ctx)
+
+	planID, err := strconv.ParseUint(chi.URLParam(r, "planID"), 10, 32)
+	if err != nil {
+		s.writeError(ctx, w, http.StatusBadRequest, errors.New("invalid plan id"))
+		return
+	}
+
+	var body = new(skillPlanRequest)
+	err = json.NewDecoder(r.Body).Decode(body)
+	if err != nil {
+		s.writeError(ctx, w, http.StatusBadRequest, errors.New("failed to decode request body"))
+		return
+	}
+
+	plan, err := s.skills.RenameSkillPlan(ctx, user, uint(planID), body.Name)
+	if err != nil {
+		s.handleSkillPlanError(w, r, err)
+		return
+	}
+
+	s.writeResponse(ctx, w, http.StatusOK, plan)
+
+}
+
+func (s *Server) handleDeleteSkillPlan(w http.ResponseWriter, r *http.Request) {
+
+	var ctx = r.Context()
+	var user = internal.UserFromContext(ctx)
+
+	planID, err := strconv.ParseUint(chi.URLParam(r, "planID"), 10, 32)
+	if err != nil {
+		s.writeError(ctx, w, http.StatusBadRequest, errors.New("invalid plan id"))
+		return
+	}
+
+	err = s.skills.DeleteSkillPlan(ctx, user, uint(planID))
+	if err != nil {
+		s.handleSkillPlanError(w, r, err)
+		return
+	}
+
+	s.writeResponse(ctx, w, http.StatusNoContent, nil)
+
+}
+
+func (s *Server) handlePutSkillPlanEntries(w http.ResponseWriter, r *http.Request) {
+
+	var ctx = r.Context()
+	var user = internal.UserFromContext(ctx)
+
+	planID, err := strconv.ParseUint(chi.URLParam(r, "planID"), 10, 32)
+	if err != nil {
+		s.writeError(ctx, w, http.StatusBadRequest, errors.New("invalid plan id"))
+		return
+	}
+
+	var body = new(skillPlanRequest)
+	err = json.NewDecoder(r.Body).Decode(body)
+	if err != nil {
+		s.writeError(ctx, w, http.StatusBadRequest, errors.New("failed to decode request body"))
+		return
+	}
+
+	plan, err := s.skills.UpdateSkillPlanEntries(ctx, user, uint(planID), body.Entries)
+	if err != nil {
+		s.handleSkillPlanError(w, r, err)
+		return
+	}
+
+	s.writeResponse(ctx, w, http.StatusOK, plan)
+
+}
+
+func (s *Server) handlePutSkillPlanOrder(w http.ResponseWriter, r *http.Request) {
+
+	var ctx = r.Context()
+	var user = internal.UserFromContext(ctx)
+
+	planID, err := strconv.ParseUint(chi.URLParam(r, "planID"), 10, 32)
+	if err != nil {
+		s.writeError(ctx, w, http.StatusBadRequest, errors.New("invalid plan id"))
+		return
+	}
+
+	var body = new(skillPlanOrderRequest)
+	err = json.NewDecoder(r.Body).Decode(body)
+	if err != nil {
+		s.writeError(ctx, w, http.StatusBadRequest, errors.New("failed to decode request body"))
+		return
+	}
+
+	plan, err := s.skills.ReorderSkillPlan(ctx, user, uint(planID), body.Order)
+	if err != nil {
+		s.handleSkillPlanError(w, r, err)
+		return
+	}
+
+	s.writeResponse(ctx, w, http.StatusOK, plan)
+
+}
+
+func (s *Server) handleGetSkillPlanRemap(w http.ResponseWriter, r *http.Request) {
+
+	var ctx = r.Context()
+	var user = internal.UserFromContext(ctx)
+
+	planID, err := strconv.ParseUint(chi.URLParam(r, "planID"), 10, 32)
+	if err != nil {
+		s.writeError(ctx, w, http.StatusBadRequest, errors.New("invalid plan id"))
+		return
+	}
+
+	remap, err := s.skills.SkillPlanRemap(ctx, user, uint(planID))
+	if err != nil {
+		s.handleSkillPlanError(w, r, err)
+		return
+	}
+
+	s.writeResponse(ctx, w, http.StatusOK, remap)
+
+}
+
+func (s *Server) handleGetSkillQueueRemap(w http.ResponseWriter, r *http.Request) {
+
+	var ctx = r.Context()
+	var nr = newrelic.FromContext(ctx)
+	var user = internal.UserFromContext(ctx)
+
+	remap, err := s.skills.SkillQueueRemap(ctx, user.CharacterID)
+	if err != nil {
+		nr.NoticeError(err)
+		s.logger.WithError(err).Error("failed to calculate skill queue remap")
+		s.writeError(ctx, w, http.StatusInternalServerError, errors.New("failed to calculate skill queue remap"))
+		return
+	}
+
+	s.writeResponse(ctx, w, http.StatusOK, remap)
+
+}
+
+func (s *Server) handleSkillPlanError(w http.ResponseWriter, r *http.Request, err error) {
+
+	var ctx = r.Context()
+
+	switch {
+	case errors.Is(err, skill.ErrSkillPlanNotFound):
+		s.writeError(ctx, w, http.StatusNotFound, err)
+	case errors.Is(err, skill.ErrInvalidSkillPlan), errors.Is(err, skill.ErrInvalidSkillPlanEntry),
+		errors.Is(err, skill.ErrCyclicPrerequisites), errors.Is(err, skill.ErrImpossiblePrerequisite):
+		s.writeError(ctx, w, http.StatusBadRequest, err)
+	default:
+		newrelic.FromContext(ctx).NoticeError(err)
+		s.logger.WithError(err).Error("failed to handle skill plan request")
+		s.writeError(ctx, w, http.StatusInternalServerError, errors.New("failed to handle skill plan request"))
+	}
+
+}
@@ -8,6 +8,9 @@ import (
 
 	"github.com/eveisesi/skillz"
 	"github.com/eveisesi/skillz/internal/auth"
+	"github.com/eveisesi/skillz/internal/esi"
+	"github.com/eveisesi/skillz/internal/fitting"
+	"github.com/eveisesi/skillz/internal/skill"
 	"github.com/eveisesi/skillz/internal/user/v2"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -19,8 +22,11 @@ type Server struct {
 	logger   *logrus.Logger
 	newrelic *newrelic.Application
 
-	auth  auth.API
-	users user.API
+	auth     auth.API
+	esi      esi.BudgetAPI
+	users    user.API
+	skills   skill.API
+	fittings fitting.API
 
 	http *http.Server
 }
@@ -32,12 +38,15 @@ type Response struct {
 	Users   []*skillz.User
 }
 
-func New(logger *logrus.Logger, newrelic *newrelic.Application, auth auth.API, user user.API) *Server {
+func New(logger *logrus.Logger, newrelic *newrelic.Application, auth auth.API, esi esi.BudgetAPI, user user.API, skills skill.API, fittings fitting.API) *Server {
 	s := &Server{
 		logger:   logger,
 		newrelic: newrelic,
 		auth:     auth,
+		esi:      esi,
 		users:    user,
+		skills:   skills,
+		fittings: fittings,
 	}
 
 	s.http = &http.Server{
@@ -63,7 +72,24 @@ func (s *Server) buildRouter() *chi.Mux {
 		middleware.SetHeader("Content-Type", "application/json"),
 	)
 	r.Get("/recent", s.handleGetRecent)
+	r.Get("/esi/budget", s.handleGetESIErrorBudget)
 	r.Get("/users/{userID}", s.handleGetRecent)
+	r.Route("/users/plans", func(r chi.Router) {
+		r.Use(s.authorize)
+		r.Get("/", s.handleGetSkillPlans)
+		r.Post("/", s.handlePostSkillPlans)
+		r.Get("/{planID}", s.handleGetSkillPlan)
+		r.Patch("/{planID}", s.handlePatchSkillPlan)
+		r.Delete("/{planID}", s.handleDeleteSkillPlan)
+		r.Put("/{planID}/entries", s.handlePutSkillPlanEntries)
+		r.Put("/{planID}/order", s.handlePutSkillPlanOrder)
+		r.Get("/{planID}/remap", s.handleGetSkillPlanRemap)
+	})
+	r.With(s.authorize).Get("/users/queue/remap", s.handleGetSkillQueueRemap)
+	r.With(s.authorize).Get("/users/timeline", s.handleGetSkillTimeline)
+	r.With(s.authorize).Get("/users/compare", s.handleGetUserComparison)
+	r.With(s.authorize).Get("/users/ships/{shipID}/requirements", s.handleGetShipRequirements)
+	r.With(s.authorize).Post("/users/fittings/check", s.handlePostFittingCheck)
 	return r
 }
 
@@ -0,0 +1,41 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/eveisesi/skillz/internal"
+	"github.com/eveisesi/skillz/internal/skill"
+	"github.com/go-chi/chi/v5"
+	"github.com/newrelic/go-agent/v3/newrelic"
+	"github.com/pkg/errors"
+)
+
+func (s *Server) handleGetShipRequirements(w http.ResponseWriter, r *http.Request) {
+
+	var ctx = r.Context()
+	var nr = newrelic.FromContext(ctx)
+	var user = internal.UserFromContext(ctx)
+
+	shipID, err := strconv.ParseUint(chi.URLParam(r, "shipID"), 10, 32)
+	if err != nil {
+		s.writeError(ctx, w, http.StatusBadRequest, errors.New("invalid ship id"))
+		return
+	}
+
+	requirements, err := s.skills.FlightRequirements(ctx, user.CharacterID, uint(shipID))
+	if err != nil {
+		if errors.Is(err, skill.ErrShipNotFound) {
+			s.writeError(ctx, w, http.StatusNotFound, err)
+			return
+		}
+
+		nr.NoticeError(err)
+		s.logger.WithError(err).Error("failed to calculate ship flight requirements")
+		s.writeError(ctx, w, http.StatusInternalServerError, errors.New("failed to calculate ship flight requirements"))
+		return
+	}
+
+	s.writeResponse(ctx, w, http.StatusOK, requirements)
+
+}
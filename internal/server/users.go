@@ -2,8 +2,12 @@ package server
 
 import (
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/eveisesi/skillz"
+	"github.com/eveisesi/skillz/internal"
+	"github.com/eveisesi/skillz/internal/user/v2"
 	"github.com/newrelic/go-agent/v3/newrelic"
 	"github.com/pkg/errors"
 )
@@ -27,3 +31,82 @@ func (s *Server) handleGetRecent(w http.ResponseWriter, r *http.Request) {
 	}{recent, highlighted})
 
 }
+
+const defaultTimelinePeriod = time.Hour * 24 * 30
+
+// handleGetSkillTimeline returns the authenticated character's skill timeline. The optional from and
+// to query parameters are RFC3339 timestamps and default to the last 30 days
+func (s *Server) handleGetSkillTimeline(w http.ResponseWriter, r *http.Request) {
+
+	var ctx = r.Context()
+	var nr = newrelic.FromContext(ctx)
+	var user = internal.UserFromContext(ctx)
+
+	to := time.Now()
+	if v := r.URL.Query().Get("to"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			s.writeError(ctx, w, http.StatusBadRequest, errors.New("invalid value for to, expected an RFC3339 timestamp"))
+			return
+		}
+		to = t
+	}
+
+	from := to.Add(-defaultTimelinePeriod)
+	if v := r.URL.Query().Get("from"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			s.writeError(ctx, w, http.StatusBadRequest, errors.New("invalid value for from, expected an RFC3339 timestamp"))
+			return
+		}
+		from = t
+	}
+
+	if from.After(to) {
+		s.writeError(ctx, w, http.StatusBadRequest, errors.New("from must be before to"))
+		return
+	}
+
+	timeline, err := s.skills.SkillTimeline(ctx, user.CharacterID, from, to)
+	if err != nil {
+		nr.NoticeError(err)
+		s.logger.WithError(err).Error("failed to fetch skill timeline")
+		s.writeError(ctx, w, http.StatusInternalServerError, errors.New("failed to fetch skill timeline"))
+		return
+	}
+
+	s.writeResponse(ctx, w, http.StatusOK, timeline)
+
+}
+
+// handleGetUserComparison compares the users provided through the comma separated ids query parameter.
+// Users the authenticated character is not allowed to view are reported as not found
+func (s *Server) handleGetUserComparison(w http.ResponseWriter, r *http.Request) {
+
+	var ctx = r.Context()
+	var nr = newrelic.FromContext(ctx)
+	var viewer = internal.UserFromContext(ctx)
+
+	ids := strings.Split(r.URL.Query().Get("ids"), ",")
+	for i, id := range ids {
+		ids[i] = strings.TrimSpace(id)
+	}
+
+	comparison, err := s.users.CompareUsers(ctx, viewer, ids)
+	if err != nil {
+		switch {
+		case errors.Is(err, user.ErrInvalidComparison):
+			s.writeError(ctx, w, http.StatusBadRequest, err)
+		case errors.Is(err, user.ErrUserNotFound):
+			s.writeError(ctx, w, http.StatusNotFound, err)
+		default:
+			nr.NoticeError(err)
+			s.logger.WithError(err).Error("failed to compare users")
+			s.writeError(ctx, w, http.StatusInternalServerError, errors.New("failed to compare users"))
+		}
+		return
+	}
+
+	s.writeResponse(ctx, w, http.StatusOK, comparison)
+
+}
@@ -0,0 +1,108 @@
+package skill
+
+import (
+	"context"
+
+	"github.com/eveisesi/skillz"
+	"github.com/pkg/errors"
+)
+
+// implantBonusAttributeIDs maps the dogma attribute of an implant's bonus to the attribute it boosts
+var implantBonusAttributeIDs = map[uint]uint{
+	skillz.CharismaBonusAttributeID:     skillz.CharismaAttributeID,
+	skillz.IntelligenceBonusAttributeID: skillz.IntelligenceAttributeID,
+	skillz.MemoryBonusAttributeID:       skillz.MemoryAttributeID,
+	skillz.PerceptionBonusAttributeID:   skillz.PerceptionAttributeID,
+	skillz.WillpowerBonusAttributeID:    skillz.WillpowerAttributeID,
+}
+
+// ImplantBonuses sums the attribute bonuses of the provided implants. Implants
+// that have not been hydrated with their type are ignored
+func ImplantBonuses(implants []*skillz.CharacterImplant) *skillz.CharacterAttributes {
+
+	bonuses := new(skillz.CharacterAttributes)
+	for _, implant := range implants {
+		if implant.Type == nil {
+			continue
+		}
+
+		bonuses.CharacterID = implant.CharacterID
+
+		for bonusAttributeID, attributeID := range implantBonusAttributeIDs {
+			attribute := implant.Type.GetAttribute(bonusAttributeID)
+			if attribute == nil || attribute.Value <= 0 {
+				continue
+			}
+
+			addAttributeValue(bonuses, attributeID, uint(attribute.Value))
+		}
+	}
+
+	return bonuses
+
+}
+
+// EffectiveAttributes returns a copy of the base attributes with the bonuses added to them
+func EffectiveAttributes(base, bonuses *skillz.CharacterAttributes) *skillz.CharacterAttributes {
+
+	if base == nil {
+		return nil
+	}
+
+	effective := *base
+	if bonuses == nil {
+		return &effective
+	}
+
+	effective.Charisma += bonuses.Charisma
+	effective.Intelligence += bonuses.Intelligence
+	effective.Memory += bonuses.Memory
+	effective.Perception += bonuses.Perception
+	effective.Willpower += bonuses.Willpower
+
+	return &effective
+
+}
+
+func addAttributeValue(attributes *skillz.CharacterAttributes, attributeID, value uint) {
+	switch attributeID {
+	case skillz.CharismaAttributeID:
+		attributes.Charisma += value
+	case skillz.IntelligenceAttributeID:
+		attributes.Intelligence += value
+	case skillz.MemoryAttributeID:
+		attributes.Memory += value
+	case skillz.PerceptionAttributeID:
+		attributes.Perception += value
+	case skillz.WillpowerAttributeID:
+		attributes.Willpower += value
+	}
+}
+
+// EffectiveAttributes returns the character's attributes including the bonuses of their active implants
+func (s *Service) EffectiveAttributes(ctx context.Context, characterID uint64) (*skillz.CharacterAttributes, error) {
+
+	attributes, bonuses, err := s.attributesAndBonuses(ctx, characterID)
+	if err != nil {
+		return nil, err
+	}
+
+	return EffectiveAttributes(attributes, bonuses), nil
+
+}
+
+func (s *Service) attributesAndBonuses(ctx context.Context, characterID uint64) (*skillz.CharacterAttributes, *skillz.CharacterAttributes, error) {
+
+	attributes, err := s.Attributes(ctx, characterID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	implants, err := s.clones.Implants(ctx, characterID)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to fetch character implants")
+	}
+
+	return attributes, ImplantBonuses(implants), nil
+
+}
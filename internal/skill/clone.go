@@ -0,0 +1,97 @@
+package skill
+
+import (
+	"context"
+
+	"github.com/eveisesi/skillz"
+	"github.com/pkg/errors"
+)
+
+// RankJumpClones sets the attribute bonuses of each clone's implants and how long the load would take to
+// train in the clone, then marks the clone that trains the load the fastest as the best for training. When
+// the load is empty, or the attributes are unknown, the clone with the largest total bonus is marked instead.
+// Clones whose implants do not have any attribute bonuses are never marked
+func RankJumpClones(clones []*skillz.CharacterJumpClone, attributes *skillz.CharacterAttributes, load TrainingLoad) {
+
+	var best *skillz.CharacterJumpClone
+	var bestTotal uint
+
+	for _, clone := range clones {
+		implants := make([]*skillz.CharacterImplant, 0, len(clone.Implants))
+		for _, implant := range clone.Implants {
+			implants = append(implants, &skillz.CharacterImplant{
+				CharacterID: implant.CharacterID,
+				ImplantID:   implant.ImplantID,
+				Slot:        implant.Slot,
+				Type:        implant.Type,
+			})
+		}
+
+		clone.Bonuses = ImplantBonuses(implants)
+		clone.BestForTraining = false
+		clone.QueueDuration = 0
+		if attributes != nil && len(load) > 0 {
+			clone.QueueDuration = load.Duration(EffectiveAttributes(attributes, clone.Bonuses))
+		}
+
+		total := clone.Bonuses.Charisma + clone.Bonuses.Intelligence + clone.Bonuses.Memory +
+			clone.Bonuses.Perception + clone.Bonuses.Willpower
+		if total == 0 {
+			continue
+		}
+
+		switch {
+		case best == nil:
+		case clone.QueueDuration > 0 && clone.QueueDuration < best.QueueDuration:
+		case clone.QueueDuration == best.QueueDuration && total > bestTotal:
+		default:
+			continue
+		}
+
+		best, bestTotal = clone, total
+	}
+
+	if best != nil {
+		best.BestForTraining = true
+	}
+
+}
+
+// JumpClones returns the character's jump clones ranked by how fast they would train the character's skill queue
+func (s *Service) JumpClones(ctx context.Context, characterID uint64) ([]*skillz.CharacterJumpClone, error) {
+
+	clones, err := s.clones.JumpClones(ctx, characterID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch character jump clones")
+	}
+
+	if len(clones) == 0 {
+		return clones, nil
+	}
+
+	attributes, err := s.Attributes(ctx, characterID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := s.SkillQueue(ctx, characterID)
+	if err != nil {
+		return nil, err
+	}
+
+	types, err := s.skillTypesByID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	load := make(TrainingLoad)
+	for _, position := range summary.Queue {
+		t := types[position.SkillID]
+		load.Add(t, QueuePositionSkillpoints(position, t))
+	}
+
+	RankJumpClones(clones, attributes, load)
+
+	return clones, nil
+
+}
@@ -0,0 +1,121 @@
+package skill
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/eveisesi/skillz"
+	"github.com/pkg/errors"
+)
+
+const maxSkillCompletions = 50
+
+// SkillCompletions detects the skill levels that finished training between two runs of the processor. A
+// position is finished when its finish date has passed, or when it has left the queue and the character's
+// trained level of the skill has reached the position's level. Positions that left the queue without being
+// trained, i.e. they were removed by the player, are not completions
+func SkillCompletions(previous, current []*skillz.CharacterSkillQueue, skills []*skillz.CharacterSkill, now time.Time) []*skillz.CharacterSkillCompletion {
+
+	trained := trainedLevels(skills)
+
+	inCurrent := make(map[string]bool, len(current))
+	for _, position := range current {
+		inCurrent[queuePositionKey(position)] = true
+	}
+
+	seen := make(map[string]bool)
+	completions := make([]*skillz.CharacterSkillCompletion, 0)
+
+	add := func(position *skillz.CharacterSkillQueue, completedAt time.Time) {
+		key := queuePositionKey(position)
+		if seen[key] {
+			return
+		}
+
+		seen[key] = true
+		completions = append(completions, &skillz.CharacterSkillCompletion{
+			CharacterID: position.CharacterID,
+			SkillID:     position.SkillID,
+			Level:       position.FinishedLevel,
+			CompletedAt: completedAt,
+		})
+	}
+
+	// ESI keeps finished positions in the queue until the character logs in
+	for _, position := range current {
+		if position.FinishDate.Valid && !position.FinishDate.Time.After(now) {
+			add(position, position.FinishDate.Time)
+		}
+	}
+
+	for _, position := range previous {
+		if inCurrent[queuePositionKey(position)] {
+			continue
+		}
+
+		switch {
+		case position.FinishDate.Valid && !position.FinishDate.Time.After(now):
+			add(position, position.FinishDate.Time)
+		case trained[position.SkillID] >= position.FinishedLevel:
+			add(position, now)
+		}
+	}
+
+	return completions
+
+}
+
+func queuePositionKey(position *skillz.CharacterSkillQueue) string {
+	return fmt.Sprintf("%d:%d", position.SkillID, position.FinishedLevel)
+}
+
+// SkillCompletions returns the most recent skill levels the character has finished training
+func (s *Service) SkillCompletions(ctx context.Context, characterID uint64) ([]*skillz.CharacterSkillCompletion, error) {
+
+	completions, err := s.skills.CharacterSkillCompletions(ctx, characterID, maxSkillCompletions)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.Wrap(err, "failed to fetch character skill completions from data store")
+	}
+
+	if len(completions) == 0 {
+		return completions, nil
+	}
+
+	types, err := s.skillTypesByID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, completion := range completions {
+		completion.Type = types[completion.SkillID]
+	}
+
+	return completions, nil
+
+}
+
+// recordSkillCompletions compares the character's previous queue against the updated queue
+// and records the positions that finished training. It must be called before the previous queue
+// is deleted and after the character's skills have been updated
+func (s *Service) recordSkillCompletions(ctx context.Context, user *skillz.User, previous, current []*skillz.CharacterSkillQueue) error {
+
+	skills, err := s.skills.CharacterSkills(ctx, user.CharacterID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return errors.Wrap(err, "failed to fetch character skills from data store")
+	}
+
+	completions := SkillCompletions(previous, current, skills, time.Now())
+	if len(completions) == 0 {
+		return nil
+	}
+
+	for _, completion := range completions {
+		completion.CharacterID = user.CharacterID
+	}
+
+	err = s.skills.CreateCharacterSkillCompletions(ctx, completions)
+	return errors.Wrap(err, "failed to create skill completions")
+
+}
@@ -0,0 +1,125 @@
+package skill
+
+import (
+	"context"
+
+	"github.com/eveisesi/skillz"
+	"github.com/eveisesi/skillz/internal/universe"
+	"github.com/pkg/errors"
+)
+
+var ErrShipNotFound = errors.New("ship does not exist")
+
+// FlightRequirements returns the skill levels the character is missing to fly the provided ship, along
+// with the skillpoints and training time required to train them with the character's current attributes
+func (s *Service) FlightRequirements(ctx context.Context, characterID uint64, shipID uint) (*skillz.FlightRequirements, error) {
+
+	ship, err := s.universe.Type(ctx, shipID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch ship type")
+	}
+
+	if ship == nil || ship.ID == 0 {
+		return nil, ErrShipNotFound
+	}
+
+	group, err := s.universe.Group(ctx, ship.GroupID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch ship group")
+	}
+
+	if group == nil || group.CategoryID != universe.CategoryShips {
+		return nil, ErrShipNotFound
+	}
+
+	requirements, err := s.shipRequirements(ctx, ship)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := s.RequirementsPlan(ctx, characterID, requirements)
+	if err != nil {
+		return nil, err
+	}
+
+	return &skillz.FlightRequirements{
+		ShipID:       ship.ID,
+		Ship:         ship,
+		Flyable:      len(plan.Entries) == 0,
+		Requirements: requirements,
+		Missing:      plan.Entries,
+		Skillpoints:  plan.Skillpoints,
+		Duration:     plan.Duration,
+	}, nil
+
+}
+
+// RequirementsPlan builds an unsaved skill plan of every skill level, including nested prerequisites, that
+// the character is missing to meet the provided requirements. The plan is evaluated so that each level is
+// timed on top of the levels that are trained before it
+func (s *Service) RequirementsPlan(ctx context.Context, characterID uint64, requirements []*skillz.SkillRequirement) (*skillz.SkillPlan, error) {
+
+	skills, err := s.Skillz(ctx, characterID)
+	if err != nil {
+		return nil, err
+	}
+
+	attributes, err := s.EffectiveAttributes(ctx, characterID)
+	if err != nil {
+		return nil, err
+	}
+
+	types, err := s.skillTypesByID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	graph := NewPrerequisiteGraph(types)
+	planned := trainedLevels(skills)
+
+	plan := &skillz.SkillPlan{Entries: make([]*skillz.SkillPlanEntry, 0)}
+	for _, requirement := range requirements {
+		requirement.Type = types[requirement.SkillID]
+
+		entries, err := graph.Resolve(requirement.SkillID, requirement.Level, planned)
+		if err != nil {
+			return nil, err
+		}
+
+		plan.Entries = append(plan.Entries, entries...)
+	}
+
+	for i, entry := range plan.Entries {
+		entry.Position = uint(i) + 1
+	}
+
+	EvaluateSkillPlan(plan, skills, types, attributes)
+
+	return plan, nil
+
+}
+
+// shipRequirements returns the skills the ship directly requires. The requirements are read from the
+// imported ship flight requirements, falling back to the ship's dogma when they have not been imported
+func (s *Service) shipRequirements(ctx context.Context, ship *skillz.Type) ([]*skillz.SkillRequirement, error) {
+
+	flightRequirements, err := s.universe.ShipFlightRequirements(ctx, ship.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(flightRequirements) == 0 {
+		return RequiredSkills(ship), nil
+	}
+
+	requirements := make([]*skillz.SkillRequirement, 0, len(flightRequirements))
+	for _, flightRequirement := range flightRequirements {
+		requirements = append(requirements, &skillz.SkillRequirement{
+			SkillID: flightRequirement.SkillID,
+			Level:   flightRequirement.MinimumSkillLevel,
+		})
+	}
+
+	return requirements, nil
+
+}
@@ -0,0 +1,422 @@
+package skill
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/eveisesi/skillz"
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrSkillPlanNotFound     = errors.New("skill plan does not exist")
+	ErrInvalidSkillPlan      = errors.New("skill plan is invalid")
+	ErrInvalidSkillPlanEntry = errors.New("skill plan entry is invalid")
+)
+
+const maxSkillPlanNameLength = 255
+
+// EvaluateSkillPlan walks the entries of the plan in order and calculates the skillpoints and
+// training time each entry requires on top of the character's current skills and every entry
+// before it. The totals are set on the plan
+func EvaluateSkillPlan(plan *skillz.SkillPlan, skills []*skillz.CharacterSkill, types map[uint]*skillz.Type, attributes *skillz.CharacterAttributes) {
+
+	skillpoints := make(map[uint]uint, len(skills))
+	for _, skill := range skills {
+		skillpoints[skill.SkillID] = skill.SkillpointsInSkill
+	}
+
+	plan.Skillpoints = 0
+	plan.Duration = 0
+
+	for _, entry := range plan.Entries {
+		entry.Skillpoints = 0
+		entry.Duration = 0
+
+		t := types[entry.SkillID]
+		if t != nil {
+			entry.Type = t
+		}
+
+		target := SkillpointsForLevel(SkillRank(t), entry.Level)
+		current := skillpoints[entry.SkillID]
+		if target <= current {
+			continue
+		}
+
+		entry.Skillpoints = target - current
+		entry.Duration = TrainingDuration(entry.Skillpoints, SPPerMinute(t, attributes))
+		skillpoints[entry.SkillID] = target
+
+		plan.Skillpoints += entry.Skillpoints
+		plan.Duration += entry.Duration
+	}
+
+}
+
+func (s *Service) SkillPlans(ctx context.Context, user *skillz.User) ([]*skillz.SkillPlan, error) {
+
+	plans, err := s.skills.SkillPlansByUserID(ctx, user.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.Wrap(err, "failed to fetch skill plans from data store")
+	}
+
+	if len(plans) == 0 {
+		return plans, nil
+	}
+
+	for _, plan := range plans {
+		plan.Entries, err = s.skills.SkillPlanEntries(ctx, plan.ID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.Wrap(err, "failed to fetch skill plan entries from data store")
+		}
+	}
+
+	err = s.evaluateSkillPlans(ctx, user.CharacterID, plans...)
+	if err != nil {
+		return nil, err
+	}
+
+	return plans, nil
+
+}
+
+func (s *Service) SkillPlan(ctx context.Context, user *skillz.User, planID uint) (*skillz.SkillPlan, error) {
+
+	plan, err := s.skillPlan(ctx, user, planID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.evaluateSkillPlans(ctx, user.CharacterID, plan)
+	if err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+
+}
+
+func (s *Service) CreateSkillPlan(ctx context.Context, user *skillz.User, name string, entries []*skillz.SkillPlanEntry) (*skillz.SkillPlan, error) {
+
+	name, err := validateSkillPlanName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	types, err := s.skillTypesByID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateSkillPlanEntries(entries, types)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err = s.resolveSkillPlanEntries(ctx, user.CharacterID, types, nil, entries)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &skillz.SkillPlan{
+		UserID:  user.ID,
+		Name:    name,
+		Entries: numberSkillPlanEntries(0, entries),
+	}
+
+	err = s.skills.CreateSkillPlan(ctx, plan)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create skill plan")
+	}
+
+	return s.SkillPlan(ctx, user, plan.ID)
+
+}
+
+func (s *Service) RenameSkillPlan(ctx context.Context, user *skillz.User, planID uint, name string) (*skillz.SkillPlan, error) {
+
+	name, err := validateSkillPlanName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := s.skillPlan(ctx, user, planID)
+	if err != nil {
+		return nil, err
+	}
+
+	plan.Name = name
+
+	err = s.skills.UpdateSkillPlan(ctx, plan)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to update skill plan")
+	}
+
+	err = s.evaluateSkillPlans(ctx, user.CharacterID, plan)
+	if err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+
+}
+
+// ReorderSkillPlan reorders the entries of a plan. order is the complete list of the
+// plan's current entry positions in the order they should now be trained in
+func (s *Service) ReorderSkillPlan(ctx context.Context, user *skillz.User, planID uint, order []uint) (*skillz.SkillPlan, error) {
+
+	plan, err := s.skillPlan(ctx, user, planID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(order) != len(plan.Entries) {
+		return nil, errors.Wrapf(ErrInvalidSkillPlan, "expected %d positions, got %d", len(plan.Entries), len(order))
+	}
+
+	entriesByPosition := make(map[uint]*skillz.SkillPlanEntry, len(plan.Entries))
+	for _, entry := range plan.Entries {
+		entriesByPosition[entry.Position] = entry
+	}
+
+	entries := make([]*skillz.SkillPlanEntry, 0, len(order))
+	for _, position := range order {
+		entry, ok := entriesByPosition[position]
+		if !ok {
+			return nil, errors.Wrapf(ErrInvalidSkillPlan, "position %d does not exist or is listed more than once", position)
+		}
+
+		entries = append(entries, entry)
+		delete(entriesByPosition, position)
+	}
+
+	return s.UpdateSkillPlanEntries(ctx, user, planID, entries)
+
+}
+
+// UpdateSkillPlanEntries replaces the entries of the plan with the provided entries. Missing
+// prerequisites are inserted ahead of the entries that require them and entries are renumbered
+// in the order they are provided in. Prerequisites that are already in the plan are not inserted
+// again, so removing an entry that a later entry requires, or moving an entry ahead of an entry
+// it requires, is rejected with ErrInvalidSkillPlanEntry
+func (s *Service) UpdateSkillPlanEntries(ctx context.Context, user *skillz.User, planID uint, entries []*skillz.SkillPlanEntry) (*skillz.SkillPlan, error) {
+
+	plan, err := s.skillPlan(ctx, user, planID)
+	if err != nil {
+		return nil, err
+	}
+
+	types, err := s.skillTypesByID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateSkillPlanEntries(entries, types)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err = s.resolveSkillPlanEntries(ctx, user.CharacterID, types, plan.Entries, entries)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.replaceSkillPlanEntries(ctx, plan, entries)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.skills.UpdateSkillPlan(ctx, plan)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to update skill plan")
+	}
+
+	err = s.evaluateSkillPlans(ctx, user.CharacterID, plan)
+	if err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+
+}
+
+func (s *Service) DeleteSkillPlan(ctx context.Context, user *skillz.User, planID uint) error {
+
+	plan, err := s.skillPlan(ctx, user, planID)
+	if err != nil {
+		return err
+	}
+
+	err = s.skills.DeleteSkillPlan(ctx, plan.ID)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete skill plan")
+	}
+
+	return nil
+
+}
+
+// skillPlan fetches a plan and its entries, ensuring that the plan belongs to the provided user
+func (s *Service) skillPlan(ctx context.Context, user *skillz.User, planID uint) (*skillz.SkillPlan, error) {
+
+	plan, err := s.skills.SkillPlan(ctx, planID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.Wrap(err, "failed to fetch skill plan from data store")
+	}
+
+	if errors.Is(err, sql.ErrNoRows) || plan.UserID != user.ID {
+		return nil, ErrSkillPlanNotFound
+	}
+
+	plan.Entries, err = s.skills.SkillPlanEntries(ctx, plan.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.Wrap(err, "failed to fetch skill plan entries from data store")
+	}
+
+	return plan, nil
+
+}
+
+func (s *Service) replaceSkillPlanEntries(ctx context.Context, plan *skillz.SkillPlan, entries []*skillz.SkillPlanEntry) error {
+
+	err := s.skills.ReplaceSkillPlanEntries(ctx, plan.ID, numberSkillPlanEntries(plan.ID, entries))
+	if err != nil {
+		return errors.Wrap(err, "failed to replace skill plan entries")
+	}
+
+	plan.Entries = entries
+
+	return nil
+
+}
+
+// numberSkillPlanEntries assigns the entries to the plan and numbers them in the order they are provided in
+func numberSkillPlanEntries(planID uint, entries []*skillz.SkillPlanEntry) []*skillz.SkillPlanEntry {
+
+	for i, entry := range entries {
+		entry.PlanID = planID
+		entry.Position = uint(i) + 1
+	}
+
+	return entries
+
+}
+
+// resolveSkillPlanEntries inserts the missing prerequisites of every entry ahead of it. previous are the
+// entries of the plan before the edit, the prerequisites among them are not inserted again
+func (s *Service) resolveSkillPlanEntries(ctx context.Context, characterID uint64, types map[uint]*skillz.Type, previous, entries []*skillz.SkillPlanEntry) ([]*skillz.SkillPlanEntry, error) {
+
+	skills, err := s.Skillz(ctx, characterID)
+	if err != nil {
+		return nil, err
+	}
+
+	g := NewPrerequisiteGraph(types)
+
+	err = validateReinsertedEntries(g, previous, entries, skills)
+	if err != nil {
+		return nil, err
+	}
+
+	return g.ResolveSkillPlanEntries(entries, skills)
+
+}
+
+// validateReinsertedEntries rejects the edit of a plan when resolving the entries would insert a prerequisite
+// that was an entry of the plan before the edit, i.e. the edit removed an entry that a later entry requires
+// or moved an entry ahead of an entry it requires. Reinserting it would silently undo the edit
+func validateReinsertedEntries(g *PrerequisiteGraph, previous, entries []*skillz.SkillPlanEntry, skills []*skillz.CharacterSkill) error {
+
+	if len(previous) == 0 {
+		return nil
+	}
+
+	type key struct{ skillID, level uint }
+
+	planned := make(map[key]bool, len(previous))
+	for _, entry := range previous {
+		planned[key{entry.SkillID, entry.Level}] = true
+	}
+
+	levels := trainedLevels(skills)
+	for _, entry := range entries {
+		resolved, err := g.Resolve(entry.SkillID, entry.Level, levels)
+		if err != nil {
+			return err
+		}
+
+		for _, prerequisite := range resolved {
+			if prerequisite.SkillID == entry.SkillID && prerequisite.Level == entry.Level {
+				continue
+			}
+
+			if planned[key{prerequisite.SkillID, prerequisite.Level}] {
+				return errors.Wrapf(
+					ErrInvalidSkillPlanEntry, "%s %d is required by %s %d and must stay ahead of it",
+					g.skillName(prerequisite.SkillID), prerequisite.Level, g.skillName(entry.SkillID), entry.Level,
+				)
+			}
+		}
+	}
+
+	return nil
+
+}
+
+func (s *Service) evaluateSkillPlans(ctx context.Context, characterID uint64, plans ...*skillz.SkillPlan) error {
+
+	skills, err := s.Skillz(ctx, characterID)
+	if err != nil {
+		return err
+	}
+
+	attributes, err := s.EffectiveAttributes(ctx, characterID)
+	if err != nil {
+		return err
+	}
+
+	types, err := s.skillTypesByID(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, plan := range plans {
+		EvaluateSkillPlan(plan, skills, types, attributes)
+	}
+
+	return nil
+
+}
+
+func validateSkillPlanName(name string) (string, error) {
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", errors.Wrap(ErrInvalidSkillPlan, "name is required")
+	}
+
+	if len(name) > maxSkillPlanNameLength {
+		return "", errors.Wrapf(ErrInvalidSkillPlan, "name must be %d characters or less", maxSkillPlanNameLength)
+	}
+
+	return name, nil
+
+}
+
+func validateSkillPlanEntries(entries []*skillz.SkillPlanEntry, types map[uint]*skillz.Type) error {
+
+	for _, entry := range entries {
+		if _, ok := types[entry.SkillID]; !ok {
+			return errors.Wrapf(ErrInvalidSkillPlanEntry, "type %d is not a skill", entry.SkillID)
+		}
+
+		if entry.Level == 0 || entry.Level > maxSkillLevel {
+			return errors.Wrapf(ErrInvalidSkillPlanEntry, "level %d is not between 1 and %d", entry.Level, maxSkillLevel)
+		}
+	}
+
+	return nil
+
+}
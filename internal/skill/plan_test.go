@@ -0,0 +1,59 @@
+package skill
+
+import (
+	"testing"
+
+	"github.com/eveisesi/skillz"
+	"github.com/pkg/errors"
+)
+
+func TestValidateReinsertedEntries(t *testing.T) {
+
+	// Navigation has no prerequisites, Evasive Maneuvering requires Navigation 1 and Warp Drive Operation requires Navigation 2
+	types := map[uint]*skillz.Type{
+		3449: {ID: 3449, Name: "Navigation"},
+		3453: {ID: 3453, Name: "Evasive Maneuvering", Attributes: []*skillz.TypeDogmaAttribute{
+			{AttributeID: 182, Value: 3449}, {AttributeID: 277, Value: 1},
+		}},
+		3455: {ID: 3455, Name: "Warp Drive Operation", Attributes: []*skillz.TypeDogmaAttribute{
+			{AttributeID: 182, Value: 3449}, {AttributeID: 277, Value: 2},
+		}},
+	}
+
+	entry := func(skillID, level uint) *skillz.SkillPlanEntry {
+		return &skillz.SkillPlanEntry{SkillID: skillID, Level: level}
+	}
+
+	plan := []*skillz.SkillPlanEntry{entry(3449, 1), entry(3449, 2), entry(3453, 1), entry(3455, 1)}
+
+	tests := []struct {
+		name     string
+		previous []*skillz.SkillPlanEntry
+		entries  []*skillz.SkillPlanEntry
+		skills   []*skillz.CharacterSkill
+		valid    bool
+	}{
+		{name: "new plan", entries: []*skillz.SkillPlanEntry{entry(3455, 1)}, valid: true},
+		{name: "unchanged plan", previous: plan, entries: plan, valid: true},
+		{name: "reorder that keeps prerequisites ahead", previous: plan, entries: []*skillz.SkillPlanEntry{plan[0], plan[2], plan[1], plan[3]}, valid: true},
+		{name: "delete an entry nothing requires", previous: plan, entries: plan[:3], valid: true},
+		{name: "add an entry with prerequisites that are not planned", previous: plan[:1], entries: []*skillz.SkillPlanEntry{plan[0], entry(3455, 1)}, valid: true},
+		{name: "delete a prerequisite that is trained", previous: plan, entries: plan[1:], skills: []*skillz.CharacterSkill{{SkillID: 3449, TrainedSkillLevel: 1}}, valid: true},
+		{name: "delete a prerequisite", previous: plan, entries: []*skillz.SkillPlanEntry{plan[1], plan[2], plan[3]}},
+		{name: "delete a lower level", previous: plan, entries: []*skillz.SkillPlanEntry{plan[0], plan[2], plan[3]}},
+		{name: "move an entry ahead of its prerequisite", previous: plan, entries: []*skillz.SkillPlanEntry{plan[2], plan[0], plan[1], plan[3]}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateReinsertedEntries(NewPrerequisiteGraph(types), test.previous, test.entries, test.skills)
+			if test.valid && err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+			if !test.valid && !errors.Is(err, ErrInvalidSkillPlanEntry) {
+				t.Errorf("got error %v, want %s", err, ErrInvalidSkillPlanEntry)
+			}
+		})
+	}
+
+}
@@ -0,0 +1,322 @@
+package skill
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/eveisesi/skillz"
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrCyclicPrerequisites    = errors.New("skill prerequisites are cyclic")
+	ErrImpossiblePrerequisite = errors.New("skill prerequisite is impossible to train")
+)
+
+// RequiredSkills reads the required skill dogma attributes of a type and returns the skills
+// and levels required to use it. The order of the requirements matches the order of the dogma
+// attributes, i.e. primary, secondary, tertiary, etc
+func RequiredSkills(t *skillz.Type) []*skillz.SkillRequirement {
+
+	if t == nil {
+		return nil
+	}
+
+	requirements := make([]*skillz.SkillRequirement, 0, len(skillNameDogmaSlice))
+	for _, nameAttributeID := range skillNameDogmaSlice {
+		skillAttribute := t.GetAttribute(nameAttributeID)
+		if skillAttribute == nil {
+			continue
+		}
+
+		levelAttribute := t.GetAttribute(skillNameToLevelDogmaMap[nameAttributeID])
+		if levelAttribute == nil {
+			continue
+		}
+
+		requirements = append(requirements, &skillz.SkillRequirement{
+			SkillID: uint(skillAttribute.Value),
+			Level:   uint(levelAttribute.Value),
+		})
+	}
+
+	return requirements
+
+}
+
+// PrerequisiteGraph is a directed graph of skills to the skills they require
+type PrerequisiteGraph struct {
+	types        map[uint]*skillz.Type
+	requirements map[uint][]*skillz.SkillRequirement
+}
+
+// NewPrerequisiteGraph builds a graph from the provided skill types. The types must be hydrated
+// with their dogma attributes, else the graph will not contain any edges
+func NewPrerequisiteGraph(types map[uint]*skillz.Type) *PrerequisiteGraph {
+
+	g := &PrerequisiteGraph{
+		types:        types,
+		requirements: make(map[uint][]*skillz.SkillRequirement, len(types)),
+	}
+
+	for id, t := range types {
+		requirements := RequiredSkills(t)
+		for _, requirement := range requirements {
+			requirement.Type = types[requirement.SkillID]
+		}
+		g.requirements[id] = requirements
+	}
+
+	return g
+
+}
+
+// Requirements returns the skills directly required to train the provided skill
+func (g *PrerequisiteGraph) Requirements(skillID uint) []*skillz.SkillRequirement {
+	return g.requirements[skillID]
+}
+
+// Validate walks every skill in the graph and reports the first skill whose prerequisites
+// are cyclic or reference a skill or level that cannot be trained
+func (g *PrerequisiteGraph) Validate() error {
+
+	ids := make([]uint, 0, len(g.types))
+	for id := range g.types {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	validated := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		err := g.validate(id, validated, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+
+}
+
+func (g *PrerequisiteGraph) validate(skillID uint, validated map[uint]bool, path []uint) error {
+
+	if validated[skillID] {
+		return nil
+	}
+
+	path = append(path, skillID)
+	for _, id := range path[:len(path)-1] {
+		if id == skillID {
+			return errors.Wrap(ErrCyclicPrerequisites, formatPrerequisitePath(path))
+		}
+	}
+
+	for _, requirement := range g.requirements[skillID] {
+		if _, ok := g.types[requirement.SkillID]; !ok {
+			return errors.Wrapf(ErrImpossiblePrerequisite, "%s requires unknown skill %d", formatPrerequisitePath(path), requirement.SkillID)
+		}
+
+		if requirement.Level == 0 || requirement.Level > maxSkillLevel {
+			return errors.Wrapf(ErrImpossiblePrerequisite, "%s requires skill %d at level %d", formatPrerequisitePath(path), requirement.SkillID, requirement.Level)
+		}
+
+		err := g.validate(requirement.SkillID, validated, path)
+		if err != nil {
+			return err
+		}
+	}
+
+	validated[skillID] = true
+
+	return nil
+
+}
+
+// Resolve returns every skill level that needs to be trained, in dependency order, for the
+// provided skill to reach the provided level. planned is the level each skill is already trained
+// or planned to and is updated with the returned levels, so that calling Resolve repeatedly with
+// the same map does not return a level more than once
+func (g *PrerequisiteGraph) Resolve(skillID, level uint, planned map[uint]uint) ([]*skillz.SkillPlanEntry, error) {
+
+	entries := make([]*skillz.SkillPlanEntry, 0)
+	err := g.resolve(skillID, level, planned, nil, &entries)
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+
+}
+
+func (g *PrerequisiteGraph) resolve(skillID, level uint, planned map[uint]uint, path []uint, entries *[]*skillz.SkillPlanEntry) error {
+
+	path = append(path, skillID)
+
+	t, ok := g.types[skillID]
+	if !ok {
+		return errors.Wrapf(ErrImpossiblePrerequisite, "%s: %d is not a known skill", formatPrerequisitePath(path), skillID)
+	}
+
+	if level == 0 || level > maxSkillLevel {
+		return errors.Wrapf(ErrImpossiblePrerequisite, "%s: level %d is not between 1 and %d", formatPrerequisitePath(path), level, maxSkillLevel)
+	}
+
+	if planned[skillID] >= level {
+		return nil
+	}
+
+	for _, id := range path[:len(path)-1] {
+		if id == skillID {
+			return errors.Wrap(ErrCyclicPrerequisites, formatPrerequisitePath(path))
+		}
+	}
+
+	for _, requirement := range g.requirements[skillID] {
+		err := g.resolve(requirement.SkillID, requirement.Level, planned, path, entries)
+		if err != nil {
+			return err
+		}
+	}
+
+	for l := planned[skillID] + 1; l <= level; l++ {
+		*entries = append(*entries, &skillz.SkillPlanEntry{
+			SkillID: skillID,
+			Level:   l,
+			Type:    t,
+		})
+	}
+
+	planned[skillID] = level
+
+	return nil
+
+}
+
+// ResolveSkillPlanEntries expands the provided entries so that every entry is preceded by its
+// missing prerequisites and lower levels. Entries that are already trained or planned earlier are dropped
+func (g *PrerequisiteGraph) ResolveSkillPlanEntries(entries []*skillz.SkillPlanEntry, skills []*skillz.CharacterSkill) ([]*skillz.SkillPlanEntry, error) {
+
+	planned := trainedLevels(skills)
+
+	resolved := make([]*skillz.SkillPlanEntry, 0, len(entries))
+	for _, entry := range entries {
+		levels, err := g.Resolve(entry.SkillID, entry.Level, planned)
+		if err != nil {
+			return nil, err
+		}
+
+		resolved = append(resolved, levels...)
+	}
+
+	return resolved, nil
+
+}
+
+// MissingPrerequisites walks the queue in order and sets MissingPrerequisites on every position
+// that requires a skill level that is neither trained nor queued in an earlier position. The
+// flagged positions are returned
+func (g *PrerequisiteGraph) MissingPrerequisites(queue []*skillz.CharacterSkillQueue, skills []*skillz.CharacterSkill) []*skillz.CharacterSkillQueue {
+
+	planned := trainedLevels(skills)
+
+	positions := append(make([]*skillz.CharacterSkillQueue, 0, len(queue)), queue...)
+	sort.SliceStable(positions, func(i, j int) bool {
+		return positions[i].QueuePosition < positions[j].QueuePosition
+	})
+
+	flagged := make([]*skillz.CharacterSkillQueue, 0)
+	for _, position := range positions {
+		position.MissingPrerequisites = nil
+
+		requirements := g.requirements[position.SkillID]
+		if position.FinishedLevel > 1 {
+			requirements = append([]*skillz.SkillRequirement{{
+				SkillID: position.SkillID,
+				Level:   position.FinishedLevel - 1,
+				Type:    g.types[position.SkillID],
+			}}, requirements...)
+		}
+
+		for _, requirement := range requirements {
+			if planned[requirement.SkillID] < requirement.Level {
+				position.MissingPrerequisites = append(position.MissingPrerequisites, requirement)
+			}
+		}
+
+		if len(position.MissingPrerequisites) > 0 {
+			flagged = append(flagged, position)
+		}
+
+		if planned[position.SkillID] < position.FinishedLevel {
+			planned[position.SkillID] = position.FinishedLevel
+		}
+	}
+
+	return flagged
+
+}
+
+func (s *Service) prerequisiteGraph(ctx context.Context) (*PrerequisiteGraph, error) {
+
+	types, err := s.skillTypesByID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPrerequisiteGraph(types), nil
+
+}
+
+// ValidateSkillQueue returns the positions of the character's skill queue whose prerequisites are not met
+func (s *Service) ValidateSkillQueue(ctx context.Context, characterID uint64) ([]*skillz.CharacterSkillQueue, error) {
+
+	summary, err := s.SkillQueue(ctx, characterID)
+	if err != nil {
+		return nil, err
+	}
+
+	flagged := make([]*skillz.CharacterSkillQueue, 0)
+	for _, position := range summary.Queue {
+		if len(position.MissingPrerequisites) > 0 {
+			flagged = append(flagged, position)
+		}
+	}
+
+	return flagged, nil
+
+}
+
+func trainedLevels(skills []*skillz.CharacterSkill) map[uint]uint {
+
+	levels := make(map[uint]uint, len(skills))
+	for _, skill := range skills {
+		levels[skill.SkillID] = skill.TrainedSkillLevel
+	}
+
+	return levels
+
+}
+
+func formatPrerequisitePath(path []uint) string {
+
+	parts := make([]string, 0, len(path))
+	for _, id := range path {
+		parts = append(parts, fmt.Sprintf("%d", id))
+	}
+
+	return strings.Join(parts, " -> ")
+
+}
+
+// skillName returns the name of the skill, falling back to its id for skills that are not in the graph
+func (g *PrerequisiteGraph) skillName(skillID uint) string {
+
+	if t, ok := g.types[skillID]; ok && t.Name != "" {
+		return t.Name
+	}
+
+	return fmt.Sprintf("skill %d", skillID)
+
+}
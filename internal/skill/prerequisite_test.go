@@ -0,0 +1,212 @@
+package skill
+
+import (
+	"testing"
+
+	"github.com/eveisesi/skillz"
+	"github.com/pkg/errors"
+)
+
+// prerequisiteSkill returns a skill type that requires the provided skills, with the
+// requirements alternating between skill ids and levels, e.g. 3449, 2 requires Navigation II
+func prerequisiteSkill(id uint, requirements ...uint) *skillz.Type {
+
+	t := &skillz.Type{ID: id}
+	for i := 0; i+1 < len(requirements); i += 2 {
+		nameAttributeID := skillNameDogmaSlice[i/2]
+		t.Attributes = append(t.Attributes,
+			&skillz.TypeDogmaAttribute{TypeID: id, AttributeID: nameAttributeID, Value: float64(requirements[i])},
+			&skillz.TypeDogmaAttribute{TypeID: id, AttributeID: skillNameToLevelDogmaMap[nameAttributeID], Value: float64(requirements[i+1])},
+		)
+	}
+
+	return t
+
+}
+
+func prerequisiteGraph(types ...*skillz.Type) *PrerequisiteGraph {
+
+	m := make(map[uint]*skillz.Type, len(types))
+	for _, t := range types {
+		m[t.ID] = t
+	}
+
+	return NewPrerequisiteGraph(m)
+
+}
+
+func TestPrerequisiteGraphValidate(t *testing.T) {
+
+	tests := []struct {
+		name  string
+		graph *PrerequisiteGraph
+		want  error
+	}{
+		{
+			name:  "multi level chain",
+			graph: prerequisiteGraph(prerequisiteSkill(1), prerequisiteSkill(2, 1, 2), prerequisiteSkill(3, 2, 3, 1, 1)),
+		},
+		{
+			name:  "two node cycle",
+			graph: prerequisiteGraph(prerequisiteSkill(1, 2, 1), prerequisiteSkill(2, 1, 1)),
+			want:  ErrCyclicPrerequisites,
+		},
+		{
+			name:  "self reference",
+			graph: prerequisiteGraph(prerequisiteSkill(1, 1, 1)),
+			want:  ErrCyclicPrerequisites,
+		},
+		{
+			name:  "unknown skill",
+			graph: prerequisiteGraph(prerequisiteSkill(1, 9999, 1)),
+			want:  ErrImpossiblePrerequisite,
+		},
+		{
+			name:  "impossible level",
+			graph: prerequisiteGraph(prerequisiteSkill(1), prerequisiteSkill(2, 1, 6)),
+			want:  ErrImpossiblePrerequisite,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.graph.Validate()
+			if test.want == nil && err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if test.want != nil && !errors.Is(err, test.want) {
+				t.Fatalf("got error %v, want %s", err, test.want)
+			}
+		})
+	}
+
+}
+
+func TestPrerequisiteGraphResolve(t *testing.T) {
+
+	type level struct {
+		skillID, level uint
+	}
+
+	// 3 requires 2 at level III, which requires 1 at level II
+	chain := prerequisiteGraph(prerequisiteSkill(1), prerequisiteSkill(2, 1, 2), prerequisiteSkill(3, 2, 3))
+
+	tests := []struct {
+		name    string
+		graph   *PrerequisiteGraph
+		skillID uint
+		level   uint
+		planned map[uint]uint
+		want    []level
+		err     error
+	}{
+		{
+			name:    "multi level chain in dependency order",
+			graph:   chain,
+			skillID: 3,
+			level:   2,
+			want:    []level{{1, 1}, {1, 2}, {2, 1}, {2, 2}, {2, 3}, {3, 1}, {3, 2}},
+		},
+		{
+			name:    "multi level chain with trained prerequisites",
+			graph:   chain,
+			skillID: 3,
+			level:   1,
+			planned: map[uint]uint{1: 2, 2: 1},
+			want:    []level{{2, 2}, {2, 3}, {3, 1}},
+		},
+		{
+			name:    "already planned",
+			graph:   chain,
+			skillID: 3,
+			level:   1,
+			planned: map[uint]uint{1: 2, 2: 3, 3: 1},
+			want:    []level{},
+		},
+		{
+			name:    "two node cycle",
+			graph:   prerequisiteGraph(prerequisiteSkill(1, 2, 1), prerequisiteSkill(2, 1, 1)),
+			skillID: 1,
+			level:   1,
+			err:     ErrCyclicPrerequisites,
+		},
+		{
+			name:    "self reference",
+			graph:   prerequisiteGraph(prerequisiteSkill(1, 1, 1)),
+			skillID: 1,
+			level:   2,
+			err:     ErrCyclicPrerequisites,
+		},
+		{
+			name:    "unknown skill",
+			graph:   chain,
+			skillID: 9999,
+			level:   1,
+			err:     ErrImpossiblePrerequisite,
+		},
+		{
+			name:    "unknown prerequisite",
+			graph:   prerequisiteGraph(prerequisiteSkill(1, 9999, 1)),
+			skillID: 1,
+			level:   1,
+			err:     ErrImpossiblePrerequisite,
+		},
+		{
+			name:    "impossible level",
+			graph:   chain,
+			skillID: 1,
+			level:   6,
+			err:     ErrImpossiblePrerequisite,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			planned := test.planned
+			if planned == nil {
+				planned = make(map[uint]uint)
+			}
+
+			entries, err := test.graph.Resolve(test.skillID, test.level, planned)
+			if test.err != nil {
+				if !errors.Is(err, test.err) {
+					t.Fatalf("got error %v, want %s", err, test.err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			got := make([]level, 0, len(entries))
+			for _, entry := range entries {
+				got = append(got, level{entry.SkillID, entry.Level})
+			}
+
+			if len(got) != len(test.want) {
+				t.Fatalf("got levels %v, want %v", got, test.want)
+			}
+
+			for i := range got {
+				if got[i] != test.want[i] {
+					t.Fatalf("got levels %v, want %v", got, test.want)
+				}
+			}
+
+			// Resolving the same level again must not return any level twice
+			again, err := test.graph.Resolve(test.skillID, test.level, planned)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if len(again) != 0 {
+				t.Errorf("got %d levels when resolving again, want none", len(again))
+			}
+
+		})
+	}
+
+}
@@ -0,0 +1,225 @@
+package skill
+
+import (
+	"context"
+	"time"
+
+	"github.com/eveisesi/skillz"
+	"github.com/volatiletech/null"
+)
+
+const (
+	remapBaseAttribute uint = 17
+	remapMaxAttribute  uint = 27
+	remapPoints        uint = 14
+
+	remapCooldown = time.Hour * 24 * 365
+)
+
+// remapAttributeIDs is the order in which attributes are assigned points when searching the remap space
+var remapAttributeIDs = []uint{
+	skillz.PerceptionAttributeID,
+	skillz.MemoryAttributeID,
+	skillz.WillpowerAttributeID,
+	skillz.IntelligenceAttributeID,
+	skillz.CharismaAttributeID,
+}
+
+// TrainingLoad is the number of skillpoints to be trained keyed by the
+// primary and secondary attribute of the skills they belong to
+type TrainingLoad map[[2]uint]uint
+
+// Add adds the skillpoints of a skill to the load. Skills that are missing
+// their attribute dogma are ignored since their training speed is unknown
+func (l TrainingLoad) Add(t *skillz.Type, skillpoints uint) {
+
+	if t == nil || skillpoints == 0 {
+		return
+	}
+
+	primary := t.GetAttribute(skillz.SkillPrimaryAttributeAttributeID)
+	secondary := t.GetAttribute(skillz.SkillSecondaryAttributeAttributeID)
+	if primary == nil || secondary == nil {
+		return
+	}
+
+	l[[2]uint{uint(primary.Value), uint(secondary.Value)}] += skillpoints
+
+}
+
+// Duration returns the time required to train the load with the provided attributes
+func (l TrainingLoad) Duration(attributes *skillz.CharacterAttributes) time.Duration {
+
+	var minutes float64
+	for pair, skillpoints := range l {
+		spPerMinute := float64(attributes.AttributeValue(pair[0])) + float64(attributes.AttributeValue(pair[1]))/2
+		if spPerMinute <= 0 {
+			continue
+		}
+
+		minutes += float64(skillpoints) / spPerMinute
+	}
+
+	return time.Duration(minutes * float64(time.Minute)).Round(time.Second)
+
+}
+
+// OptimalRemap searches every legal remap, 17 to 27 points per attribute with 14 points
+// distributed on top of a base of 17, and returns the remap that trains the load the fastest.
+// bonuses, i.e. implants, are added on top of every remap when timing the load
+func OptimalRemap(load TrainingLoad, bonuses *skillz.CharacterAttributes) (*skillz.CharacterAttributes, time.Duration) {
+
+	var best *skillz.CharacterAttributes
+	var bestDuration time.Duration
+
+	values := make([]uint, len(remapAttributeIDs))
+
+	var search func(i int, remaining uint)
+	search = func(i int, remaining uint) {
+		if i == len(remapAttributeIDs)-1 {
+			if remapBaseAttribute+remaining > remapMaxAttribute {
+				return
+			}
+
+			values[i] = remapBaseAttribute + remaining
+
+			candidate := remapFromValues(values)
+			duration := load.Duration(EffectiveAttributes(candidate, bonuses))
+			if best == nil || duration < bestDuration {
+				best, bestDuration = candidate, duration
+			}
+			return
+		}
+
+		for points := uint(0); points <= remaining && remapBaseAttribute+points <= remapMaxAttribute; points++ {
+			values[i] = remapBaseAttribute + points
+			search(i+1, remaining-points)
+		}
+	}
+
+	search(0, remapPoints)
+
+	return best, bestDuration
+
+}
+
+// RecommendRemap compares the character's current attributes against the optimal remap for the
+// load and reports whether the character is able to remap at this time. The recommended remap
+// does not include the bonuses, but the durations do
+func RecommendRemap(load TrainingLoad, attributes, bonuses *skillz.CharacterAttributes) *skillz.RemapRecommendation {
+
+	optimal, optimalDuration := OptimalRemap(load, bonuses)
+
+	recommendation := &skillz.RemapRecommendation{
+		Attributes:      optimal,
+		OptimalDuration: optimalDuration,
+	}
+
+	if attributes == nil {
+		return recommendation
+	}
+
+	recommendation.CurrentDuration = load.Duration(EffectiveAttributes(attributes, bonuses))
+	if recommendation.CurrentDuration > optimalDuration {
+		recommendation.TimeSaved = recommendation.CurrentDuration - optimalDuration
+	}
+
+	recommendation.RemapAvailable, recommendation.AvailableAt = remapAvailability(attributes, time.Now())
+	if attributes.BonusRemaps.Valid {
+		recommendation.BonusRemaps = attributes.BonusRemaps.Uint
+	}
+
+	return recommendation
+
+}
+
+func remapAvailability(attributes *skillz.CharacterAttributes, now time.Time) (bool, null.Time) {
+
+	if attributes.BonusRemaps.Valid && attributes.BonusRemaps.Uint > 0 {
+		return true, null.Time{}
+	}
+
+	cooldown := attributes.AccruedRemapCooldownDate
+	if !cooldown.Valid && attributes.LastRemapDate.Valid {
+		cooldown = null.TimeFrom(attributes.LastRemapDate.Time.Add(remapCooldown))
+	}
+
+	if !cooldown.Valid || !cooldown.Time.After(now) {
+		return true, null.Time{}
+	}
+
+	return false, cooldown
+
+}
+
+func remapFromValues(values []uint) *skillz.CharacterAttributes {
+
+	remap := new(skillz.CharacterAttributes)
+	for i, attributeID := range remapAttributeIDs {
+		switch attributeID {
+		case skillz.CharismaAttributeID:
+			remap.Charisma = values[i]
+		case skillz.IntelligenceAttributeID:
+			remap.Intelligence = values[i]
+		case skillz.MemoryAttributeID:
+			remap.Memory = values[i]
+		case skillz.PerceptionAttributeID:
+			remap.Perception = values[i]
+		case skillz.WillpowerAttributeID:
+			remap.Willpower = values[i]
+		}
+	}
+
+	return remap
+
+}
+
+// SkillQueueRemap recommends the remap that trains the remainder of the character's skill queue the fastest
+func (s *Service) SkillQueueRemap(ctx context.Context, characterID uint64) (*skillz.RemapRecommendation, error) {
+
+	summary, err := s.SkillQueue(ctx, characterID)
+	if err != nil {
+		return nil, err
+	}
+
+	attributes, bonuses, err := s.attributesAndBonuses(ctx, characterID)
+	if err != nil {
+		return nil, err
+	}
+
+	types, err := s.skillTypesByID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	load := make(TrainingLoad)
+	for _, position := range summary.Queue {
+		t := types[position.SkillID]
+		load.Add(t, QueuePositionSkillpoints(position, t))
+	}
+
+	return RecommendRemap(load, attributes, bonuses), nil
+
+}
+
+// SkillPlanRemap recommends the remap that trains the provided skill plan the fastest
+func (s *Service) SkillPlanRemap(ctx context.Context, user *skillz.User, planID uint) (*skillz.RemapRecommendation, error) {
+
+	plan, err := s.SkillPlan(ctx, user, planID)
+	if err != nil {
+		return nil, err
+	}
+
+	attributes, bonuses, err := s.attributesAndBonuses(ctx, user.CharacterID)
+	if err != nil {
+		return nil, err
+	}
+
+	load := make(TrainingLoad)
+	for _, entry := range plan.Entries {
+		load.Add(entry.Type, entry.Skillpoints)
+	}
+
+	return RecommendRemap(load, attributes, bonuses), nil
+
+}
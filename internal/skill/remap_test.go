@@ -0,0 +1,123 @@
+package skill
+
+import (
+	"testing"
+
+	"github.com/eveisesi/skillz"
+)
+
+func TestOptimalRemap(t *testing.T) {
+
+	implants := &skillz.CharacterAttributes{Charisma: 3, Intelligence: 5, Memory: 5, Perception: 5, Willpower: 5}
+
+	tests := []struct {
+		name    string
+		load    TrainingLoad
+		bonuses *skillz.CharacterAttributes
+		// want is the expected remap, or nil when any legal remap is acceptable
+		want *skillz.CharacterAttributes
+	}{
+		{
+			name: "intelligence and memory",
+			load: TrainingLoad{{skillz.IntelligenceAttributeID, skillz.MemoryAttributeID}: 256000},
+			want: &skillz.CharacterAttributes{Charisma: 17, Intelligence: 27, Memory: 21, Perception: 17, Willpower: 17},
+		},
+		{
+			name: "perception and willpower",
+			load: TrainingLoad{{skillz.PerceptionAttributeID, skillz.WillpowerAttributeID}: 256000},
+			want: &skillz.CharacterAttributes{Charisma: 17, Intelligence: 17, Memory: 17, Perception: 27, Willpower: 21},
+		},
+		{
+			name:    "perception and willpower with implants",
+			load:    TrainingLoad{{skillz.PerceptionAttributeID, skillz.WillpowerAttributeID}: 256000},
+			bonuses: implants,
+			want:    &skillz.CharacterAttributes{Charisma: 17, Intelligence: 17, Memory: 17, Perception: 27, Willpower: 21},
+		},
+		{
+			name: "mixed load",
+			load: TrainingLoad{
+				{skillz.PerceptionAttributeID, skillz.WillpowerAttributeID}:    512000,
+				{skillz.IntelligenceAttributeID, skillz.MemoryAttributeID}:     256000,
+				{skillz.CharismaAttributeID, skillz.WillpowerAttributeID}:      45255,
+				{skillz.MemoryAttributeID, skillz.IntelligenceAttributeID}:     8000,
+				{skillz.WillpowerAttributeID, skillz.PerceptionAttributeID}:    1415,
+				{skillz.IntelligenceAttributeID, skillz.PerceptionAttributeID}: 250,
+			},
+			bonuses: implants,
+		},
+		{
+			name: "empty load",
+			load: TrainingLoad{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			remap, duration := OptimalRemap(test.load, test.bonuses)
+			if remap == nil {
+				t.Fatal("expected a remap, got nil")
+			}
+
+			var total uint
+			for _, attributeID := range remapAttributeIDs {
+				value := remap.AttributeValue(attributeID)
+				if value < remapBaseAttribute || value > remapMaxAttribute {
+					t.Errorf("got %d points in attribute %d, want between %d and %d", value, attributeID, remapBaseAttribute, remapMaxAttribute)
+				}
+
+				total += value - remapBaseAttribute
+			}
+
+			if total != remapPoints {
+				t.Errorf("got %d points distributed on top of the base, want %d", total, remapPoints)
+			}
+
+			if test.want != nil && *remap != *test.want {
+				t.Errorf("got remap %+v, want %+v", *remap, *test.want)
+			}
+
+			if got := test.load.Duration(EffectiveAttributes(remap, test.bonuses)); got != duration {
+				t.Errorf("got duration %s, want the duration of the remap %s", duration, got)
+			}
+
+		})
+	}
+
+}
+
+func TestRecommendRemapTimeSaved(t *testing.T) {
+
+	load := TrainingLoad{{skillz.PerceptionAttributeID, skillz.WillpowerAttributeID}: 512000}
+
+	tests := []struct {
+		name       string
+		attributes *skillz.CharacterAttributes
+		saved      bool
+	}{
+		{name: "optimal attributes", attributes: &skillz.CharacterAttributes{Charisma: 17, Intelligence: 17, Memory: 17, Perception: 27, Willpower: 21}},
+		{name: "opposite attributes", attributes: &skillz.CharacterAttributes{Charisma: 27, Intelligence: 17, Memory: 21, Perception: 17, Willpower: 17}, saved: true},
+		// Attributes that are better than any legal remap, e.g. from an older remap system, must not report a negative saving
+		{name: "attributes above any remap", attributes: &skillz.CharacterAttributes{Charisma: 27, Intelligence: 27, Memory: 27, Perception: 27, Willpower: 27}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			recommendation := RecommendRemap(load, test.attributes, nil)
+			if recommendation.TimeSaved < 0 {
+				t.Fatalf("got negative time saved %s", recommendation.TimeSaved)
+			}
+
+			if test.saved != (recommendation.TimeSaved > 0) {
+				t.Errorf("got time saved %s, want saving %t", recommendation.TimeSaved, test.saved)
+			}
+
+			if test.saved && recommendation.TimeSaved != recommendation.CurrentDuration-recommendation.OptimalDuration {
+				t.Errorf("got time saved %s, want %s", recommendation.TimeSaved, recommendation.CurrentDuration-recommendation.OptimalDuration)
+			}
+
+		})
+	}
+
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/eveisesi/skillz"
 	"github.com/eveisesi/skillz/internal/cache"
+	"github.com/eveisesi/skillz/internal/clone"
 	"github.com/eveisesi/skillz/internal/esi"
 	"github.com/eveisesi/skillz/internal/universe"
 	"github.com/go-redis/redis/v8"
@@ -20,9 +21,27 @@ type API interface {
 	Meta(ctx context.Context, characterID uint64) (*skillz.CharacterSkillMeta, error)
 	Skillz(ctx context.Context, characterID uint64) ([]*skillz.CharacterSkill, error)
 	Attributes(ctx context.Context, characterID uint64) (*skillz.CharacterAttributes, error)
+	EffectiveAttributes(ctx context.Context, characterID uint64) (*skillz.CharacterAttributes, error)
+	JumpClones(ctx context.Context, characterID uint64) ([]*skillz.CharacterJumpClone, error)
 	Flyable(ctx context.Context, characterID uint64) ([]*skillz.ShipGroup, error)
 	SkillQueue(ctx context.Context, characterID uint64) (*skillz.CharacterSkillQueueSummary, error)
+	ValidateSkillQueue(ctx context.Context, characterID uint64) ([]*skillz.CharacterSkillQueue, error)
+	FlightRequirements(ctx context.Context, characterID uint64, shipID uint) (*skillz.FlightRequirements, error)
+	RequirementsPlan(ctx context.Context, characterID uint64, requirements []*skillz.SkillRequirement) (*skillz.SkillPlan, error)
+	SkillQueueRemap(ctx context.Context, characterID uint64) (*skillz.RemapRecommendation, error)
+	SkillTimeline(ctx context.Context, characterID uint64, from, to time.Time) (*skillz.CharacterSkillTimeline, error)
+	SkillCompletions(ctx context.Context, characterID uint64) ([]*skillz.CharacterSkillCompletion, error)
 	SkillsGrouped(ctx context.Context, characterID uint64) ([]*skillz.CharacterSkillGroup, error)
+	TrainingTime(ctx context.Context, characterID uint64, skillID uint) (*skillz.SkillTrainingTime, error)
+
+	SkillPlans(ctx context.Context, user *skillz.User) ([]*skillz.SkillPlan, error)
+	SkillPlan(ctx context.Context, user *skillz.User, planID uint) (*skillz.SkillPlan, error)
+	CreateSkillPlan(ctx context.Context, user *skillz.User, name string, entries []*skillz.SkillPlanEntry) (*skillz.SkillPlan, error)
+	RenameSkillPlan(ctx context.Context, user *skillz.User, planID uint, name string) (*skillz.SkillPlan, error)
+	ReorderSkillPlan(ctx context.Context, user *skillz.User, planID uint, order []uint) (*skillz.SkillPlan, error)
+	UpdateSkillPlanEntries(ctx context.Context, user *skillz.User, planID uint, entries []*skillz.SkillPlanEntry) (*skillz.SkillPlan, error)
+	DeleteSkillPlan(ctx context.Context, user *skillz.User, planID uint) error
+	SkillPlanRemap(ctx context.Context, user *skillz.User, planID uint) (*skillz.RemapRecommendation, error)
 }
 
 type Service struct {
@@ -31,33 +50,35 @@ type Service struct {
 	esi    esi.SkillAPI
 
 	universe universe.API
+	clones   clone.API
 
 	skills skillz.CharacterSkillRepository
 }
 
 var _ API = (*Service)(nil)
 
-func New(logger *logrus.Logger, cache cache.SkillAPI, esi esi.SkillAPI, universe universe.API, skills skillz.CharacterSkillRepository) *Service {
+func New(logger *logrus.Logger, cache cache.SkillAPI, esi esi.SkillAPI, universe universe.API, clones clone.API, skills skillz.CharacterSkillRepository) *Service {
 	return &Service{
 		logger:   logger,
 		cache:    cache,
 		esi:      esi,
 		universe: universe,
+		clones:   clones,
 		skills:   skills,
 	}
 }
 
+// Process updates the skills and attributes of the user before their skill queue, regardless of the order
+// of the scopes on the user's token, since skill completions are recorded against the updated skills
 func (s *Service) Process(ctx context.Context, user *skillz.User) error {
 
 	var err error
 	var funcs = []func(context.Context, *skillz.User) error{}
-	for _, scope := range user.Scopes {
-		switch scope {
-		case skillz.ReadSkillsV1:
-			funcs = append(funcs, s.updateSkills, s.updateAttributes)
-		case skillz.ReadSkillQueueV1:
-			funcs = append(funcs, s.updateSkillQueue)
-		}
+	if user.Scopes.Has(skillz.ReadSkillsV1) {
+		funcs = append(funcs, s.updateSkills, s.updateAttributes)
+	}
+	if user.Scopes.Has(skillz.ReadSkillQueueV1) {
+		funcs = append(funcs, s.updateSkillQueue)
 	}
 
 	for _, f := range funcs {
@@ -219,6 +240,11 @@ func (s *Service) SkillsGrouped(ctx context.Context, characterID uint64) ([]*ski
 		return nil, err
 	}
 
+	attributes, err := s.EffectiveAttributes(ctx, characterID)
+	if err != nil {
+		return nil, err
+	}
+
 	skillzMap := make(map[uint]*skillz.CharacterSkill)
 	for _, skill := range skills {
 		skillzMap[skill.SkillID] = skill
@@ -233,7 +259,7 @@ func (s *Service) SkillsGrouped(ctx context.Context, characterID uint64) ([]*ski
 			skillType := &skillz.SkillType{Type: t}
 
 			for _, attribute := range t.Attributes {
-				if attribute.AttributeID == 275 {
+				if attribute.AttributeID == skillz.SkillRankAttributeID {
 					skillType.Rank = attribute
 					break
 				}
@@ -244,6 +270,8 @@ func (s *Service) SkillsGrouped(ctx context.Context, characterID uint64) ([]*ski
 				characterSkillGroup.TotalGroupSP += skill.SkillpointsInSkill
 			}
 
+			skillType.Training = TrainingTime(t, skillType.Skill, attributes)
+
 			skillGroup.Skills = append(skillGroup.Skills, skillType)
 
 		}
@@ -286,6 +314,11 @@ func (s *Service) updateSkills(ctx context.Context, user *skillz.User) error {
 	}
 
 	if updateSkills != nil {
+		err = s.snapshotSkills(ctx, user, updateSkills)
+		if err != nil {
+			return errors.Wrap(err, "failed to snapshot skills")
+		}
+
 		err = s.skills.CreateCharacterSkillMeta(ctx, updateSkills)
 		if err != nil {
 			return errors.Wrap(err, "failed to update skill meta")
@@ -391,7 +424,26 @@ func (s *Service) SkillQueue(ctx context.Context, characterID uint64) (*skillz.C
 		mapSkillInfo[info.ID] = info
 	}
 
+	attributes, bonuses, err := s.attributesAndBonuses(ctx, characterID)
+	if err != nil {
+		return nil, err
+	}
+
+	effective := EffectiveAttributes(attributes, bonuses)
+
+	skillDogma, err := s.skillTypesByID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	skills, err := s.Skillz(ctx, characterID)
+	if err != nil {
+		return nil, err
+	}
+
 	mapGroupSummary := make(map[uint]*skillz.QueueGroupSummary)
+	summary = new(skillz.CharacterSkillQueueSummary)
+	load := make(TrainingLoad)
 
 	for _, position := range queue {
 		if _, ok := mapSkillInfo[position.SkillID]; !ok {
@@ -414,18 +466,26 @@ func (s *Service) SkillQueue(ctx context.Context, characterID uint64) (*skillz.C
 		if position.LevelEndSp.Valid && position.LevelStartSp.Valid {
 			gs.Skillpoints += position.LevelEndSp.Uint - position.LevelStartSp.Uint
 		}
-		if position.FinishDate.Valid && position.StartDate.Valid {
-			gs.Duration += time.Duration(position.FinishDate.Time.Unix() - position.StartDate.Time.Unix())
-		}
+
+		position.Duration = QueuePositionDuration(position, skillDogma[position.SkillID], effective)
+		gs.Duration += position.Duration
+		summary.Duration += position.Duration
+
+		load.Add(skillDogma[position.SkillID], QueuePositionSkillpoints(position, skillDogma[position.SkillID]))
+	}
+
+	if attributes != nil {
+		summary.ImplantTimeSaved = load.Duration(attributes) - load.Duration(effective)
 	}
 
-	summary = new(skillz.CharacterSkillQueueSummary)
 	summary.Summary = make([]*skillz.QueueGroupSummary, 0, len(mapGroupSummary))
 	for _, entry := range mapGroupSummary {
 		summary.Summary = append(summary.Summary, entry)
 	}
 	summary.Queue = queue
 
+	NewPrerequisiteGraph(skillDogma).MissingPrerequisites(queue, skills)
+
 	if len(queue) > 0 {
 		defer func(summary *skillz.CharacterSkillQueueSummary) {
 			err = s.cache.SetCharacterSkillQueueSummary(ctx, characterID, summary, time.Hour)
@@ -464,6 +524,16 @@ func (s *Service) updateSkillQueue(ctx context.Context, user *skillz.User) error
 
 	if updatedQueue != nil {
 
+		previousQueue, err := s.skills.CharacterSkillQueue(ctx, user.CharacterID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return errors.Wrap(err, "failed to fetch previous character skill queue")
+		}
+
+		err = s.recordSkillCompletions(ctx, user, previousQueue, updatedQueue)
+		if err != nil {
+			return errors.Wrap(err, "failed to record skill completions")
+		}
+
 		err = s.skills.DeleteCharacterSkillQueue(ctx, user.CharacterID)
 		if err != nil {
 			return errors.Wrap(err, "failed to delete character skill queue")
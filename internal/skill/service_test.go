@@ -0,0 +1,173 @@
+package skill_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/eveisesi/skillz"
+	"github.com/eveisesi/skillz/internal/clone"
+	"github.com/eveisesi/skillz/internal/esitest"
+	"github.com/eveisesi/skillz/internal/memory"
+	"github.com/eveisesi/skillz/internal/skill"
+	"github.com/eveisesi/skillz/internal/universe"
+)
+
+type harness struct {
+	server  *esitest.Server
+	service *skill.Service
+	skills  skillz.CharacterSkillRepository
+}
+
+// newHarness wires the skill service against the fake ESI and the in-memory repositories
+func newHarness(t *testing.T) *harness {
+	t.Helper()
+
+	env := esitest.NewHarness(t)
+
+	skillsRepo := memory.NewSkillRepository(env.DB)
+	universe := universe.New(env.Logger, env.Cache, env.ESI, memory.NewUniverseRepository(env.DB))
+	clone := clone.New(env.Logger, env.Cache, env.ETag, env.ESI, universe, memory.NewCloneRepository(env.DB))
+
+	return &harness{
+		server:  env.Server,
+		service: skill.New(env.Logger, env.Cache, env.ESI, universe, clone, skillsRepo),
+		skills:  skillsRepo,
+	}
+
+}
+
+func (h *harness) user(t *testing.T, scopes ...skillz.Scope) *skillz.User {
+	t.Helper()
+
+	token, err := h.server.Token(esitest.CharacterID, scopes...)
+	if err != nil {
+		t.Fatalf("failed to issue token: %s", err)
+	}
+
+	return &skillz.User{
+		ID:          "user-1",
+		CharacterID: esitest.CharacterID,
+		AccessToken: token,
+		Scopes:      scopes,
+	}
+}
+
+func TestProcess(t *testing.T) {
+
+	var ctx = context.Background()
+
+	h := newHarness(t)
+	user := h.user(t, skillz.ReadSkillsV1, skillz.ReadSkillQueueV1)
+
+	err := h.service.Process(ctx, user)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	meta, err := h.skills.CharacterSkillMeta(ctx, esitest.CharacterID)
+	if err != nil {
+		t.Fatalf("failed to fetch skill meta: %s", err)
+	}
+	if meta.TotalSP != 310670 || meta.UnallocatedSP.Uint != 50000 {
+		t.Errorf("got total sp %d and unallocated sp %d, want 310670 and 50000", meta.TotalSP, meta.UnallocatedSP.Uint)
+	}
+
+	skills, err := h.skills.CharacterSkills(ctx, esitest.CharacterID)
+	if err != nil {
+		t.Fatalf("failed to fetch skills: %s", err)
+	}
+	if len(skills) != 4 {
+		t.Errorf("got %d skills, want 4", len(skills))
+	}
+
+	attributes, err := h.skills.CharacterAttributes(ctx, esitest.CharacterID)
+	if err != nil {
+		t.Fatalf("failed to fetch attributes: %s", err)
+	}
+	if attributes.Intelligence != 27 || attributes.Memory != 21 {
+		t.Errorf("got intelligence %d and memory %d, want 27 and 21", attributes.Intelligence, attributes.Memory)
+	}
+
+	queue, err := h.skills.CharacterSkillQueue(ctx, esitest.CharacterID)
+	if err != nil {
+		t.Fatalf("failed to fetch skill queue: %s", err)
+	}
+	if len(queue) != 2 || queue[0].SkillID != 3328 {
+		t.Errorf("got a queue of %d positions, want 2 starting with skill 3328", len(queue))
+	}
+
+}
+
+func TestProcessOrderIgnoresScopeOrder(t *testing.T) {
+
+	var ctx = context.Background()
+
+	h := newHarness(t)
+
+	err := h.service.Process(ctx, h.user(t, skillz.ReadSkillQueueV1, skillz.ReadSkillsV1))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var want = []string{
+		"GET /v4/characters/90000001/skills/",
+		"GET /v1/characters/90000001/attributes/",
+		"GET /v2/characters/90000001/skillqueue/",
+	}
+
+	var got = make([]string, 0, len(want))
+	for _, request := range h.server.Requests() {
+		for _, w := range want {
+			if request == w {
+				got = append(got, request)
+			}
+		}
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("got requests %v, want %v", got, want)
+	}
+
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got requests %v, want %v", got, want)
+		}
+	}
+
+}
+
+func TestProcessWithoutScopes(t *testing.T) {
+
+	var ctx = context.Background()
+
+	h := newHarness(t)
+
+	err := h.service.Process(ctx, h.user(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if requests := h.server.Requests(); len(requests) != 0 {
+		t.Errorf("expected no requests to esi without scopes, got %v", requests)
+	}
+
+}
+
+func TestProcessForbidden(t *testing.T) {
+
+	var ctx = context.Background()
+
+	h := newHarness(t)
+	h.server.SetStatus("/v4/characters/90000001/skills/", 403)
+
+	err := h.service.Process(ctx, h.user(t, skillz.ReadSkillsV1))
+	if err == nil {
+		t.Fatal("expected an error when esi rejects the token, got nil")
+	}
+
+	_, err = h.skills.CharacterSkillMeta(ctx, esitest.CharacterID)
+	if err == nil {
+		t.Error("expected no skill meta to be stored when esi rejects the token")
+	}
+
+}
@@ -0,0 +1,143 @@
+package skill
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/eveisesi/skillz"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// SkillChanges compares the previous skills of a character against their current skills and returns
+// the skills whose trained level or skillpoints have changed. Skills that are new to the character are
+// compared against level 0 with 0 skillpoints
+func SkillChanges(previous, current []*skillz.CharacterSkill) []*skillz.CharacterSkillChange {
+
+	previousBySkill := make(map[uint]*skillz.CharacterSkill, len(previous))
+	for _, skill := range previous {
+		previousBySkill[skill.SkillID] = skill
+	}
+
+	changes := make([]*skillz.CharacterSkillChange, 0)
+	for _, skill := range current {
+		change := &skillz.CharacterSkillChange{
+			CharacterID:        skill.CharacterID,
+			SkillID:            skill.SkillID,
+			TrainedSkillLevel:  skill.TrainedSkillLevel,
+			SkillpointsInSkill: skill.SkillpointsInSkill,
+		}
+
+		if prev, ok := previousBySkill[skill.SkillID]; ok {
+			if prev.TrainedSkillLevel == skill.TrainedSkillLevel && prev.SkillpointsInSkill == skill.SkillpointsInSkill {
+				continue
+			}
+
+			change.PreviousSkillLevel = prev.TrainedSkillLevel
+			change.PreviousSkillpoints = prev.SkillpointsInSkill
+		}
+
+		changes = append(changes, change)
+	}
+
+	return changes
+
+}
+
+// SkillTimeline returns the character's skillpoint curve and the skill levels they trained between the provided dates
+func (s *Service) SkillTimeline(ctx context.Context, characterID uint64, from, to time.Time) (*skillz.CharacterSkillTimeline, error) {
+
+	snapshots, err := s.skills.CharacterSkillSnapshots(ctx, characterID, from, to)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.Wrap(err, "failed to fetch character skill snapshots from data store")
+	}
+
+	changes, err := s.skills.CharacterSkillChanges(ctx, characterID, from, to)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.Wrap(err, "failed to fetch character skill changes from data store")
+	}
+
+	types, err := s.skillTypesByID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshotsByID := make(map[uint64]*skillz.CharacterSkillSnapshot, len(snapshots))
+	for _, snapshot := range snapshots {
+		snapshotsByID[snapshot.ID] = snapshot
+	}
+
+	levelUps := make([]*skillz.CharacterSkillChange, 0)
+	for _, change := range changes {
+		change.Type = types[change.SkillID]
+
+		if snapshot, ok := snapshotsByID[change.SnapshotID]; ok {
+			snapshot.Changes = append(snapshot.Changes, change)
+		}
+
+		if change.TrainedSkillLevel > change.PreviousSkillLevel {
+			levelUps = append(levelUps, change)
+		}
+	}
+
+	return &skillz.CharacterSkillTimeline{
+		From:      from,
+		To:        to,
+		Snapshots: snapshots,
+		LevelUps:  levelUps,
+	}, nil
+
+}
+
+// snapshotSkills records the total skillpoints of the character along with the skills that changed
+// since the previous run. It must be called before the character's current skills are overwritten.
+// The first snapshot of a character is a baseline and does not record any changes
+func (s *Service) snapshotSkills(ctx context.Context, user *skillz.User, meta *skillz.CharacterSkillMeta) error {
+
+	s.logger.WithFields(logrus.Fields{
+		"service": "skill",
+		"userID":  user.ID,
+	}).Info("snapshotting skills")
+
+	_, err := s.skills.CharacterSkillMeta(ctx, user.CharacterID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return errors.Wrap(err, "failed to fetch previous skill meta from data store")
+	}
+
+	baseline := errors.Is(err, sql.ErrNoRows)
+
+	var changes []*skillz.CharacterSkillChange
+	if !baseline {
+		previous, err := s.skills.CharacterSkills(ctx, user.CharacterID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return errors.Wrap(err, "failed to fetch previous skills from data store")
+		}
+
+		changes = SkillChanges(previous, meta.Skills)
+	}
+
+	snapshot := &skillz.CharacterSkillSnapshot{
+		CharacterID:   user.CharacterID,
+		TotalSP:       meta.TotalSP,
+		UnallocatedSP: meta.UnallocatedSP,
+	}
+
+	err = s.skills.CreateCharacterSkillSnapshot(ctx, snapshot)
+	if err != nil {
+		return errors.Wrap(err, "failed to create skill snapshot")
+	}
+
+	if len(changes) == 0 {
+		return nil
+	}
+
+	for _, change := range changes {
+		change.SnapshotID = snapshot.ID
+		change.CharacterID = user.CharacterID
+	}
+
+	err = s.skills.CreateCharacterSkillChanges(ctx, changes)
+	return errors.Wrap(err, "failed to create skill changes")
+
+}
@@ -0,0 +1,186 @@
+package skill
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/eveisesi/skillz"
+	"github.com/eveisesi/skillz/internal/universe"
+	"github.com/pkg/errors"
+)
+
+const maxSkillLevel uint = 5
+
+// SkillpointsForLevel returns the total number of skillpoints a skill of the
+// provided rank needs to be trained to the provided level
+func SkillpointsForLevel(rank float64, level uint) uint {
+	if level == 0 {
+		return 0
+	}
+
+	if level > maxSkillLevel {
+		level = maxSkillLevel
+	}
+
+	// sqrt(32)^(level-1) is written as a power of two, which is exact for the odd levels. Raising the
+	// float approximation of sqrt(32) accumulates an error that the ceiling turns into an extra skillpoint
+	return uint(math.Ceil(250 * rank * math.Pow(2, 2.5*float64(level-1))))
+}
+
+// SkillRank returns the rank of the skill, defaulting to 1 when the dogma attribute is missing
+func SkillRank(t *skillz.Type) float64 {
+	if t == nil {
+		return 1
+	}
+
+	attribute := t.GetAttribute(skillz.SkillRankAttributeID)
+	if attribute == nil || attribute.Value <= 0 {
+		return 1
+	}
+
+	return attribute.Value
+}
+
+// SPPerMinute returns the number of skillpoints per minute the character trains the skill at.
+// Zero is returned when the skill is missing its attribute dogma or the character has no attributes
+func SPPerMinute(t *skillz.Type, attributes *skillz.CharacterAttributes) float64 {
+	if t == nil || attributes == nil {
+		return 0
+	}
+
+	primary := t.GetAttribute(skillz.SkillPrimaryAttributeAttributeID)
+	secondary := t.GetAttribute(skillz.SkillSecondaryAttributeAttributeID)
+	if primary == nil || secondary == nil {
+		return 0
+	}
+
+	return float64(attributes.AttributeValue(uint(primary.Value))) + float64(attributes.AttributeValue(uint(secondary.Value)))/2
+}
+
+// TrainingDuration returns how long it takes to train the provided number of skillpoints at the provided rate
+func TrainingDuration(skillpoints uint, spPerMinute float64) time.Duration {
+	if skillpoints == 0 || spPerMinute <= 0 {
+		return 0
+	}
+
+	return time.Duration(float64(skillpoints) / spPerMinute * float64(time.Minute)).Round(time.Second)
+}
+
+// TrainingTime calculates the training speed of a skill for a character, along with the time
+// it takes to get from the character's current progress to the next level and to level V
+func TrainingTime(t *skillz.Type, skill *skillz.CharacterSkill, attributes *skillz.CharacterAttributes) *skillz.SkillTrainingTime {
+
+	out := &skillz.SkillTrainingTime{
+		SPPerMinute: SPPerMinute(t, attributes),
+	}
+
+	if t != nil {
+		out.SkillID = t.ID
+	}
+
+	var level, sp uint
+	if skill != nil {
+		level, sp = skill.TrainedSkillLevel, skill.SkillpointsInSkill
+	}
+
+	if level >= maxSkillLevel {
+		return out
+	}
+
+	rank := SkillRank(t)
+	if next := SkillpointsForLevel(rank, level+1); next > sp {
+		out.TimeToNextLevel = TrainingDuration(next-sp, out.SPPerMinute)
+	}
+
+	if max := SkillpointsForLevel(rank, maxSkillLevel); max > sp {
+		out.TimeToLevelV = TrainingDuration(max-sp, out.SPPerMinute)
+	}
+
+	return out
+
+}
+
+// QueuePositionDuration returns the time required to train the provided queue position.
+// When ESI has provided a start and finish date, the difference between them is used, else the
+// remaining skillpoints for the position are calculated using the skill's rank and the character's attributes
+func QueuePositionDuration(position *skillz.CharacterSkillQueue, t *skillz.Type, attributes *skillz.CharacterAttributes) time.Duration {
+
+	if position.StartDate.Valid && position.FinishDate.Valid {
+		return position.FinishDate.Time.Sub(position.StartDate.Time).Round(time.Second)
+	}
+
+	return TrainingDuration(QueuePositionSkillpoints(position, t), SPPerMinute(t, attributes))
+
+}
+
+// QueuePositionSkillpoints returns the number of skillpoints that remain to be trained for the provided
+// queue position, preferring the skillpoint values provided by ESI over values calculated from the skill's rank
+func QueuePositionSkillpoints(position *skillz.CharacterSkillQueue, t *skillz.Type) uint {
+
+	if position.FinishedLevel == 0 {
+		return 0
+	}
+
+	rank := SkillRank(t)
+
+	start := SkillpointsForLevel(rank, position.FinishedLevel-1)
+	if position.LevelStartSp.Valid {
+		start = position.LevelStartSp.Uint
+	}
+	if position.TrainingStartSp.Valid && position.TrainingStartSp.Uint > start {
+		start = position.TrainingStartSp.Uint
+	}
+
+	end := SkillpointsForLevel(rank, position.FinishedLevel)
+	if position.LevelEndSp.Valid {
+		end = position.LevelEndSp.Uint
+	}
+
+	if end <= start {
+		return 0
+	}
+
+	return end - start
+
+}
+
+// skillTypesByID returns skill types hydrated with their dogma attributes keyed by type id
+func (s *Service) skillTypesByID(ctx context.Context) (map[uint]*skillz.Type, error) {
+
+	groups, err := s.universe.TypeGroupsHydrated(ctx, universe.CategorySkills)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch hydrated skill groups from universe")
+	}
+
+	types := make(map[uint]*skillz.Type)
+	for _, group := range groups {
+		for _, t := range group.Types {
+			types[t.ID] = t
+		}
+	}
+
+	return types, nil
+
+}
+
+func (s *Service) TrainingTime(ctx context.Context, characterID uint64, skillID uint) (*skillz.SkillTrainingTime, error) {
+
+	t, err := s.universe.Type(ctx, skillID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch skill type")
+	}
+
+	attributes, err := s.EffectiveAttributes(ctx, characterID)
+	if err != nil {
+		return nil, err
+	}
+
+	skills, err := s.Skillz(ctx, characterID)
+	if err != nil {
+		return nil, err
+	}
+
+	return TrainingTime(t, skillFromSkillSlice(skillID, skills), attributes), nil
+
+}
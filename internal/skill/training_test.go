@@ -0,0 +1,148 @@
+package skill
+
+import (
+	"testing"
+	"time"
+
+	"github.com/eveisesi/skillz"
+	"github.com/volatiletech/null"
+)
+
+// trainingSkill returns a skill of the provided rank that trains on intelligence and memory
+func trainingSkill(rank float64) *skillz.Type {
+	return &skillz.Type{ID: 3413, Name: "Power Grid Management", Attributes: []*skillz.TypeDogmaAttribute{
+		{AttributeID: skillz.SkillRankAttributeID, Value: rank},
+		{AttributeID: skillz.SkillPrimaryAttributeAttributeID, Value: float64(skillz.IntelligenceAttributeID)},
+		{AttributeID: skillz.SkillSecondaryAttributeAttributeID, Value: float64(skillz.MemoryAttributeID)},
+	}}
+}
+
+func TestSkillpointsForLevel(t *testing.T) {
+
+	tests := []struct {
+		name  string
+		rank  float64
+		level uint
+		want  uint
+	}{
+		{name: "level 0", rank: 1, level: 0, want: 0},
+		{name: "rank 1 level I", rank: 1, level: 1, want: 250},
+		{name: "rank 1 level II", rank: 1, level: 2, want: 1415},
+		{name: "rank 1 level III", rank: 1, level: 3, want: 8000},
+		{name: "rank 1 level IV", rank: 1, level: 4, want: 45255},
+		{name: "rank 1 level V", rank: 1, level: 5, want: 256000},
+		{name: "rank 5 level V", rank: 5, level: 5, want: 1280000},
+		{name: "level above V", rank: 1, level: 6, want: 256000},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := SkillpointsForLevel(test.rank, test.level); got != test.want {
+				t.Errorf("got %d skillpoints, want %d", got, test.want)
+			}
+		})
+	}
+
+}
+
+func TestSPPerMinute(t *testing.T) {
+
+	attributes := &skillz.CharacterAttributes{Charisma: 17, Intelligence: 27, Memory: 21, Perception: 17, Willpower: 17}
+
+	tests := []struct {
+		name       string
+		skill      *skillz.Type
+		attributes *skillz.CharacterAttributes
+		want       float64
+	}{
+		{name: "primary and secondary attribute", skill: trainingSkill(1), attributes: attributes, want: 37.5},
+		{name: "missing attributes", skill: trainingSkill(1), want: 0},
+		{name: "missing skill", attributes: attributes, want: 0},
+		{name: "missing attribute dogma", skill: &skillz.Type{ID: 3413}, attributes: attributes, want: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := SPPerMinute(test.skill, test.attributes); got != test.want {
+				t.Errorf("got %v sp per minute, want %v", got, test.want)
+			}
+		})
+	}
+
+}
+
+func TestTrainingDuration(t *testing.T) {
+
+	tests := []struct {
+		name        string
+		skillpoints uint
+		spPerMinute float64
+		want        time.Duration
+	}{
+		{name: "rank 1 level V from scratch", skillpoints: 256000, spPerMinute: 37.5, want: 113*time.Hour + 46*time.Minute + 40*time.Second},
+		{name: "rounded to the second", skillpoints: 1415, spPerMinute: 37.5, want: 37*time.Minute + 44*time.Second},
+		{name: "no skillpoints", skillpoints: 0, spPerMinute: 37.5, want: 0},
+		{name: "no training speed", skillpoints: 256000, spPerMinute: 0, want: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := TrainingDuration(test.skillpoints, test.spPerMinute); got != test.want {
+				t.Errorf("got %s, want %s", got, test.want)
+			}
+		})
+	}
+
+}
+
+func TestQueuePositionDuration(t *testing.T) {
+
+	attributes := &skillz.CharacterAttributes{Charisma: 17, Intelligence: 27, Memory: 21, Perception: 17, Willpower: 17}
+	start := time.Date(2021, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		position *skillz.CharacterSkillQueue
+		want     time.Duration
+	}{
+		{
+			name: "dates provided by ESI",
+			position: &skillz.CharacterSkillQueue{
+				FinishedLevel: 5,
+				StartDate:     null.TimeFrom(start),
+				FinishDate:    null.TimeFrom(start.Add(90 * time.Minute)),
+			},
+			want: 90 * time.Minute,
+		},
+		{
+			// A paused queue has no dates, so the duration is calculated from the skill's rank and the attributes
+			name:     "paused queue calculated from rank",
+			position: &skillz.CharacterSkillQueue{FinishedLevel: 5},
+			want:     TrainingDuration(256000-45255, 37.5),
+		},
+		{
+			name: "paused queue calculated from the skillpoints provided by ESI",
+			position: &skillz.CharacterSkillQueue{
+				FinishedLevel:   5,
+				TrainingStartSp: null.UintFrom(100000),
+				LevelStartSp:    null.UintFrom(45255),
+				LevelEndSp:      null.UintFrom(256000),
+			},
+			want: TrainingDuration(156000, 37.5),
+		},
+		{
+			name:     "paused queue with only a start date",
+			position: &skillz.CharacterSkillQueue{FinishedLevel: 1, StartDate: null.TimeFrom(start)},
+			want:     TrainingDuration(250, 37.5),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := QueuePositionDuration(test.position, trainingSkill(1), attributes); got != test.want {
+				t.Errorf("got %s, want %s", got, test.want)
+			}
+		})
+	}
+
+}
@@ -31,6 +31,8 @@ type API interface {
 	TypeGroupsHydrated(ctx context.Context, categoryID uint) ([]*skillz.Group, error)
 	TypeAttributes(ctx context.Context, id uint) ([]*skillz.TypeDogmaAttribute, error)
 	TypesByGroup(ctx context.Context, groupID uint) ([]*skillz.Type, error)
+	TypesByName(ctx context.Context, names []string) ([]*skillz.Type, error)
+	ShipFlightRequirements(ctx context.Context, shipID uint) ([]*skillz.ShipFlightRequirement, error)
 }
 
 type Service struct {
@@ -257,7 +259,8 @@ func (s *Service) Station(ctx context.Context, stationID uint) (*skillz.Station,
 
 }
 
-// TODO: Need Auth Token from Currently Authenticated Character
+// Structure returns the structure from the data store. Structures that have not been seen before are fetched from
+// ESI with the token of the user in the context, when the user has granted the structures scope
 func (s *Service) Structure(ctx context.Context, structureID uint64) (*skillz.Structure, error) {
 
 	structure, err := s.cache.Structure(ctx, structureID)
@@ -281,8 +284,10 @@ func (s *Service) Structure(ctx context.Context, structureID uint64) (*skillz.St
 
 	exists := err == nil
 	if !exists {
+		// ESI rejects requests for structures with tokens that lack the structures scope, and every
+		// rejection counts against the error limit
 		user := internal.UserFromContext(ctx)
-		if user == nil {
+		if user == nil || !user.Scopes.Has(skillz.ReadStructuresV1) {
 			return nil, nil
 		}
 
@@ -370,9 +375,14 @@ func (s *Service) SkillTypesHydrated(ctx context.Context) ([]*skillz.Type, error
 }
 
 const (
-	CategoryShips    = uint(6)
-	CategorySkills   = uint(16)
-	CategoryImplants = uint(20)
+	CategoryShips      = uint(6)
+	CategoryModules    = uint(7)
+	CategoryCharges    = uint(8)
+	CategorySkills     = uint(16)
+	CategoryDrones     = uint(18)
+	CategoryImplants   = uint(20)
+	CategorySubsystems = uint(32)
+	CategoryFighters   = uint(87)
 )
 
 func (s *Service) TypeGroupsHydrated(ctx context.Context, categoryID uint) ([]*skillz.Group, error) {
@@ -600,3 +610,58 @@ func (s *Service) TypesByGroup(ctx context.Context, groupID uint) ([]*skillz.Typ
 	return types, s.cache.SetTypesByGroupID(ctx, groupID, types)
 
 }
+
+// TypesByName fetches the types that exactly match the provided names, hydrated with their dogma attributes.
+// Names that do not match a type are omitted from the results
+func (s *Service) TypesByName(ctx context.Context, names []string) ([]*skillz.Type, error) {
+
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	values := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		values = append(values, name)
+	}
+
+	types, err := s.universe.Types(ctx, skillz.NewInOperator(mysql.TypesName, values))
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.Wrap(err, "failed to fetch types by name from data store")
+	}
+
+	if len(types) == 0 {
+		return types, nil
+	}
+
+	typeIDs := make([]uint, 0, len(types))
+	mapTypes := make(map[uint]*skillz.Type, len(types))
+	for _, t := range types {
+		typeIDs = append(typeIDs, t.ID)
+		mapTypes[t.ID] = t
+	}
+
+	attributes, err := s.universe.TypeDogmaAttributesBulk(ctx, typeIDs)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.Wrap(err, "failed to fetch type attributes from data store")
+	}
+
+	for _, attribute := range attributes {
+		if t, ok := mapTypes[attribute.TypeID]; ok {
+			t.Attributes = append(t.Attributes, attribute)
+		}
+	}
+
+	return types, nil
+
+}
+
+func (s *Service) ShipFlightRequirements(ctx context.Context, shipID uint) ([]*skillz.ShipFlightRequirement, error) {
+
+	requirements, err := s.universe.ShipFlightRequirements(ctx, shipID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.Wrap(err, "failed to fetch ship flight requirements from data store")
+	}
+
+	return requirements, nil
+
+}
@@ -0,0 +1,123 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/eveisesi/skillz"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+)
+
+var ErrUserNotLinked = errors.New("character is not linked to this account")
+
+// LinkedUsers returns the users linked to the same account as the provided user, including the user
+// itself, loaded with their characters. Users whose owner hash has changed since they were linked, i.e.
+// the character was transferred to another player, are left out. Their links are cut when they log in
+func (s *Service) LinkedUsers(ctx context.Context, user *skillz.User) ([]*skillz.User, error) {
+
+	account, err := s.UserRepository.UserAccount(ctx, user.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.Wrap(err, "failed to fetch user account")
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return []*skillz.User{user}, nil
+	}
+
+	accounts, err := s.UserRepository.UserAccountsByAccountID(ctx, account.AccountID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.Wrap(err, "failed to fetch linked users")
+	}
+
+	users := make([]*skillz.User, 0, len(accounts))
+	for _, account := range accounts {
+		u, err := s.User(ctx, account.UserID, UserCharacterRel)
+		if err != nil && !errors.Is(err, ErrUserNotFound) {
+			return nil, err
+		}
+
+		if errors.Is(err, ErrUserNotFound) {
+			continue
+		}
+
+		if u.OwnerHash != account.OwnerHash {
+			continue
+		}
+
+		users = append(users, u)
+	}
+
+	if len(users) == 0 {
+		return []*skillz.User{user}, nil
+	}
+
+	return users, nil
+
+}
+
+// LinkUser links the linked user to the account of the user. The user's account is created
+// under a newly generated id when the user is not yet linked to any other characters, or has
+// been transferred since it was linked, which moves it out of the previous owner's account
+func (s *Service) LinkUser(ctx context.Context, user, linked *skillz.User) error {
+
+	if user.ID == linked.ID {
+		return nil
+	}
+
+	account, err := s.UserRepository.UserAccount(ctx, user.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return errors.Wrap(err, "failed to fetch user account")
+	}
+
+	if errors.Is(err, sql.ErrNoRows) || account.OwnerHash != user.OwnerHash {
+		account = &skillz.UserAccount{
+			UserID:    user.ID,
+			AccountID: uuid.Must(uuid.NewV4()).String(),
+			OwnerHash: user.OwnerHash,
+		}
+
+		err = s.UserRepository.CreateUserAccount(ctx, account)
+		if err != nil {
+			return errors.Wrap(err, "failed to create user account")
+		}
+	}
+
+	err = s.UserRepository.CreateUserAccount(ctx, &skillz.UserAccount{
+		UserID:    linked.ID,
+		AccountID: account.AccountID,
+		OwnerHash: linked.OwnerHash,
+	})
+	return errors.Wrap(err, "failed to link user to account")
+
+}
+
+// UnlinkUser removes the linked user from the user's account. A user is able to unlink itself
+func (s *Service) UnlinkUser(ctx context.Context, user *skillz.User, linkedID string) error {
+
+	users, err := s.LinkedUsers(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	for _, u := range users {
+		if u.ID != linkedID {
+			continue
+		}
+
+		err = s.UserRepository.DeleteUserAccount(ctx, linkedID)
+		return errors.Wrap(err, "failed to unlink user from account")
+	}
+
+	return ErrUserNotLinked
+
+}
+
+func (s *Service) unlinkTransferredUser(ctx context.Context, user *skillz.User) error {
+
+	s.logger.WithField("userID", user.ID).Info("owner hash has changed, unlinking user from account")
+
+	err := s.UserRepository.DeleteUserAccount(ctx, user.ID)
+	return errors.Wrap(err, "failed to unlink transferred user from account")
+
+}
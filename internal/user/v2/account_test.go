@@ -0,0 +1,176 @@
+package user_test
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/eveisesi/skillz"
+	"github.com/eveisesi/skillz/internal/esitest"
+)
+
+// newOwnerHash is the owner hash of the player the fixture character is transferred to
+const newOwnerHash = "n3wOwn3rHash="
+
+// addCharacter records a character in the fixture character's corporation
+func (h *harness) addCharacter(characterID uint64, name string) {
+	h.server.SetFixture(fmt.Sprintf("/characters/%d/", characterID), []byte(fmt.Sprintf(
+		`{"birthday":"2015-03-24T11:37:00Z","bloodline_id":4,"corporation_id":%d,"gender":"male","name":%q,"race_id":2}`,
+		esitest.CorporationID, name,
+	)))
+}
+
+// processedLogin logs the character in and marks the user as processed, the way the processor does after the first login
+func (h *harness) processedLogin(t *testing.T, characterID uint64) *skillz.User {
+	t.Helper()
+
+	u, err := h.login(t, characterID)
+	if err != nil {
+		t.Fatalf("failed to login character %d: %s", characterID, err)
+	}
+
+	u.IsNew = false
+	err = h.users.CreateUser(context.Background(), u)
+	if err != nil {
+		t.Fatalf("failed to update user: %s", err)
+	}
+
+	return u
+}
+
+func linkedIDs(t *testing.T, h *harness, u *skillz.User) []string {
+	t.Helper()
+
+	users, err := h.service.LinkedUsers(context.Background(), u)
+	if err != nil {
+		t.Fatalf("failed to fetch linked users: %s", err)
+	}
+
+	ids := make([]string, 0, len(users))
+	for _, linked := range users {
+		ids = append(ids, linked.ID)
+	}
+	sort.Strings(ids)
+
+	return ids
+}
+
+func requireLinked(t *testing.T, h *harness, u *skillz.User, want ...*skillz.User) {
+	t.Helper()
+
+	ids := make([]string, 0, len(want))
+	for _, w := range want {
+		ids = append(ids, w.ID)
+	}
+	sort.Strings(ids)
+
+	got := linkedIDs(t, h, u)
+	if fmt.Sprint(got) != fmt.Sprint(ids) {
+		t.Errorf("got linked users %v for %s, want %v", got, u.ID, ids)
+	}
+}
+
+func TestLinkUser(t *testing.T) {
+
+	var ctx = context.Background()
+
+	h := newHarness(t)
+	h.addCharacter(90000002, "Alt Pilot")
+
+	main := h.processedLogin(t, esitest.CharacterID)
+	alt := h.processedLogin(t, 90000002)
+
+	err := h.service.LinkUser(ctx, main, alt)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	account, err := h.users.UserAccount(ctx, main.ID)
+	if err != nil {
+		t.Fatalf("failed to fetch account: %s", err)
+	}
+	if account.AccountID == main.ID {
+		t.Error("expected the account to be created under a generated id, not the id of the user")
+	}
+
+	requireLinked(t, h, main, main, alt)
+	requireLinked(t, h, alt, main, alt)
+
+}
+
+func TestLinkUserAfterTransfer(t *testing.T) {
+
+	var ctx = context.Background()
+
+	h := newHarness(t)
+	h.addCharacter(90000002, "Previous Alt")
+	h.addCharacter(90000003, "New Main")
+	h.server.TransferCharacter(90000003, newOwnerHash)
+
+	transferred := h.processedLogin(t, esitest.CharacterID)
+	alt := h.processedLogin(t, 90000002)
+
+	err := h.service.LinkUser(ctx, transferred, alt)
+	if err != nil {
+		t.Fatalf("failed to link users: %s", err)
+	}
+
+	h.server.TransferCharacter(esitest.CharacterID, newOwnerHash)
+
+	transferred = h.processedLogin(t, esitest.CharacterID)
+	if transferred.OwnerHash != newOwnerHash {
+		t.Fatalf("expected the user to be updated with the new owner hash, got %s", transferred.OwnerHash)
+	}
+
+	requireLinked(t, h, alt, alt)
+
+	main := h.processedLogin(t, 90000003)
+
+	err = h.service.LinkUser(ctx, transferred, main)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// The new owner must not inherit the previous owner's alt
+	requireLinked(t, h, main, main, transferred)
+	requireLinked(t, h, transferred, main, transferred)
+	requireLinked(t, h, alt, alt)
+
+}
+
+func TestLinkUserTransferredSinceLinked(t *testing.T) {
+
+	var ctx = context.Background()
+
+	h := newHarness(t)
+	h.addCharacter(90000002, "Previous Alt")
+	h.addCharacter(90000003, "New Alt")
+
+	transferred := h.processedLogin(t, esitest.CharacterID)
+	alt := h.processedLogin(t, 90000002)
+
+	err := h.service.LinkUser(ctx, transferred, alt)
+	if err != nil {
+		t.Fatalf("failed to link users: %s", err)
+	}
+
+	// The owner hash changed without the links being cut, i.e. nothing has listed the linked users since the transfer
+	transferred.OwnerHash = newOwnerHash
+	err = h.users.CreateUser(ctx, transferred)
+	if err != nil {
+		t.Fatalf("failed to update user: %s", err)
+	}
+
+	h.server.TransferCharacter(90000003, newOwnerHash)
+	newAlt := h.processedLogin(t, 90000003)
+
+	err = h.service.LinkUser(ctx, transferred, newAlt)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	requireLinked(t, h, transferred, transferred, newAlt)
+	requireLinked(t, h, alt, alt)
+
+}
@@ -0,0 +1,277 @@
+package user
+
+import (
+	"context"
+	"sort"
+
+	"github.com/eveisesi/skillz"
+	"github.com/pkg/errors"
+)
+
+var ErrInvalidComparison = errors.New("comparison is invalid")
+
+// MaxComparedUsers limits the number of users that can be compared at once
+const MaxComparedUsers = 5
+
+// CanView reports whether the viewer is allowed to view the user based on the user's visibility
+// settings. Private users can only be viewed by themselves and token users can be viewed by
+// anybody that provides the user's visibility token. The viewer may be nil
+func CanView(user, viewer *skillz.User, token string) bool {
+
+	settings := user.Settings
+	if settings == nil || settings.Visibility == skillz.VisibilityPublic {
+		return true
+	}
+
+	if settings.Visibility == skillz.VisibilityToken && token != "" {
+		return token == settings.VisibilityToken
+	}
+
+	return viewer != nil && viewer.ID == user.ID
+
+}
+
+// CompareUsers loads the users with the provided ids and compares their skills, flyable ships and
+// attributes. Users that do not exist and users that the viewer is not allowed to view are reported
+// as not found. Besides the users CanView allows, the viewer is allowed to view the users linked to their
+// account, so that private alts can be compared. The viewer may be nil, in which case only public users
+// can be compared
+func (s *Service) CompareUsers(ctx context.Context, viewer *skillz.User, ids []string) (*skillz.UserComparison, error) {
+
+	var seen = make(map[string]bool, len(ids))
+	var unique = make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id == "" || seen[id] {
+			continue
+		}
+
+		seen[id] = true
+		unique = append(unique, id)
+	}
+
+	if len(unique) < 2 {
+		return nil, errors.Wrap(ErrInvalidComparison, "at least two users are required")
+	}
+
+	if len(unique) > MaxComparedUsers {
+		return nil, errors.Wrapf(ErrInvalidComparison, "no more than %d users can be compared", MaxComparedUsers)
+	}
+
+	var linked = make(map[string]bool)
+	if viewer != nil {
+		users, err := s.LinkedUsers(ctx, viewer)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, user := range users {
+			linked[user.ID] = true
+		}
+	}
+
+	users := make([]*skillz.User, 0, len(unique))
+	for _, id := range unique {
+		user, err := s.User(ctx, id, UserCharacterRel, UserSkillsRel, UserFlyableRel, UserAttributesRel)
+		if err != nil {
+			return nil, err
+		}
+
+		if !(linked[user.ID] || CanView(user, viewer, "")) || user.Character == nil {
+			return nil, ErrUserNotFound
+		}
+
+		users = append(users, user)
+	}
+
+	return Compare(users), nil
+
+}
+
+// Compare builds a comparison of the skills grouped by skill group, flyable ships and attributes of the
+// users. Only skills that at least one of the users has trained and ships that at least one of the
+// users can fly are included. Sections a user has hidden through their settings are not compared
+func Compare(users []*skillz.User) *skillz.UserComparison {
+
+	comparison := &skillz.UserComparison{
+		Users:            users,
+		SkillsHidden:     make([]bool, len(users)),
+		FlyableHidden:    make([]bool, len(users)),
+		AttributesHidden: make([]bool, len(users)),
+	}
+
+	for i, user := range users {
+		if user.Settings == nil {
+			continue
+		}
+
+		comparison.SkillsHidden[i] = user.Settings.HideSkills
+		comparison.FlyableHidden[i] = user.Settings.HideFlyable
+		comparison.AttributesHidden[i] = user.Settings.HideAttributes
+		if user.Settings.HideAttributes {
+			user.Attributes = nil
+		}
+	}
+
+	comparison.Groups = compareSkills(users, comparison.SkillsHidden)
+	comparison.ShipGroups = compareShips(users, comparison.FlyableHidden)
+
+	return comparison
+
+}
+
+func compareSkills(users []*skillz.User, hidden []bool) []*skillz.SkillGroupComparison {
+
+	var groups = make(map[uint]*skillz.SkillGroupComparison)
+	var skills = make(map[uint]*skillz.SkillComparison)
+
+	for i, user := range users {
+		if hidden[i] {
+			continue
+		}
+
+		for _, group := range user.SkillsGrouped {
+			if group == nil || group.SkillGroup == nil || group.Group == nil {
+				continue
+			}
+
+			g, ok := groups[group.ID]
+			if !ok {
+				g = &skillz.SkillGroupComparison{
+					GroupID:     group.ID,
+					Name:        group.Name,
+					Skillpoints: make([]uint, len(users)),
+				}
+				groups[group.ID] = g
+			}
+
+			g.Skillpoints[i] = group.TotalGroupSP
+
+			for _, skill := range group.Skills {
+				if skill == nil || skill.Type == nil || skill.Skill == nil || skill.Skill.TrainedSkillLevel == 0 {
+					continue
+				}
+
+				c, ok := skills[skill.ID]
+				if !ok {
+					c = &skillz.SkillComparison{
+						SkillID:     skill.ID,
+						Name:        skill.Name,
+						Levels:      make([]uint, len(users)),
+						Skillpoints: make([]uint, len(users)),
+					}
+					skills[skill.ID] = c
+					g.Skills = append(g.Skills, c)
+				}
+
+				c.Levels[i] = skill.Skill.TrainedSkillLevel
+				c.Skillpoints[i] = skill.Skill.SkillpointsInSkill
+			}
+		}
+	}
+
+	results := make([]*skillz.SkillGroupComparison, 0, len(groups))
+	for _, group := range groups {
+		for _, skill := range group.Skills {
+			skill.Unique = isUnique(len(users), hidden, func(i int) bool { return skill.Levels[i] > 0 })
+		}
+
+		sort.Slice(group.Skills, func(i, j int) bool {
+			return group.Skills[i].Name < group.Skills[j].Name
+		})
+
+		results = append(results, group)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Name < results[j].Name
+	})
+
+	return results
+
+}
+
+func compareShips(users []*skillz.User, hidden []bool) []*skillz.ShipGroupComparison {
+
+	var groups = make(map[uint]*skillz.ShipGroupComparison)
+	var ships = make(map[uint]*skillz.ShipComparison)
+
+	for i, user := range users {
+		if hidden[i] {
+			continue
+		}
+
+		for _, group := range user.Flyable {
+			if group == nil || group.Group == nil {
+				continue
+			}
+
+			for _, ship := range group.Ships {
+				if ship == nil || ship.Type == nil || !ship.Flyable {
+					continue
+				}
+
+				g, ok := groups[group.ID]
+				if !ok {
+					g = &skillz.ShipGroupComparison{
+						GroupID: group.ID,
+						Name:    group.Name,
+					}
+					groups[group.ID] = g
+				}
+
+				c, ok := ships[ship.ID]
+				if !ok {
+					c = &skillz.ShipComparison{
+						ShipID:  ship.ID,
+						Name:    ship.Name,
+						Flyable: make([]bool, len(users)),
+					}
+					ships[ship.ID] = c
+					g.Ships = append(g.Ships, c)
+				}
+
+				c.Flyable[i] = true
+			}
+		}
+	}
+
+	results := make([]*skillz.ShipGroupComparison, 0, len(groups))
+	for _, group := range groups {
+		for _, ship := range group.Ships {
+			ship.Unique = isUnique(len(users), hidden, func(i int) bool { return ship.Flyable[i] })
+		}
+
+		sort.Slice(group.Ships, func(i, j int) bool {
+			return group.Ships[i].Name < group.Ships[j].Name
+		})
+
+		results = append(results, group)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Name < results[j].Name
+	})
+
+	return results
+
+}
+
+// isUnique reports whether has is true for some, but not all, of the users that have not hidden the section
+func isUnique(n int, hidden []bool, has func(i int) bool) bool {
+
+	var with, without int
+	for i := 0; i < n; i++ {
+		if hidden[i] {
+			continue
+		}
+
+		if has(i) {
+			with++
+		} else {
+			without++
+		}
+	}
+
+	return with > 0 && without > 0
+
+}
@@ -0,0 +1,84 @@
+package user_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/eveisesi/skillz"
+	"github.com/eveisesi/skillz/internal/esitest"
+	user "github.com/eveisesi/skillz/internal/user/v2"
+	"github.com/pkg/errors"
+)
+
+func (h *harness) setVisibility(t *testing.T, u *skillz.User, visibility skillz.Visibility) {
+	t.Helper()
+
+	err := h.service.CreateUserSettings(context.Background(), u.ID, &skillz.UserSettings{Visibility: visibility})
+	if err != nil {
+		t.Fatalf("failed to update settings of %s: %s", u.ID, err)
+	}
+}
+
+func TestCompareUsersVisibility(t *testing.T) {
+
+	var ctx = context.Background()
+
+	h := newHarness(t)
+	h.addCharacter(90000002, "Private Alt")
+	h.addCharacter(90000003, "Public Pilot")
+	h.addCharacter(90000004, "Private Stranger")
+
+	main := h.processedLogin(t, esitest.CharacterID)
+	alt := h.processedLogin(t, 90000002)
+	public := h.processedLogin(t, 90000003)
+	stranger := h.processedLogin(t, 90000004)
+
+	h.setVisibility(t, main, skillz.VisibilityPrivate)
+	h.setVisibility(t, alt, skillz.VisibilityPrivate)
+	h.setVisibility(t, public, skillz.VisibilityPublic)
+	h.setVisibility(t, stranger, skillz.VisibilityToken)
+
+	err := h.service.LinkUser(ctx, main, alt)
+	if err != nil {
+		t.Fatalf("failed to link users: %s", err)
+	}
+
+	tests := []struct {
+		name   string
+		viewer *skillz.User
+		users  []*skillz.User
+		err    error
+	}{
+		{name: "private user without a viewer", users: []*skillz.User{public, main}, err: user.ErrUserNotFound},
+		{name: "own private user", viewer: main, users: []*skillz.User{main, public}},
+		{name: "private alt linked to the viewer", viewer: main, users: []*skillz.User{main, alt}},
+		{name: "private alt viewed by the alt", viewer: alt, users: []*skillz.User{main, alt, public}},
+		{name: "private alt of another player", viewer: public, users: []*skillz.User{public, alt}, err: user.ErrUserNotFound},
+		{name: "token user without the token", viewer: main, users: []*skillz.User{main, stranger}, err: user.ErrUserNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ids := make([]string, 0, len(test.users))
+			for _, u := range test.users {
+				ids = append(ids, u.ID)
+			}
+
+			comparison, err := h.service.CompareUsers(ctx, test.viewer, ids)
+			if test.err != nil {
+				if !errors.Is(err, test.err) {
+					t.Fatalf("got error %v, want %v", err, test.err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if len(comparison.Users) != len(test.users) {
+				t.Errorf("got %d users, want %d", len(comparison.Users), len(test.users))
+			}
+		})
+	}
+
+}
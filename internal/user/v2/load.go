@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/eveisesi/skillz"
 	"github.com/sirupsen/logrus"
@@ -66,6 +67,21 @@ func (s *Service) LoadAttributes(ctx context.Context, user *skillz.User, entry *
 	user.Attributes = attributes
 }
 
+func (s *Service) LoadEffectiveAttributes(ctx context.Context, user *skillz.User, entry *logrus.Entry, mx *sync.Mutex, wg *sync.WaitGroup) {
+	defer wg.Done()
+	attributes, err := s.skills.EffectiveAttributes(ctx, user.CharacterID)
+	if err != nil {
+		entry.WithError(err).
+			Error("failed to fetch character effective attributes")
+		mx.Lock()
+		defer mx.Unlock()
+		user.Errors = append(user.Errors, fmt.Errorf("failed to fetch character effective attributes"))
+		return
+	}
+
+	user.EffectiveAttributes = attributes
+}
+
 func (s *Service) LoadImplants(ctx context.Context, user *skillz.User, entry *logrus.Entry, mx *sync.Mutex, wg *sync.WaitGroup) {
 	defer wg.Done()
 	implants, err := s.clones.Implants(ctx, user.CharacterID)
@@ -81,6 +97,36 @@ func (s *Service) LoadImplants(ctx context.Context, user *skillz.User, entry *lo
 	user.Implants = implants
 }
 
+func (s *Service) LoadJumpClones(ctx context.Context, user *skillz.User, entry *logrus.Entry, mx *sync.Mutex, wg *sync.WaitGroup) {
+	defer wg.Done()
+	clones, err := s.skills.JumpClones(ctx, user.CharacterID)
+	if err != nil {
+		entry.WithError(err).
+			Error("failed to fetch character jump clones")
+		mx.Lock()
+		defer mx.Unlock()
+		user.Errors = append(user.Errors, fmt.Errorf("failed to fetch character jump clones"))
+		return
+	}
+
+	user.JumpClones = clones
+}
+
+func (s *Service) LoadContacts(ctx context.Context, user *skillz.User, entry *logrus.Entry, mx *sync.Mutex, wg *sync.WaitGroup) {
+	defer wg.Done()
+	contacts, err := s.contacts.Contacts(ctx, user.CharacterID)
+	if err != nil {
+		entry.WithError(err).
+			Error("failed to fetch character contacts")
+		mx.Lock()
+		defer mx.Unlock()
+		user.Errors = append(user.Errors, fmt.Errorf("failed to fetch character contacts"))
+		return
+	}
+
+	user.Contacts = contacts
+}
+
 func (s *Service) LoadSkills(ctx context.Context, user *skillz.User, entry *logrus.Entry, mx *sync.Mutex, wg *sync.WaitGroup) {
 	defer wg.Done()
 	skills, err := s.skills.Skillz(ctx, user.CharacterID)
@@ -154,3 +200,20 @@ func (s *Service) LoadSkillMeta(ctx context.Context, user *skillz.User, entry *l
 
 	user.Meta = meta
 }
+
+const timelinePeriod = time.Hour * 24 * 30
+
+func (s *Service) LoadSkillTimeline(ctx context.Context, user *skillz.User, entry *logrus.Entry, mx *sync.Mutex, wg *sync.WaitGroup) {
+	defer wg.Done()
+	now := time.Now()
+	timeline, err := s.skills.SkillTimeline(ctx, user.CharacterID, now.Add(-timelinePeriod), now)
+	if err != nil {
+		entry.WithError(err).Error("failed to fetch character skill timeline")
+		mx.Lock()
+		defer mx.Unlock()
+		user.Errors = append(user.Errors, fmt.Errorf("failed to fetch character skill timeline"))
+		return
+	}
+
+	user.Timeline = timeline
+}
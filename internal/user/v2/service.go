@@ -18,6 +18,7 @@ import (
 	"github.com/eveisesi/skillz/internal/cache"
 	"github.com/eveisesi/skillz/internal/character"
 	"github.com/eveisesi/skillz/internal/clone"
+	"github.com/eveisesi/skillz/internal/contact"
 	"github.com/eveisesi/skillz/internal/corporation"
 	"github.com/eveisesi/skillz/internal/skill"
 	"github.com/go-redis/redis/v8"
@@ -47,6 +48,18 @@ type API interface {
 
 	UserSettings(ctx context.Context, id string) (*skillz.UserSettings, error)
 	CreateUserSettings(ctx context.Context, userID string, settings *skillz.UserSettings) error
+
+	LinkedUsers(ctx context.Context, user *skillz.User) ([]*skillz.User, error)
+	LinkUser(ctx context.Context, user, linked *skillz.User) error
+	UnlinkUser(ctx context.Context, user *skillz.User, linkedID string) error
+
+	SharedUser(ctx context.Context, token string) (*skillz.User, *skillz.UserShareLink, error)
+	ShareLinks(ctx context.Context, user *skillz.User) ([]*skillz.UserShareLink, error)
+	CreateShareLink(ctx context.Context, user *skillz.User, link *skillz.UserShareLink, duration time.Duration) error
+	RevokeShareLink(ctx context.Context, user *skillz.User, token string) error
+	RecordShareLinkView(ctx context.Context, link *skillz.UserShareLink, view *skillz.UserShareLinkView) error
+
+	CompareUsers(ctx context.Context, viewer *skillz.User, ids []string) (*skillz.UserComparison, error)
 }
 
 type Service struct {
@@ -59,8 +72,9 @@ type Service struct {
 	corporation corporation.API
 	alliance    alliance.API
 
-	clones clone.API
-	skills skill.API
+	clones   clone.API
+	contacts contact.API
+	skills   skill.API
 
 	skillz.UserRepository
 }
@@ -77,6 +91,7 @@ func New(
 	corporation corporation.API,
 	skills skill.API,
 	clones clone.API,
+	contacts contact.API,
 	user skillz.UserRepository,
 ) *Service {
 	return &Service{
@@ -89,6 +104,7 @@ func New(
 		corporation:    corporation,
 		skills:         skills,
 		clones:         clones,
+		contacts:       contacts,
 		UserRepository: user,
 	}
 }
@@ -99,6 +115,9 @@ var allRels = []UserRel{
 	UserCharacterRel, UserAttributesRel,
 	UserSkillsRel, UserFlyableRel,
 	UserSkillQueueRel, UserSkillMetaRel,
+	UserEffectiveAttributesRel, UserSkillTimelineRel,
+	UserSkillListRel, UserImplantsRel,
+	UserContactsRel, UserJumpClonesRel,
 }
 
 func (r UserRel) Valid() bool {
@@ -118,6 +137,12 @@ const (
 	UserFlyableRel
 	UserSkillQueueRel
 	UserSkillMetaRel
+	UserEffectiveAttributesRel
+	UserSkillTimelineRel
+	UserSkillListRel
+	UserImplantsRel
+	UserContactsRel
+	UserJumpClonesRel
 )
 
 func (s *Service) User(ctx context.Context, id string, rels ...UserRel) (*skillz.User, error) {
@@ -165,6 +190,18 @@ func (s *Service) User(ctx context.Context, id string, rels ...UserRel) (*skillz
 			go s.LoadSkillQueue(ctx, user, entry, mx, wg)
 		case UserSkillMetaRel:
 			go s.LoadSkillMeta(ctx, user, entry, mx, wg)
+		case UserEffectiveAttributesRel:
+			go s.LoadEffectiveAttributes(ctx, user, entry, mx, wg)
+		case UserSkillTimelineRel:
+			go s.LoadSkillTimeline(ctx, user, entry, mx, wg)
+		case UserSkillListRel:
+			go s.LoadSkills(ctx, user, entry, mx, wg)
+		case UserImplantsRel:
+			go s.LoadImplants(ctx, user, entry, mx, wg)
+		case UserContactsRel:
+			go s.LoadContacts(ctx, user, entry, mx, wg)
+		case UserJumpClonesRel:
+			go s.LoadJumpClones(ctx, user, entry, mx, wg)
 		}
 	}
 
@@ -233,9 +270,6 @@ var ErrUserNotFound = errors.New("user does not exist")
 
 func (s *Service) LoadUserAll(ctx context.Context, id string) (*skillz.User, error) {
 
-	var mx = new(sync.Mutex)
-	var wg = new(sync.WaitGroup)
-
 	user, err := s.UserRepository.User(ctx, id)
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		return nil, errors.Wrap(err, "unexpected error encountered fetch user")
@@ -250,6 +284,18 @@ func (s *Service) LoadUserAll(ctx context.Context, id string) (*skillz.User, err
 		return nil, errors.Wrap(err, "unexpected error encountered fetch user")
 	}
 
+	s.loadUserSections(ctx, user)
+
+	return user, nil
+
+}
+
+// loadUserSections loads the sections of the user's skillboard that are not hidden by the user's settings
+func (s *Service) loadUserSections(ctx context.Context, user *skillz.User) {
+
+	var mx = new(sync.Mutex)
+	var wg = new(sync.WaitGroup)
+
 	entry := s.logger.
 		WithField("userID", user.ID)
 
@@ -262,6 +308,9 @@ func (s *Service) LoadUserAll(ctx context.Context, id string) (*skillz.User, err
 	if !user.Settings.HideSkills {
 		wg.Add(1)
 		go s.LoadSkillGrouped(ctx, user, entry, mx, wg)
+
+		wg.Add(1)
+		go s.LoadSkillTimeline(ctx, user, entry, mx, wg)
 	}
 
 	if !user.Settings.HideFlyable {
@@ -285,9 +334,24 @@ func (s *Service) LoadUserAll(ctx context.Context, id string) (*skillz.User, err
 
 	}
 
-	wg.Wait()
+	if !PermissionHideClones.Hidden(user.Settings) {
+		wg.Add(1)
+		go s.LoadJumpClones(ctx, user, entry, mx, wg)
+	}
 
-	return user, nil
+	if !PermissionHideStandings.Hidden(user.Settings) {
+		wg.Add(1)
+		go s.LoadContacts(ctx, user, entry, mx, wg)
+	}
+
+	// Effective attributes reveal the bonuses of the character's implants,
+	// so they are only loaded when both are visible
+	if !user.Settings.HideAttributes && !user.Settings.HideImplants {
+		wg.Add(1)
+		go s.LoadEffectiveAttributes(ctx, user, entry, mx, wg)
+	}
+
+	wg.Wait()
 
 }
 
@@ -381,6 +445,15 @@ func (s *Service) Login(ctx context.Context, code, state string) (*skillz.User,
 		return nil, errors.New("invalid type for scp claim in token.")
 	}
 
+	// A new owner hash means the character has been transferred to another
+	// player, so it can no longer be linked to the previous owner's characters
+	if !user.IsNew && user.OwnerHash != ownerHash {
+		err = s.unlinkTransferredUser(ctx, user)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	user.OwnerHash = ownerHash
 	user.Scopes = scp
 	user.AccessToken = bearer.AccessToken
@@ -551,6 +624,28 @@ const (
 	PermissionHideShips
 )
 
+// Hidden reports whether the user has chosen to hide the data covered by the permission
+func (p Permission) Hidden(settings *skillz.UserSettings) bool {
+
+	if settings == nil {
+		return true
+	}
+
+	switch p {
+	case PermissionHideQueue:
+		return settings.HideQueue
+	case PermissionHideClones:
+		return settings.HideImplants
+	case PermissionHideStandings:
+		return settings.HideStandings
+	case PermissionHideShips:
+		return settings.HideFlyable
+	}
+
+	return true
+
+}
+
 func (s *Service) UserSettings(ctx context.Context, id string) (*skillz.UserSettings, error) {
 
 	settings, err := s.cache.UserSettings(ctx, id)
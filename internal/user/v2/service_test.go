@@ -0,0 +1,149 @@
+package user_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/eveisesi/skillz"
+	"github.com/eveisesi/skillz/internal"
+	"github.com/eveisesi/skillz/internal/alliance"
+	"github.com/eveisesi/skillz/internal/auth"
+	"github.com/eveisesi/skillz/internal/character"
+	"github.com/eveisesi/skillz/internal/clone"
+	"github.com/eveisesi/skillz/internal/contact"
+	"github.com/eveisesi/skillz/internal/corporation"
+	"github.com/eveisesi/skillz/internal/esitest"
+	"github.com/eveisesi/skillz/internal/memory"
+	"github.com/eveisesi/skillz/internal/skill"
+	"github.com/eveisesi/skillz/internal/universe"
+	user "github.com/eveisesi/skillz/internal/user/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+type harness struct {
+	server  *esitest.Server
+	redis   *redis.Client
+	auth    *auth.Service
+	service *user.Service
+	users   skillz.UserRepository
+}
+
+// newHarness wires the user service against the fake ESI and SSO and the in-memory repositories
+func newHarness(t *testing.T) *harness {
+	t.Helper()
+
+	env := esitest.NewHarness(t)
+
+	userRepo := memory.NewUserRepository(env.DB)
+
+	character := character.New(env.Logger, env.Cache, env.ESI, env.ETag, memory.NewCharacterRepository(env.DB))
+	corporation := corporation.New(env.Logger, env.Cache, env.ESI, env.ETag, memory.NewCorporationRepository(env.DB))
+	alliance := alliance.New(env.Logger, env.Cache, env.ESI, env.ETag, memory.NewAllianceRepository(env.DB))
+
+	auth := auth.New(skillz.Production, env.Server.Client(), env.Cache, env.Server.OAuth2Config(env.Server.URL+"/callback"), env.Server.BaseURI())
+	universe := universe.New(env.Logger, env.Cache, env.ESI, memory.NewUniverseRepository(env.DB))
+	clone := clone.New(env.Logger, env.Cache, env.ETag, env.ESI, universe, memory.NewCloneRepository(env.DB))
+	contact := contact.New(env.Logger, env.Cache, env.ETag, env.ESI, character, corporation, alliance, memory.NewContactRepository(env.DB))
+	skills := skill.New(env.Logger, env.Cache, env.ESI, universe, clone, memory.NewSkillRepository(env.DB))
+
+	return &harness{
+		server:  env.Server,
+		redis:   env.Redis,
+		auth:    auth,
+		service: user.New(env.Redis, env.Logger, env.Cache, auth, alliance, character, corporation, skills, clone, contact, userRepo),
+		users:   userRepo,
+	}
+
+}
+
+// login runs the character through the SSO with the scopes, the way the callback handler does
+func (h *harness) login(t *testing.T, characterID uint64, scopes ...skillz.Scope) (*skillz.User, error) {
+	t.Helper()
+
+	var ctx = context.Background()
+
+	attempt, err := h.auth.InitializeAttempt(ctx)
+	if err != nil {
+		t.Fatalf("failed to initialize auth attempt: %s", err)
+	}
+
+	return h.service.Login(ctx, h.server.Code(characterID, scopes...), attempt.State)
+}
+
+func TestLogin(t *testing.T) {
+
+	var ctx = context.Background()
+
+	h := newHarness(t)
+
+	u, err := h.login(t, esitest.CharacterID, skillz.ReadSkillsV1, skillz.ReadSkillQueueV1)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !u.IsNew || u.CharacterID != esitest.CharacterID || u.OwnerHash != esitest.OwnerHash {
+		t.Errorf("expected a new user for the fixture character, got %+v", u)
+	}
+	if len(u.Scopes) != 2 || u.AccessToken == "" || u.RefreshToken == "" {
+		t.Errorf("expected the user to carry the token and both scopes, got %d scopes", len(u.Scopes))
+	}
+
+	stored, err := h.service.User(ctx, u.ID, user.UserCharacterRel)
+	if err != nil {
+		t.Fatalf("failed to fetch user: %s", err)
+	}
+	if stored.Settings == nil || stored.Settings.Visibility != skillz.VisibilityPrivate {
+		t.Error("expected new users to be private")
+	}
+	if stored.Character == nil || stored.Character.Name != esitest.CharacterName {
+		t.Error("expected the user's character to be fetched from esi")
+	}
+
+	queued, err := h.redis.ZScore(ctx, internal.UpdateQueue, u.ID).Result()
+	if err != nil || queued == 0 {
+		t.Errorf("expected the user to be queued for processing, got %v", err)
+	}
+
+	again, err := h.login(t, esitest.CharacterID, skillz.ReadSkillsV1)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if again.ID != u.ID || len(again.Scopes) != 1 {
+		t.Errorf("expected logging in again to update the existing user's scopes")
+	}
+
+}
+
+func TestLoginInvalidState(t *testing.T) {
+
+	h := newHarness(t)
+
+	_, err := h.service.Login(context.Background(), h.server.Code(esitest.CharacterID), "unknown-state")
+	if err == nil {
+		t.Fatal("expected an error for a state that was never issued, got nil")
+	}
+
+}
+
+func TestLoginInvalidCode(t *testing.T) {
+
+	var ctx = context.Background()
+
+	h := newHarness(t)
+
+	attempt, err := h.auth.InitializeAttempt(ctx)
+	if err != nil {
+		t.Fatalf("failed to initialize auth attempt: %s", err)
+	}
+
+	_, err = h.service.Login(ctx, "unknown-code", attempt.State)
+	if err == nil {
+		t.Fatal("expected an error for a code the sso did not issue, got nil")
+	}
+
+	_, err = h.users.UserByCharacterID(ctx, esitest.CharacterID)
+	if err == nil {
+		t.Error("expected no user to be created when the code is rejected")
+	}
+
+}
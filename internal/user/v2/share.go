@@ -0,0 +1,155 @@
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/eveisesi/skillz"
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrShareLinkNotFound = errors.New("share link does not exist or has expired")
+	ErrInvalidShareLink  = errors.New("share link is invalid")
+)
+
+// MaxShareLinkDuration is the longest that a share link is allowed to remain valid for
+const MaxShareLinkDuration = time.Hour * 24 * 30
+
+// SharedUser returns the user that the share link was created for, loaded with only the sections
+// that the link shares. ErrShareLinkNotFound is returned for unknown, revoked and expired links
+func (s *Service) SharedUser(ctx context.Context, token string) (*skillz.User, *skillz.UserShareLink, error) {
+
+	link, err := s.UserRepository.UserShareLink(ctx, token)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, errors.Wrap(err, "failed to fetch share link")
+	}
+
+	if errors.Is(err, sql.ErrNoRows) || link.Expired() {
+		return nil, nil, ErrShareLinkNotFound
+	}
+
+	user, err := s.UserRepository.User(ctx, link.UserID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, errors.Wrap(err, "unexpected error encountered fetch user")
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, ErrShareLinkNotFound
+	}
+
+	settings, err := s.UserSettings(ctx, user.ID)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "unexpected error encountered fetch user")
+	}
+
+	// The link takes the place of the user's settings so that only the shared sections are
+	// loaded. Attributes are shared with skills since they are needed to read training times
+	user.Settings = &skillz.UserSettings{
+		UserID:         user.ID,
+		Visibility:     skillz.VisibilityPrivate,
+		HideSkills:     !link.ShareSkills,
+		HideQueue:      !link.ShareQueue,
+		HideAttributes: !link.ShareSkills && !link.ShareQueue,
+		HideFlyable:    !link.ShareFlyable,
+		HideImplants:   !link.ShareImplants,
+		HideStandings:  true,
+	}
+	if settings != nil {
+		user.Settings.VisibilityToken = settings.VisibilityToken
+	}
+
+	s.loadUserSections(ctx, user)
+
+	return user, link, nil
+
+}
+
+// ShareLinks returns the user's share links, including expired ones, along with the views of each link
+func (s *Service) ShareLinks(ctx context.Context, user *skillz.User) ([]*skillz.UserShareLink, error) {
+
+	links, err := s.UserRepository.UserShareLinks(ctx, user.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.Wrap(err, "failed to fetch share links")
+	}
+
+	viewers := make(map[string]*skillz.User)
+	for _, link := range links {
+		link.Views, err = s.UserRepository.UserShareLinkViews(ctx, link.Token)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.Wrap(err, "failed to fetch share link views")
+		}
+
+		for _, view := range link.Views {
+			if !view.ViewerID.Valid {
+				continue
+			}
+
+			viewer, ok := viewers[view.ViewerID.String]
+			if !ok {
+				viewer, err = s.User(ctx, view.ViewerID.String, UserCharacterRel)
+				if err != nil && !errors.Is(err, ErrUserNotFound) {
+					return nil, err
+				}
+
+				viewers[view.ViewerID.String] = viewer
+			}
+
+			view.Viewer = viewer
+		}
+	}
+
+	return links, nil
+
+}
+
+// CreateShareLink creates a share link for the user that is valid for the provided duration
+func (s *Service) CreateShareLink(ctx context.Context, user *skillz.User, link *skillz.UserShareLink, duration time.Duration) error {
+
+	link.Label = strings.TrimSpace(link.Label)
+	if link.Label == "" {
+		return errors.Wrap(ErrInvalidShareLink, "a label is required")
+	}
+
+	if !link.ShareSkills && !link.ShareQueue && !link.ShareImplants && !link.ShareFlyable {
+		return errors.Wrap(ErrInvalidShareLink, "at least one section must be shared")
+	}
+
+	if duration <= 0 || duration > MaxShareLinkDuration {
+		return errors.Wrap(ErrInvalidShareLink, "expiry is out of range")
+	}
+
+	b := make([]byte, 20)
+	_, err := rand.Read(b)
+	if err != nil {
+		return errors.Wrap(err, "failed to generate share link token")
+	}
+
+	link.Token = hex.EncodeToString(b)
+	link.UserID = user.ID
+	link.ExpiresAt = time.Now().Add(duration)
+
+	err = s.UserRepository.CreateUserShareLink(ctx, link)
+	return errors.Wrap(err, "failed to create share link")
+
+}
+
+// RevokeShareLink deletes the user's share link. The views of the link are deleted along with it
+func (s *Service) RevokeShareLink(ctx context.Context, user *skillz.User, token string) error {
+
+	err := s.UserRepository.DeleteUserShareLink(ctx, user.ID, token)
+	return errors.Wrap(err, "failed to revoke share link")
+
+}
+
+func (s *Service) RecordShareLinkView(ctx context.Context, link *skillz.UserShareLink, view *skillz.UserShareLinkView) error {
+
+	view.Token = link.Token
+	err := s.UserRepository.CreateUserShareLinkView(ctx, view)
+	return errors.Wrap(err, "failed to record share link view")
+
+}
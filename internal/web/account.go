@@ -0,0 +1,133 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/eveisesi/skillz"
+	"github.com/eveisesi/skillz/internal/user/v2"
+	"github.com/gobuffalo/buffalo"
+	"github.com/gobuffalo/buffalo/render"
+	"github.com/pkg/errors"
+)
+
+// linkedCharactersExpiry is how long the characters linked to the authenticated user's account are kept
+// in the session before they are loaded again, which picks up links made from another session
+const linkedCharactersExpiry = time.Minute * 5
+
+// linkedCharacter is what the session keeps of a character linked to the authenticated user's
+// account, which is enough to render the character switcher
+type linkedCharacter struct {
+	ID          string `json:"id"`
+	CharacterID uint64 `json:"character_id"`
+	Name        string `json:"name"`
+}
+
+type linkedCharactersSession struct {
+	UserID     string             `json:"user_id"`
+	Expires    time.Time          `json:"expires"`
+	Characters []*linkedCharacter `json:"characters"`
+}
+
+// linkedCharacters returns the characters linked to the user's account from the session, loading and
+// storing them in the session when they are missing, have expired or were stored for another user
+func (s *Service) linkedCharacters(c buffalo.Context, u *skillz.User) []*linkedCharacter {
+
+	if data, ok := c.Session().Get(keyLinkedCharacters).(string); ok {
+		var cached = new(linkedCharactersSession)
+		err := json.Unmarshal([]byte(data), cached)
+		if err == nil && cached.UserID == u.ID && time.Now().Before(cached.Expires) {
+			return cached.Characters
+		}
+	}
+
+	users, err := s.user.LinkedUsers(c.Request().Context(), u)
+	if err != nil {
+		s.logger.WithError(err).WithField("userID", u.ID).Error("failed to fetch linked users")
+		users = []*skillz.User{u}
+	}
+
+	characters := make([]*linkedCharacter, 0, len(users))
+	for _, linked := range users {
+		character := &linkedCharacter{ID: linked.ID, CharacterID: linked.CharacterID}
+		if linked.Character != nil {
+			character.Name = linked.Character.Name
+		}
+
+		characters = append(characters, character)
+	}
+
+	if err != nil {
+		return characters
+	}
+
+	data, err := json.Marshal(linkedCharactersSession{UserID: u.ID, Expires: time.Now().Add(linkedCharactersExpiry), Characters: characters})
+	if err != nil {
+		s.logger.WithError(err).WithField("userID", u.ID).Error("failed to encode linked characters")
+		return characters
+	}
+
+	c.Session().Set(keyLinkedCharacters, string(data))
+
+	return characters
+
+}
+
+// switchUserHandler switches the session over to another character linked to the authenticated user's account
+func (s *Service) switchUserHandler(c buffalo.Context) error {
+
+	var ctx = c.Request().Context()
+
+	authenticatedUser, ok := c.Data()[keyAuthenticatedUser].(*skillz.User)
+	if !ok {
+		return c.Redirect(http.StatusFound, "rootPath()")
+	}
+
+	linked, err := s.user.LinkedUsers(ctx, authenticatedUser)
+	if err != nil {
+		return c.Error(http.StatusInternalServerError, err)
+	}
+
+	userID := c.Param("userID")
+	for _, u := range linked {
+		if u.ID != userID {
+			continue
+		}
+
+		c.Session().Set(keyAuthenticatedUserID, u.ID)
+		c.Session().Delete(keyLinkedCharacters)
+		return c.Redirect(http.StatusFound, "userPath()", render.Data{"userID": u.ID})
+	}
+
+	s.flashDanger(c, "That character is not linked to your account")
+	return c.Redirect(http.StatusFound, "usersSettingsPath()")
+
+}
+
+func (s *Service) unlinkUserHandler(c buffalo.Context) error {
+
+	var ctx = c.Request().Context()
+
+	authenticatedUser := c.Data()[keyAuthenticatedUser].(*skillz.User)
+	if authenticatedUser == nil {
+		return c.Redirect(http.StatusFound, "rootPath()")
+	}
+
+	err := s.user.UnlinkUser(ctx, authenticatedUser, c.Param("userID"))
+	c.Session().Delete(keyLinkedCharacters)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotLinked) {
+			s.flashDanger(c, "That character is not linked to your account")
+			return c.Redirect(http.StatusFound, "usersSettingsPath()")
+		}
+
+		s.logger.WithError(err).WithField("userID", authenticatedUser.ID).Error("failed to unlink user")
+		s.flashDanger(c, "Failed to unlink character. Please try again")
+		return c.Redirect(http.StatusFound, "usersSettingsPath()")
+	}
+
+	s.flashSuccess(c, "Character has been unlinked from your account successfully")
+	return c.Redirect(http.StatusFound, "usersSettingsPath()")
+
+}
@@ -5,7 +5,10 @@ import (
 
 	"github.com/eveisesi/skillz"
 	"github.com/eveisesi/skillz/internal/auth"
+	"github.com/eveisesi/skillz/internal/doctrine"
+	"github.com/eveisesi/skillz/internal/fitting"
 	"github.com/eveisesi/skillz/internal/processor"
+	"github.com/eveisesi/skillz/internal/skill"
 	"github.com/eveisesi/skillz/internal/user/v2"
 	"github.com/eveisesi/skillz/public"
 	"github.com/gobuffalo/buffalo"
@@ -22,6 +25,9 @@ type Service struct {
 	app        *buffalo.App
 	auth       auth.API
 	user       user.API
+	skills     skill.API
+	fittings   fitting.API
+	doctrines  doctrine.API
 	processor  *processor.Service
 	logger     *logrus.Logger
 	renderer   *render.Engine
@@ -30,6 +36,9 @@ type Service struct {
 
 const keyAuthenticatedUser = "authenticatedUser"
 const keyAuthenticatedUserID = "authenticatedUserID"
+const keyLinkedUsers = "linkedUsers"
+const keyLinkedCharacters = "linkedCharacters"
+const keyLinkAccountUserID = "linkAccountUserID"
 
 const titleSuffix = "|| Eve Is ESI || A Third Party Eve Online App"
 
@@ -40,6 +49,9 @@ func NewService(
 
 	auth auth.API,
 	user user.API,
+	skills skill.API,
+	fittings fitting.API,
+	doctrines doctrine.API,
 	processor *processor.Service,
 
 	renderer *render.Engine,
@@ -56,6 +68,9 @@ func NewService(
 		baseDomain: baseDomain,
 		auth:       auth,
 		user:       user,
+		skills:     skills,
+		fittings:   fittings,
+		doctrines:  doctrines,
 		processor:  processor,
 		renderer:   renderer,
 		logger:     logger,
@@ -75,14 +90,43 @@ func NewService(
 
 	s.app.Use(s.setBaseDomain)
 	s.app.Use(s.setCurrentUser)
-	s.app.GET("/", s.indexHandler)
+	s.app.GET("/", csrf.New(s.indexHandler))
 	s.app.GET("/login", csrf.New(s.loginGetHandler))
 	s.app.POST("/login", csrf.New(s.loginPostHandler))
 	s.app.GET("/logout", s.logoutHandler)
 	s.app.GET("/users/settings", csrf.New(s.authorize(s.userSettingsHandler)))
 	s.app.POST("/users/settings", csrf.New(s.authorize(s.postUserSettingsHandler)))
 	s.app.DELETE("/users/settings", csrf.New(s.authorize(s.deleteUserSettingsHandler)))
-	s.app.GET("/users/{userID}", s.userHandler)
+	s.app.GET("/users/plans", csrf.New(s.authorize(s.skillPlansHandler)))
+	s.app.POST("/users/plans", csrf.New(s.authorize(s.postSkillPlansHandler)))
+	s.app.POST("/users/plans/import", csrf.New(s.authorize(s.importSkillPlanHandler)))
+	s.app.GET("/users/plans/{planID}", csrf.New(s.authorize(s.skillPlanHandler)))
+	s.app.POST("/users/plans/{planID}", csrf.New(s.authorize(s.postSkillPlanHandler)))
+	s.app.DELETE("/users/plans/{planID}", csrf.New(s.authorize(s.deleteSkillPlanHandler)))
+	s.app.POST("/users/plans/{planID}/entries", csrf.New(s.authorize(s.postSkillPlanEntryHandler)))
+	s.app.POST("/users/plans/{planID}/entries/{position}/move", csrf.New(s.authorize(s.moveSkillPlanEntryHandler)))
+	s.app.DELETE("/users/plans/{planID}/entries/{position}", csrf.New(s.authorize(s.deleteSkillPlanEntryHandler)))
+	s.app.POST("/users/accounts/{userID}/switch", csrf.New(s.authorize(s.switchUserHandler)))
+	s.app.DELETE("/users/accounts/{userID}", csrf.New(s.authorize(s.unlinkUserHandler)))
+	s.app.POST("/users/shares", csrf.New(s.authorize(s.postShareLinkHandler)))
+	s.app.DELETE("/users/shares/{token}", csrf.New(s.authorize(s.deleteShareLinkHandler)))
+	s.app.GET("/users/evemon", csrf.New(s.authorize(s.evemonCharacterHandler)))
+	s.app.GET("/users/doctrines", csrf.New(s.authorize(s.doctrinesHandler)))
+	s.app.POST("/users/doctrines", csrf.New(s.authorize(s.postDoctrinesHandler)))
+	s.app.GET("/users/doctrines/{doctrineID}/report.csv", s.authorize(s.doctrineReportCSVHandler))
+	s.app.GET("/users/doctrines/{doctrineID}", csrf.New(s.authorize(s.doctrineHandler)))
+	s.app.DELETE("/users/doctrines/{doctrineID}", csrf.New(s.authorize(s.deleteDoctrineHandler)))
+	s.app.POST("/users/doctrines/{doctrineID}/fits", csrf.New(s.authorize(s.postDoctrineFitHandler)))
+	s.app.DELETE("/users/doctrines/{doctrineID}/fits/{fitID}", csrf.New(s.authorize(s.deleteDoctrineFitHandler)))
+	s.app.GET("/users/fittings", csrf.New(s.authorize(s.fittingsHandler)))
+	s.app.POST("/users/fittings", csrf.New(s.authorize(s.postFittingsHandler)))
+	s.app.POST("/users/fittings/plan", csrf.New(s.authorize(s.postFittingPlanHandler)))
+	s.app.GET("/users/compare", csrf.New(s.compareHandler))
+	s.app.GET("/users/{userID}/feed.atom", s.userAtomFeedHandler)
+	s.app.GET("/users/{userID}/feed.rss", s.userRSSFeedHandler)
+	s.app.GET("/users/{userID}/queue.ics", s.userQueueCalendarHandler)
+	s.app.GET("/users/{userID}", csrf.New(s.userHandler))
+	s.app.GET("/share/{token}", csrf.New(s.sharedUserHandler))
 
 	s.app.ServeFiles("/", http.FS(public.FS())) // serve files from the public directory
 
@@ -6,8 +6,10 @@ import (
 
 	"github.com/eveisesi/skillz"
 	"github.com/eveisesi/skillz/internal/errors"
+	"github.com/eveisesi/skillz/internal/processor"
 	"github.com/gobuffalo/buffalo"
 	"github.com/gobuffalo/buffalo/render"
+	pkgerrors "github.com/pkg/errors"
 )
 
 func (s *Service) logoutHandler(c buffalo.Context) error {
@@ -33,20 +35,42 @@ func (s *Service) loginGetHandler(c buffalo.Context) error {
 
 		if user.IsNew {
 			s.logger.WithField("user", user.ID).Info("processing new user")
+			// A processor worker may have picked the user up from the update queue already
 			err = s.processor.ProcessUser(ctx, user)
-			if err != nil {
+			if err != nil && !pkgerrors.Is(err, processor.ErrUserLeased) {
 				s.logger.WithError(err).WithField("user", user.ID).Error("failed to process user")
 				s.flashDanger(c, "Failed to fetch user data. Please try again. If error persists, join us on Discord")
 				return c.Render(http.StatusOK, s.renderer.HTML("login/index.plush.html"))
 			}
 		}
 
+		// When linking, the session still belongs to the character that initiated the link
+		if linkUserID, ok := c.Session().Get(keyLinkAccountUserID).(string); ok {
+			c.Session().Delete(keyLinkAccountUserID)
+
+			current, ok := c.Data()[keyAuthenticatedUser].(*skillz.User)
+			if ok && current.ID == linkUserID {
+				err = s.user.LinkUser(ctx, current, user)
+				c.Session().Delete(keyLinkedCharacters)
+				if err != nil {
+					s.logger.WithError(err).WithField("user", user.ID).Error("failed to link user")
+					s.flashDanger(c, "Failed to link character to your account. Please try again")
+					return c.Redirect(http.StatusFound, "usersSettingsPath()")
+				}
+
+				s.flashSuccess(c, "Character has been linked to your account successfully")
+			}
+		}
+
 		c.Session().Set(keyAuthenticatedUserID, user.ID)
 
 		return c.Redirect(http.StatusFound, "userPath()", render.Data{"userID": user.ID})
 
 	}
 
+	_, authenticated := c.Data()[keyAuthenticatedUser].(*skillz.User)
+	c.Set("link", authenticated && c.Param("link") == "true")
+
 	return c.Render(http.StatusOK, s.renderer.HTML("login/index.plush.html"))
 }
 
@@ -61,6 +85,21 @@ func (s *Service) loginPostHandler(c buffalo.Context) error {
 		scopes = append(scopes, skillz.ReadImplantsV1.String())
 	}
 
+	// Jump clones stored in structures can only be located with the structures scope
+	if form.Has("allow_clones") {
+		scopes = append(scopes, skillz.ReadClonesV1.String(), skillz.ReadStructuresV1.String())
+	}
+
+	if form.Has("allow_contacts") {
+		scopes = append(scopes, skillz.ReadContactsV1.String())
+	}
+
+	if current, ok := c.Data()[keyAuthenticatedUser].(*skillz.User); ok && form.Get("link") == "true" {
+		c.Session().Set(keyLinkAccountUserID, current.ID)
+	} else {
+		c.Session().Delete(keyLinkAccountUserID)
+	}
+
 	attempt, err := s.auth.InitializeAttempt(ctx)
 	if err != nil {
 		return err
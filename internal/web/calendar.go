@@ -0,0 +1,114 @@
+package web
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gobuffalo/buffalo"
+	"github.com/gobuffalo/buffalo/render"
+	"github.com/pkg/errors"
+)
+
+const icsTimestampFormat = "20060102T150405Z"
+
+// userQueueCalendarHandler renders the user's skill queue as an iCalendar feed with an event for each
+// queue position. Token visible users can be subscribed to by providing the token query parameter
+func (s *Service) userQueueCalendarHandler(c buffalo.Context) error {
+	var ctx = c.Request().Context()
+
+	u, err := s.viewableUser(c)
+	if err != nil {
+		return err
+	}
+
+	if u.Settings != nil && u.Settings.HideQueue {
+		return c.Error(http.StatusNotFound, errors.New("user has hidden their skill queue"))
+	}
+
+	summary, err := s.skills.SkillQueue(ctx, u.CharacterID)
+	if err != nil {
+		return c.Error(http.StatusInternalServerError, err)
+	}
+
+	host := s.baseDomain
+	if parsed, err := url.Parse(s.baseDomain); err == nil && parsed.Host != "" {
+		host = parsed.Host
+	}
+
+	var b strings.Builder
+	now := time.Now().UTC().Format(icsTimestampFormat)
+
+	writeICSLine(&b, "BEGIN:VCALENDAR")
+	writeICSLine(&b, "VERSION:2.0")
+	writeICSLine(&b, fmt.Sprintf("PRODID:-//%s//Skill Queue//EN", host))
+	writeICSLine(&b, "CALSCALE:GREGORIAN")
+	writeICSLine(&b, "METHOD:PUBLISH")
+	writeICSLine(&b, fmt.Sprintf("X-WR-CALNAME:%s", escapeICSText(fmt.Sprintf("%s Skill Queue", u.Character.Name))))
+
+	for _, position := range summary.Queue {
+		if !position.StartDate.Valid || !position.FinishDate.Valid {
+			// The queue is paused, so there is nothing to put on a calendar
+			continue
+		}
+
+		name := fmt.Sprintf("Skill %d", position.SkillID)
+		if position.Type != nil {
+			name = position.Type.Name
+		}
+
+		writeICSLine(&b, "BEGIN:VEVENT")
+		// The UID is derived from the skill and level rather than the queue position so that
+		// reordering the queue updates the existing events instead of duplicating them
+		writeICSLine(&b, fmt.Sprintf("UID:%s-%d-%d@%s", u.ID, position.SkillID, position.FinishedLevel, host))
+		writeICSLine(&b, fmt.Sprintf("DTSTAMP:%s", now))
+		writeICSLine(&b, fmt.Sprintf("DTSTART:%s", position.StartDate.Time.UTC().Format(icsTimestampFormat)))
+		writeICSLine(&b, fmt.Sprintf("DTEND:%s", position.FinishDate.Time.UTC().Format(icsTimestampFormat)))
+		writeICSLine(&b, fmt.Sprintf("SUMMARY:%s", escapeICSText(fmt.Sprintf("%s %d", name, position.FinishedLevel))))
+		writeICSLine(&b, fmt.Sprintf("DESCRIPTION:%s", escapeICSText(fmt.Sprintf("%s finishes training %s to level %d", u.Character.Name, name, position.FinishedLevel))))
+		writeICSLine(&b, fmt.Sprintf("URL:%s", s.userLink(u)))
+		writeICSLine(&b, "TRANSP:TRANSPARENT")
+		writeICSLine(&b, "END:VEVENT")
+	}
+
+	writeICSLine(&b, "END:VCALENDAR")
+
+	return c.Render(http.StatusOK, render.Func("text/calendar; charset=utf-8", func(w io.Writer, d render.Data) error {
+		_, err := io.WriteString(w, b.String())
+		return err
+	}))
+
+}
+
+// writeICSLine writes a content line terminated by CRLF, folding it into
+// continuation lines so that no line is longer than 75 octets. Continuation
+// lines start with a space, which counts towards their 75 octets
+func writeICSLine(b *strings.Builder, line string) {
+
+	limit := 75
+	for len(line) > limit {
+		i := limit
+		// Do not split a multi-byte character across lines
+		for i > 0 && line[i]&0xC0 == 0x80 {
+			i--
+		}
+
+		b.WriteString(line[:i])
+		b.WriteString("\r\n ")
+		line = line[i:]
+		limit = 74
+	}
+
+	b.WriteString(line)
+	b.WriteString("\r\n")
+
+}
+
+var icsTextEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+
+func escapeICSText(text string) string {
+	return icsTextEscaper.Replace(text)
+}
@@ -0,0 +1,58 @@
+package web
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestWriteICSLine(t *testing.T) {
+
+	tests := []struct {
+		name string
+		line string
+	}{
+		{name: "74 octets", line: strings.Repeat("a", 74)},
+		{name: "75 octets", line: strings.Repeat("a", 75)},
+		{name: "76 octets", line: strings.Repeat("a", 76)},
+		{name: "150 octets", line: strings.Repeat("a", 150)},
+		{name: "rune straddling the first fold", line: strings.Repeat("a", 73) + "€" + strings.Repeat("b", 10)},
+		{name: "rune straddling a continuation fold", line: strings.Repeat("a", 75) + strings.Repeat("b", 73) + "€" + strings.Repeat("c", 10)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			var b strings.Builder
+			writeICSLine(&b, test.line)
+
+			out := b.String()
+			if !strings.HasSuffix(out, "\r\n") {
+				t.Fatalf("expected output to be terminated by CRLF, got %q", out)
+			}
+
+			lines := strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n")
+			for i, line := range lines {
+				if len(line) > 75 {
+					t.Errorf("line %d is %d octets, want at most 75", i, len(line))
+				}
+
+				if !utf8.ValidString(line) {
+					t.Errorf("line %d splits a multi-byte character: %q", i, line)
+				}
+
+				if i > 0 && !strings.HasPrefix(line, " ") {
+					t.Errorf("continuation line %d does not start with a space: %q", i, line)
+				}
+			}
+
+			// Unfolding removes every CRLF that is immediately followed by a single space
+			unfolded := strings.TrimSuffix(strings.ReplaceAll(out, "\r\n ", ""), "\r\n")
+			if unfolded != test.line {
+				t.Errorf("got unfolded line %q, want %q", unfolded, test.line)
+			}
+
+		})
+	}
+
+}
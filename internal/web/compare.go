@@ -0,0 +1,45 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/eveisesi/skillz"
+	"github.com/eveisesi/skillz/internal/user/v2"
+	"github.com/gobuffalo/buffalo"
+	"github.com/pkg/errors"
+)
+
+// compareHandler renders a side by side comparison of the users provided through the comma
+// separated ids parameter. Users the visitor is not allowed to view are reported as not found
+func (s *Service) compareHandler(c buffalo.Context) error {
+
+	var ctx = c.Request().Context()
+
+	viewer, _ := c.Data()[keyAuthenticatedUser].(*skillz.User)
+
+	ids := strings.Split(c.Param("ids"), ",")
+	for i, id := range ids {
+		ids[i] = strings.TrimSpace(id)
+	}
+
+	comparison, err := s.user.CompareUsers(ctx, viewer, ids)
+	if err != nil {
+		switch {
+		case errors.Is(err, user.ErrInvalidComparison):
+			s.flashDanger(c, fmt.Sprintf("Unable to compare characters, please select between 2 and %d characters to compare", user.MaxComparedUsers))
+		case errors.Is(err, user.ErrUserNotFound):
+			s.flashDanger(c, "User Not Found")
+		default:
+			return c.Error(http.StatusInternalServerError, err)
+		}
+
+		return c.Redirect(http.StatusFound, "rootPath()")
+	}
+
+	c.Set("title", fmt.Sprintf("Character Comparison %s", titleSuffix))
+	c.Set("comparison", comparison)
+	return c.Render(http.StatusOK, s.renderer.HTML("user/compare.plush.html"))
+
+}
@@ -0,0 +1,255 @@
+package web
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/eveisesi/skillz"
+	"github.com/eveisesi/skillz/internal/doctrine"
+	"github.com/eveisesi/skillz/internal/fitting"
+	"github.com/gertd/go-pluralize"
+	"github.com/gobuffalo/buffalo"
+	"github.com/gobuffalo/buffalo/render"
+	"github.com/pkg/errors"
+)
+
+type doctrineForm struct {
+	Name         string `form:"name"`
+	AllianceWide bool   `form:"alliance_wide"`
+}
+
+type doctrineFitForm struct {
+	Fitting string `form:"fitting"`
+}
+
+var doctrinesPageTitle = func(name string) (string, string) {
+	name = pluralize.NewClient().Plural(name)
+	return "title", fmt.Sprintf("%s Doctrines %s", name, titleSuffix)
+}
+
+func (s *Service) doctrinesHandler(c buffalo.Context) error {
+
+	var ctx = c.Request().Context()
+
+	user := c.Data()[keyAuthenticatedUser].(*skillz.User)
+	if user == nil {
+		return c.Redirect(http.StatusFound, "rootPath()")
+	}
+
+	c.Set(doctrinesPageTitle(user.Character.Name))
+
+	doctrines, err := s.doctrines.Doctrines(ctx, user)
+	if err != nil && !errors.Is(err, doctrine.ErrNotSharing) {
+		return c.Error(http.StatusInternalServerError, err)
+	}
+
+	c.Set("sharing", !errors.Is(err, doctrine.ErrNotSharing))
+	c.Set("doctrines", doctrines)
+	return c.Render(http.StatusOK, s.renderer.HTML("user/doctrines.plush.html"))
+
+}
+
+func (s *Service) postDoctrinesHandler(c buffalo.Context) error {
+
+	var ctx = c.Request().Context()
+
+	user := c.Data()[keyAuthenticatedUser].(*skillz.User)
+	if user == nil {
+		return c.Redirect(http.StatusFound, "rootPath()")
+	}
+
+	form := new(doctrineForm)
+	err := c.Bind(form)
+	if err != nil {
+		s.flashDanger(c, "failed to process form. Please try again")
+		return c.Redirect(http.StatusFound, "usersDoctrinesPath()")
+	}
+
+	d, err := s.doctrines.CreateDoctrine(ctx, user, form.Name, form.AllianceWide)
+	if err != nil {
+		return s.doctrineError(c, err, "usersDoctrinesPath()", nil)
+	}
+
+	s.flashSuccess(c, "Doctrine created successfully")
+	return c.Redirect(http.StatusFound, "usersDoctrinePath()", render.Data{"doctrineID": d.ID})
+
+}
+
+func (s *Service) doctrineHandler(c buffalo.Context) error {
+
+	var ctx = c.Request().Context()
+
+	user := c.Data()[keyAuthenticatedUser].(*skillz.User)
+	if user == nil {
+		return c.Redirect(http.StatusFound, "rootPath()")
+	}
+
+	doctrineID, err := strconv.ParseUint(c.Param("doctrineID"), 10, 32)
+	if err != nil {
+		return s.doctrineError(c, doctrine.ErrDoctrineNotFound, "usersDoctrinesPath()", nil)
+	}
+
+	report, err := s.doctrines.Report(ctx, user, uint(doctrineID))
+	if err != nil {
+		return s.doctrineError(c, err, "usersDoctrinesPath()", nil)
+	}
+
+	manageable, err := s.doctrines.CanManage(ctx, user, report.Doctrine)
+	if err != nil {
+		return c.Error(http.StatusInternalServerError, err)
+	}
+
+	c.Set(doctrinesPageTitle(user.Character.Name))
+	c.Set("report", report)
+	c.Set("doctrine", report.Doctrine)
+	c.Set("manageable", manageable)
+	return c.Render(http.StatusOK, s.renderer.HTML("user/doctrine.plush.html"))
+
+}
+
+// doctrineReportCSVHandler downloads the compliance report of the doctrine as CSV
+func (s *Service) doctrineReportCSVHandler(c buffalo.Context) error {
+
+	var ctx = c.Request().Context()
+
+	user := c.Data()[keyAuthenticatedUser].(*skillz.User)
+	if user == nil {
+		return c.Redirect(http.StatusFound, "rootPath()")
+	}
+
+	doctrineID, err := strconv.ParseUint(c.Param("doctrineID"), 10, 32)
+	if err != nil {
+		return s.doctrineError(c, doctrine.ErrDoctrineNotFound, "usersDoctrinesPath()", nil)
+	}
+
+	report, err := s.doctrines.Report(ctx, user, uint(doctrineID))
+	if err != nil {
+		return s.doctrineError(c, err, "usersDoctrinesPath()", nil)
+	}
+
+	var buf = new(bytes.Buffer)
+	err = doctrine.WriteReportCSV(buf, report)
+	if err != nil {
+		return c.Error(http.StatusInternalServerError, err)
+	}
+
+	filename := fmt.Sprintf("%s_%s.csv", strings.ReplaceAll(report.Doctrine.Name, " ", "_"), report.CreatedAt.UTC().Format("20060102"))
+	return c.Render(http.StatusOK, render.Download(ctx, filename, buf))
+
+}
+
+func (s *Service) deleteDoctrineHandler(c buffalo.Context) error {
+
+	var ctx = c.Request().Context()
+
+	user := c.Data()[keyAuthenticatedUser].(*skillz.User)
+	if user == nil {
+		return c.Redirect(http.StatusFound, "rootPath()")
+	}
+
+	doctrineID, err := strconv.ParseUint(c.Param("doctrineID"), 10, 32)
+	if err != nil {
+		return s.doctrineError(c, doctrine.ErrDoctrineNotFound, "usersDoctrinesPath()", nil)
+	}
+
+	data := render.Data{"doctrineID": doctrineID}
+
+	err = s.doctrines.DeleteDoctrine(ctx, user, uint(doctrineID))
+	if err != nil {
+		return s.doctrineError(c, err, "usersDoctrinePath()", data)
+	}
+
+	s.flashSuccess(c, "Doctrine deleted successfully")
+	return c.Redirect(http.StatusFound, "usersDoctrinesPath()")
+
+}
+
+func (s *Service) postDoctrineFitHandler(c buffalo.Context) error {
+
+	var ctx = c.Request().Context()
+
+	user := c.Data()[keyAuthenticatedUser].(*skillz.User)
+	if user == nil {
+		return c.Redirect(http.StatusFound, "rootPath()")
+	}
+
+	doctrineID, err := strconv.ParseUint(c.Param("doctrineID"), 10, 32)
+	if err != nil {
+		return s.doctrineError(c, doctrine.ErrDoctrineNotFound, "usersDoctrinesPath()", nil)
+	}
+
+	data := render.Data{"doctrineID": doctrineID}
+
+	form := new(doctrineFitForm)
+	err = c.Bind(form)
+	if err != nil {
+		s.flashDanger(c, "failed to process form. Please try again")
+		return c.Redirect(http.StatusFound, "usersDoctrinePath()", data)
+	}
+
+	_, err = s.doctrines.CreateDoctrineFit(ctx, user, uint(doctrineID), form.Fitting)
+	if err != nil {
+		return s.doctrineError(c, err, "usersDoctrinePath()", data)
+	}
+
+	s.flashSuccess(c, "Fit added to doctrine successfully")
+	return c.Redirect(http.StatusFound, "usersDoctrinePath()", data)
+
+}
+
+func (s *Service) deleteDoctrineFitHandler(c buffalo.Context) error {
+
+	var ctx = c.Request().Context()
+
+	user := c.Data()[keyAuthenticatedUser].(*skillz.User)
+	if user == nil {
+		return c.Redirect(http.StatusFound, "rootPath()")
+	}
+
+	doctrineID, err := strconv.ParseUint(c.Param("doctrineID"), 10, 32)
+	if err != nil {
+		return s.doctrineError(c, doctrine.ErrDoctrineNotFound, "usersDoctrinesPath()", nil)
+	}
+
+	data := render.Data{"doctrineID": doctrineID}
+
+	fitID, err := strconv.ParseUint(c.Param("fitID"), 10, 32)
+	if err != nil {
+		return c.Redirect(http.StatusFound, "usersDoctrinePath()", data)
+	}
+
+	err = s.doctrines.DeleteDoctrineFit(ctx, user, uint(doctrineID), uint(fitID))
+	if err != nil {
+		return s.doctrineError(c, err, "usersDoctrinePath()", data)
+	}
+
+	return c.Redirect(http.StatusFound, "usersDoctrinePath()", data)
+
+}
+
+// doctrineError flashes validation, permission and not found errors back to the user and
+// redirects them to the provided route. All other errors are treated as a 500
+func (s *Service) doctrineError(c buffalo.Context, err error, route string, data render.Data) error {
+
+	switch {
+	case errors.Is(err, doctrine.ErrDoctrineNotFound):
+		s.flashDanger(c, "Doctrine Not Found")
+		return c.Redirect(http.StatusFound, "usersDoctrinesPath()")
+	case errors.Is(err, doctrine.ErrNotSharing):
+		s.flashDanger(c, err.Error())
+		return c.Redirect(http.StatusFound, "usersDoctrinesPath()")
+	case errors.Is(err, doctrine.ErrInvalidDoctrine), errors.Is(err, doctrine.ErrNotPermitted),
+		errors.Is(err, fitting.ErrInvalidFitting):
+		s.flashDanger(c, err.Error())
+		if data == nil {
+			return c.Redirect(http.StatusFound, route)
+		}
+		return c.Redirect(http.StatusFound, route, data)
+	}
+
+	return c.Error(http.StatusInternalServerError, err)
+
+}
@@ -0,0 +1,100 @@
+package web
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/eveisesi/skillz"
+	"github.com/eveisesi/skillz/internal/evemon"
+	"github.com/eveisesi/skillz/internal/user/v2"
+	"github.com/gobuffalo/buffalo"
+	"github.com/gobuffalo/buffalo/render"
+	"github.com/pkg/errors"
+)
+
+// maxPlanUploadSize limits the size of an uploaded EVEMon plan
+const maxPlanUploadSize = 1 << 20
+
+// evemonCharacterHandler downloads the authenticated user's character in the EVEMon
+// character format so that it can be imported into EVEMon or pyfa
+func (s *Service) evemonCharacterHandler(c buffalo.Context) error {
+
+	var ctx = c.Request().Context()
+
+	authenticatedUser := c.Data()[keyAuthenticatedUser].(*skillz.User)
+	if authenticatedUser == nil {
+		return c.Redirect(http.StatusFound, "rootPath()")
+	}
+
+	u, err := s.user.User(ctx, authenticatedUser.ID, user.UserCharacterRel, user.UserAttributesRel, user.UserSkillListRel, user.UserImplantsRel)
+	if err != nil {
+		return c.Error(http.StatusInternalServerError, err)
+	}
+
+	if len(u.Errors) > 0 {
+		return c.Error(http.StatusInternalServerError, u.Errors[0])
+	}
+
+	data, err := evemon.ExportCharacter(u)
+	if err != nil {
+		return c.Error(http.StatusInternalServerError, err)
+	}
+
+	filename := fmt.Sprintf("%s.xml", strings.ReplaceAll(u.Character.Name, " ", "_"))
+	return c.Render(http.StatusOK, render.Download(ctx, filename, bytes.NewReader(data)))
+
+}
+
+// importSkillPlanHandler creates a skill plan from an uploaded EVEMon plan
+func (s *Service) importSkillPlanHandler(c buffalo.Context) error {
+
+	var ctx = c.Request().Context()
+
+	user := c.Data()[keyAuthenticatedUser].(*skillz.User)
+	if user == nil {
+		return c.Redirect(http.StatusFound, "rootPath()")
+	}
+
+	f, err := c.File("plan")
+	if err != nil || f.File == nil {
+		s.flashDanger(c, "failed to process form. Please try again")
+		return c.Redirect(http.StatusFound, "usersPlansPath()")
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(io.LimitReader(f, maxPlanUploadSize+1))
+	if err != nil {
+		return c.Error(http.StatusInternalServerError, err)
+	}
+
+	if len(data) > maxPlanUploadSize {
+		s.flashDanger(c, "plan is too large to be imported")
+		return c.Redirect(http.StatusFound, "usersPlansPath()")
+	}
+
+	name, entries, err := evemon.ParsePlan(data)
+	if err != nil {
+		if errors.Is(err, evemon.ErrInvalidPlan) {
+			s.flashDanger(c, err.Error())
+			return c.Redirect(http.StatusFound, "usersPlansPath()")
+		}
+		return c.Error(http.StatusInternalServerError, err)
+	}
+
+	if name == "" {
+		name = strings.TrimSuffix(f.Filename, filepath.Ext(f.Filename))
+	}
+
+	plan, err := s.skills.CreateSkillPlan(ctx, user, name, entries)
+	if err != nil {
+		return s.skillPlanError(c, err, "usersPlansPath()", nil)
+	}
+
+	s.flashSuccess(c, "Skill Plan imported successfully")
+	return c.Redirect(http.StatusFound, "usersPlanPath()", render.Data{"planID": plan.ID})
+
+}
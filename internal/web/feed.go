@@ -0,0 +1,203 @@
+package web
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/eveisesi/skillz"
+	"github.com/gobuffalo/buffalo"
+	"github.com/gobuffalo/buffalo/render"
+	"github.com/pkg/errors"
+)
+
+type atomFeed struct {
+	XMLName xml.Name     `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string       `xml:"id"`
+	Title   string       `xml:"title"`
+	Updated string       `xml:"updated"`
+	Link    []*atomLink  `xml:"link"`
+	Author  *atomAuthor  `xml:"author"`
+	Entries []*atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	ID      string    `xml:"id"`
+	Title   string    `xml:"title"`
+	Updated string    `xml:"updated"`
+	Link    *atomLink `xml:"link"`
+	Summary string    `xml:"summary"`
+}
+
+type rssFeed struct {
+	XMLName xml.Name    `xml:"rss"`
+	Version string      `xml:"version,attr"`
+	Channel *rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string     `xml:"title"`
+	Link          string     `xml:"link"`
+	Description   string     `xml:"description"`
+	LastBuildDate string     `xml:"lastBuildDate,omitempty"`
+	Items         []*rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	GUID        *rssGUID `xml:"guid"`
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	Description string   `xml:"description"`
+	PubDate     string   `xml:"pubDate"`
+}
+
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+}
+
+// feedEntry is a skill completion formatted for either of the feed formats
+type feedEntry struct {
+	id          string
+	title       string
+	summary     string
+	completedAt time.Time
+}
+
+func (s *Service) userAtomFeedHandler(c buffalo.Context) error {
+
+	u, entries, err := s.userFeed(c)
+	if err != nil {
+		return err
+	}
+
+	link := s.userLink(u)
+	feed := &atomFeed{
+		ID:      link,
+		Title:   fmt.Sprintf("%s Skill Completions", u.Character.Name),
+		Updated: u.CreatedAt.UTC().Format(time.RFC3339),
+		Link: []*atomLink{
+			{Href: link},
+			{Href: fmt.Sprintf("%s/feed.atom", link), Rel: "self"},
+		},
+		Author:  &atomAuthor{Name: u.Character.Name},
+		Entries: make([]*atomEntry, 0, len(entries)),
+	}
+
+	if len(entries) > 0 {
+		feed.Updated = entries[0].completedAt.UTC().Format(time.RFC3339)
+	}
+
+	for _, entry := range entries {
+		feed.Entries = append(feed.Entries, &atomEntry{
+			ID:      entry.id,
+			Title:   entry.title,
+			Updated: entry.completedAt.UTC().Format(time.RFC3339),
+			Link:    &atomLink{Href: link},
+			Summary: entry.summary,
+		})
+	}
+
+	return c.Render(http.StatusOK, render.Func("application/atom+xml; charset=utf-8", renderXML(feed)))
+
+}
+
+func (s *Service) userRSSFeedHandler(c buffalo.Context) error {
+
+	u, entries, err := s.userFeed(c)
+	if err != nil {
+		return err
+	}
+
+	link := s.userLink(u)
+	channel := &rssChannel{
+		Title:       fmt.Sprintf("%s Skill Completions", u.Character.Name),
+		Link:        link,
+		Description: fmt.Sprintf("Skills %s has finished training", u.Character.Name),
+		Items:       make([]*rssItem, 0, len(entries)),
+	}
+
+	if len(entries) > 0 {
+		channel.LastBuildDate = entries[0].completedAt.UTC().Format(time.RFC1123Z)
+	}
+
+	for _, entry := range entries {
+		channel.Items = append(channel.Items, &rssItem{
+			GUID:        &rssGUID{Value: entry.id},
+			Title:       entry.title,
+			Link:        link,
+			Description: entry.summary,
+			PubDate:     entry.completedAt.UTC().Format(time.RFC1123Z),
+		})
+	}
+
+	return c.Render(http.StatusOK, render.Func("application/rss+xml; charset=utf-8", renderXML(&rssFeed{Version: "2.0", Channel: channel})))
+
+}
+
+// userFeed loads the user of the request and their skill completions, respecting the user's
+// visibility settings. Users that have hidden their skills do not have a feed
+func (s *Service) userFeed(c buffalo.Context) (*skillz.User, []*feedEntry, error) {
+	var ctx = c.Request().Context()
+
+	u, err := s.viewableUser(c)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if u.Settings != nil && u.Settings.HideSkills {
+		return nil, nil, c.Error(http.StatusNotFound, errors.New("user has hidden their skills"))
+	}
+
+	completions, err := s.skills.SkillCompletions(ctx, u.CharacterID)
+	if err != nil {
+		return nil, nil, c.Error(http.StatusInternalServerError, err)
+	}
+
+	link := s.userLink(u)
+	entries := make([]*feedEntry, 0, len(completions))
+	for _, completion := range completions {
+		name := fmt.Sprintf("Skill %d", completion.SkillID)
+		if completion.Type != nil {
+			name = completion.Type.Name
+		}
+
+		entries = append(entries, &feedEntry{
+			id:          fmt.Sprintf("%s#skill-%d-%d", link, completion.SkillID, completion.Level),
+			title:       fmt.Sprintf("%s %d completed", name, completion.Level),
+			summary:     fmt.Sprintf("%s finished training %s to level %d", u.Character.Name, name, completion.Level),
+			completedAt: completion.CompletedAt,
+		})
+	}
+
+	return u, entries, nil
+
+}
+
+func (s *Service) userLink(u *skillz.User) string {
+	return fmt.Sprintf("%s/users/%s", s.baseDomain, u.ID)
+}
+
+func renderXML(v interface{}) func(w io.Writer, d render.Data) error {
+	return func(w io.Writer, d render.Data) error {
+		_, err := io.WriteString(w, xml.Header)
+		if err != nil {
+			return err
+		}
+
+		enc := xml.NewEncoder(w)
+		enc.Indent("", "  ")
+		return enc.Encode(v)
+	}
+}
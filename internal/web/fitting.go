@@ -0,0 +1,110 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/eveisesi/skillz"
+	"github.com/eveisesi/skillz/internal/fitting"
+	"github.com/gobuffalo/buffalo"
+	"github.com/gobuffalo/buffalo/render"
+	"github.com/pkg/errors"
+)
+
+type fittingForm struct {
+	Fitting string `form:"fitting"`
+}
+
+var fittingsPageTitle = func() (string, string) {
+	return "title", fmt.Sprintf("Fitting Check %s", titleSuffix)
+}
+
+func (s *Service) fittingsHandler(c buffalo.Context) error {
+
+	user := c.Data()[keyAuthenticatedUser].(*skillz.User)
+	if user == nil {
+		return c.Redirect(http.StatusFound, "rootPath()")
+	}
+
+	c.Set(fittingsPageTitle())
+	c.Set("fitting", "")
+	c.Set("report", nil)
+	c.Set("reportItems", nil)
+	return c.Render(http.StatusOK, s.renderer.HTML("user/fittings.plush.html"))
+
+}
+
+func (s *Service) postFittingsHandler(c buffalo.Context) error {
+
+	var ctx = c.Request().Context()
+
+	user := c.Data()[keyAuthenticatedUser].(*skillz.User)
+	if user == nil {
+		return c.Redirect(http.StatusFound, "rootPath()")
+	}
+
+	form := new(fittingForm)
+	err := c.Bind(form)
+	if err != nil {
+		s.flashDanger(c, "failed to process form. Please try again")
+		return c.Redirect(http.StatusFound, "usersFittingsPath()")
+	}
+
+	report, err := s.fittings.CheckEFT(ctx, user.CharacterID, form.Fitting)
+	if err != nil {
+		if errors.Is(err, fitting.ErrInvalidFitting) {
+			s.flashDanger(c, err.Error())
+			return c.Redirect(http.StatusFound, "usersFittingsPath()")
+		}
+		return c.Error(http.StatusInternalServerError, err)
+	}
+
+	c.Set(fittingsPageTitle())
+	c.Set("fitting", form.Fitting)
+	c.Set("report", report)
+	c.Set("reportItems", append([]*skillz.FittingItemReport{report.Ship}, report.Items...))
+	return c.Render(http.StatusOK, s.renderer.HTML("user/fittings.plush.html"))
+
+}
+
+// postFittingPlanHandler checks the fitting again and saves the
+// skill plan of whatever the character is missing to use it
+func (s *Service) postFittingPlanHandler(c buffalo.Context) error {
+
+	var ctx = c.Request().Context()
+
+	user := c.Data()[keyAuthenticatedUser].(*skillz.User)
+	if user == nil {
+		return c.Redirect(http.StatusFound, "rootPath()")
+	}
+
+	form := new(fittingForm)
+	err := c.Bind(form)
+	if err != nil {
+		s.flashDanger(c, "failed to process form. Please try again")
+		return c.Redirect(http.StatusFound, "usersFittingsPath()")
+	}
+
+	report, err := s.fittings.CheckEFT(ctx, user.CharacterID, form.Fitting)
+	if err != nil {
+		if errors.Is(err, fitting.ErrInvalidFitting) {
+			s.flashDanger(c, err.Error())
+			return c.Redirect(http.StatusFound, "usersFittingsPath()")
+		}
+		return c.Error(http.StatusInternalServerError, err)
+	}
+
+	if len(report.Plan.Entries) == 0 {
+		s.flashSuccess(c, "This character is already able to use this fitting")
+		return c.Redirect(http.StatusFound, "usersFittingsPath()")
+	}
+
+	plan, err := s.skills.CreateSkillPlan(ctx, user, report.Plan.Name, report.Plan.Entries)
+	if err != nil {
+		return s.skillPlanError(c, err, "usersFittingsPath()", nil)
+	}
+
+	s.flashSuccess(c, "Skill Plan created successfully")
+	return c.Redirect(http.StatusFound, "usersPlanPath()", render.Data{"planID": plan.ID})
+
+}
@@ -37,6 +37,7 @@ func (s *Service) setCurrentUser(next buffalo.Handler) buffalo.Handler {
 			}
 
 			c.Set(keyAuthenticatedUser, user)
+			c.Set(keyLinkedUsers, s.linkedCharacters(c, user))
 		}
 		return next(c)
 	}
@@ -0,0 +1,336 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/eveisesi/skillz"
+	"github.com/eveisesi/skillz/internal/skill"
+	"github.com/gertd/go-pluralize"
+	"github.com/gobuffalo/buffalo"
+	"github.com/gobuffalo/buffalo/render"
+	"github.com/pkg/errors"
+)
+
+type skillPlanForm struct {
+	Name string `form:"name"`
+}
+
+type skillPlanEntryForm struct {
+	SkillID uint `form:"skill_id"`
+	Level   uint `form:"level"`
+}
+
+var skillPlansPageTitle = func(name string) (string, string) {
+	name = pluralize.NewClient().Plural(name)
+	return "title", fmt.Sprintf("%s Skill Plans %s", name, titleSuffix)
+}
+
+func (s *Service) skillPlansHandler(c buffalo.Context) error {
+
+	var ctx = c.Request().Context()
+
+	user := c.Data()[keyAuthenticatedUser].(*skillz.User)
+	if user == nil {
+		return c.Redirect(http.StatusFound, "rootPath()")
+	}
+
+	plans, err := s.skills.SkillPlans(ctx, user)
+	if err != nil {
+		return c.Error(http.StatusInternalServerError, err)
+	}
+
+	c.Set(skillPlansPageTitle(user.Character.Name))
+	c.Set("plans", plans)
+	return c.Render(http.StatusOK, s.renderer.HTML("user/plans.plush.html"))
+
+}
+
+func (s *Service) postSkillPlansHandler(c buffalo.Context) error {
+
+	var ctx = c.Request().Context()
+
+	user := c.Data()[keyAuthenticatedUser].(*skillz.User)
+	if user == nil {
+		return c.Redirect(http.StatusFound, "rootPath()")
+	}
+
+	form := new(skillPlanForm)
+	err := c.Bind(form)
+	if err != nil {
+		s.flashDanger(c, "failed to process form. Please try again")
+		return c.Redirect(http.StatusFound, "usersPlansPath()")
+	}
+
+	plan, err := s.skills.CreateSkillPlan(ctx, user, form.Name, nil)
+	if err != nil {
+		return s.skillPlanError(c, err, "usersPlansPath()", nil)
+	}
+
+	s.flashSuccess(c, "Skill Plan created successfully")
+	return c.Redirect(http.StatusFound, "usersPlanPath()", render.Data{"planID": plan.ID})
+
+}
+
+func (s *Service) skillPlanHandler(c buffalo.Context) error {
+
+	var ctx = c.Request().Context()
+
+	user := c.Data()[keyAuthenticatedUser].(*skillz.User)
+	if user == nil {
+		return c.Redirect(http.StatusFound, "rootPath()")
+	}
+
+	planID, err := strconv.ParseUint(c.Param("planID"), 10, 32)
+	if err != nil {
+		return s.skillPlanError(c, skill.ErrSkillPlanNotFound, "usersPlansPath()", nil)
+	}
+
+	plan, err := s.skills.SkillPlan(ctx, user, uint(planID))
+	if err != nil {
+		return s.skillPlanError(c, err, "usersPlansPath()", nil)
+	}
+
+	groups, err := s.skills.SkillsGrouped(ctx, user.CharacterID)
+	if err != nil {
+		return c.Error(http.StatusInternalServerError, err)
+	}
+
+	remap, err := s.skills.SkillPlanRemap(ctx, user, plan.ID)
+	if err != nil {
+		return c.Error(http.StatusInternalServerError, err)
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Name < groups[j].Name
+	})
+
+	c.Set(skillPlansPageTitle(user.Character.Name))
+	c.Set("plan", plan)
+	c.Set("skillGroups", groups)
+	c.Set("remap", remap)
+	return c.Render(http.StatusOK, s.renderer.HTML("user/plan.plush.html"))
+
+}
+
+func (s *Service) postSkillPlanHandler(c buffalo.Context) error {
+
+	var ctx = c.Request().Context()
+
+	user := c.Data()[keyAuthenticatedUser].(*skillz.User)
+	if user == nil {
+		return c.Redirect(http.StatusFound, "rootPath()")
+	}
+
+	planID, err := strconv.ParseUint(c.Param("planID"), 10, 32)
+	if err != nil {
+		return s.skillPlanError(c, skill.ErrSkillPlanNotFound, "usersPlansPath()", nil)
+	}
+
+	data := render.Data{"planID": planID}
+
+	form := new(skillPlanForm)
+	err = c.Bind(form)
+	if err != nil {
+		s.flashDanger(c, "failed to process form. Please try again")
+		return c.Redirect(http.StatusFound, "usersPlanPath()", data)
+	}
+
+	_, err = s.skills.RenameSkillPlan(ctx, user, uint(planID), form.Name)
+	if err != nil {
+		return s.skillPlanError(c, err, "usersPlanPath()", data)
+	}
+
+	s.flashSuccess(c, "Skill Plan renamed successfully")
+	return c.Redirect(http.StatusFound, "usersPlanPath()", data)
+
+}
+
+func (s *Service) deleteSkillPlanHandler(c buffalo.Context) error {
+
+	var ctx = c.Request().Context()
+
+	user := c.Data()[keyAuthenticatedUser].(*skillz.User)
+	if user == nil {
+		return c.Redirect(http.StatusFound, "rootPath()")
+	}
+
+	planID, err := strconv.ParseUint(c.Param("planID"), 10, 32)
+	if err != nil {
+		return s.skillPlanError(c, skill.ErrSkillPlanNotFound, "usersPlansPath()", nil)
+	}
+
+	err = s.skills.DeleteSkillPlan(ctx, user, uint(planID))
+	if err != nil {
+		return s.skillPlanError(c, err, "usersPlansPath()", nil)
+	}
+
+	s.flashSuccess(c, "Skill Plan deleted successfully")
+	return c.Redirect(http.StatusFound, "usersPlansPath()")
+
+}
+
+func (s *Service) postSkillPlanEntryHandler(c buffalo.Context) error {
+
+	var ctx = c.Request().Context()
+
+	user := c.Data()[keyAuthenticatedUser].(*skillz.User)
+	if user == nil {
+		return c.Redirect(http.StatusFound, "rootPath()")
+	}
+
+	planID, err := strconv.ParseUint(c.Param("planID"), 10, 32)
+	if err != nil {
+		return s.skillPlanError(c, skill.ErrSkillPlanNotFound, "usersPlansPath()", nil)
+	}
+
+	data := render.Data{"planID": planID}
+
+	form := new(skillPlanEntryForm)
+	err = c.Bind(form)
+	if err != nil {
+		s.flashDanger(c, "failed to process form. Please try again")
+		return c.Redirect(http.StatusFound, "usersPlanPath()", data)
+	}
+
+	plan, err := s.skills.SkillPlan(ctx, user, uint(planID))
+	if err != nil {
+		return s.skillPlanError(c, err, "usersPlansPath()", nil)
+	}
+
+	entries := append(plan.Entries, &skillz.SkillPlanEntry{SkillID: form.SkillID, Level: form.Level})
+
+	_, err = s.skills.UpdateSkillPlanEntries(ctx, user, plan.ID, entries)
+	if err != nil {
+		return s.skillPlanError(c, err, "usersPlanPath()", data)
+	}
+
+	return c.Redirect(http.StatusFound, "usersPlanPath()", data)
+
+}
+
+func (s *Service) moveSkillPlanEntryHandler(c buffalo.Context) error {
+
+	var ctx = c.Request().Context()
+
+	user := c.Data()[keyAuthenticatedUser].(*skillz.User)
+	if user == nil {
+		return c.Redirect(http.StatusFound, "rootPath()")
+	}
+
+	planID, err := strconv.ParseUint(c.Param("planID"), 10, 32)
+	if err != nil {
+		return s.skillPlanError(c, skill.ErrSkillPlanNotFound, "usersPlansPath()", nil)
+	}
+
+	data := render.Data{"planID": planID}
+
+	position, err := strconv.ParseUint(c.Param("position"), 10, 32)
+	if err != nil {
+		s.flashDanger(c, "Invalid value for position. Please try again")
+		return c.Redirect(http.StatusFound, "usersPlanPath()", data)
+	}
+
+	plan, err := s.skills.SkillPlan(ctx, user, uint(planID))
+	if err != nil {
+		return s.skillPlanError(c, err, "usersPlansPath()", nil)
+	}
+
+	order := make([]uint, 0, len(plan.Entries))
+	for _, entry := range plan.Entries {
+		order = append(order, entry.Position)
+	}
+
+	for i, p := range order {
+		if p != uint(position) {
+			continue
+		}
+
+		switch c.Request().FormValue("direction") {
+		case "up":
+			if i > 0 {
+				order[i-1], order[i] = order[i], order[i-1]
+			}
+		case "down":
+			if i < len(order)-1 {
+				order[i+1], order[i] = order[i], order[i+1]
+			}
+		}
+		break
+	}
+
+	_, err = s.skills.ReorderSkillPlan(ctx, user, plan.ID, order)
+	if err != nil {
+		return s.skillPlanError(c, err, "usersPlanPath()", data)
+	}
+
+	return c.Redirect(http.StatusFound, "usersPlanPath()", data)
+
+}
+
+func (s *Service) deleteSkillPlanEntryHandler(c buffalo.Context) error {
+
+	var ctx = c.Request().Context()
+
+	user := c.Data()[keyAuthenticatedUser].(*skillz.User)
+	if user == nil {
+		return c.Redirect(http.StatusFound, "rootPath()")
+	}
+
+	planID, err := strconv.ParseUint(c.Param("planID"), 10, 32)
+	if err != nil {
+		return s.skillPlanError(c, skill.ErrSkillPlanNotFound, "usersPlansPath()", nil)
+	}
+
+	data := render.Data{"planID": planID}
+
+	position, err := strconv.ParseUint(c.Param("position"), 10, 32)
+	if err != nil {
+		s.flashDanger(c, "Invalid value for position. Please try again")
+		return c.Redirect(http.StatusFound, "usersPlanPath()", data)
+	}
+
+	plan, err := s.skills.SkillPlan(ctx, user, uint(planID))
+	if err != nil {
+		return s.skillPlanError(c, err, "usersPlansPath()", nil)
+	}
+
+	entries := make([]*skillz.SkillPlanEntry, 0, len(plan.Entries))
+	for _, entry := range plan.Entries {
+		if entry.Position == uint(position) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	_, err = s.skills.UpdateSkillPlanEntries(ctx, user, plan.ID, entries)
+	if err != nil {
+		return s.skillPlanError(c, err, "usersPlanPath()", data)
+	}
+
+	return c.Redirect(http.StatusFound, "usersPlanPath()", data)
+
+}
+
+// skillPlanError flashes validation and not found errors back to the user and
+// redirects them to the provided route. All other errors are treated as a 500
+func (s *Service) skillPlanError(c buffalo.Context, err error, route string, data render.Data) error {
+
+	switch {
+	case errors.Is(err, skill.ErrSkillPlanNotFound):
+		s.flashDanger(c, "Skill Plan Not Found")
+		return c.Redirect(http.StatusFound, "usersPlansPath()")
+	case errors.Is(err, skill.ErrInvalidSkillPlan), errors.Is(err, skill.ErrInvalidSkillPlanEntry),
+		errors.Is(err, skill.ErrCyclicPrerequisites), errors.Is(err, skill.ErrImpossiblePrerequisite):
+		s.flashDanger(c, err.Error())
+		if data == nil {
+			return c.Redirect(http.StatusFound, route)
+		}
+		return c.Redirect(http.StatusFound, route, data)
+	}
+
+	return c.Error(http.StatusInternalServerError, err)
+
+}
@@ -0,0 +1,138 @@
+package web
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eveisesi/skillz"
+	"github.com/eveisesi/skillz/internal/user/v2"
+	"github.com/gobuffalo/buffalo"
+	"github.com/pkg/errors"
+	"github.com/volatiletech/null"
+)
+
+// shareLinkExpiries are the number of days a share link can be created for
+var shareLinkExpiries = []int{1, 3, 7, 14, 30}
+
+// sharedUserHandler renders the sections of a user's skillboard that are shared through the share link
+// and records the view so that the user can see who has looked at their skillboard
+func (s *Service) sharedUserHandler(c buffalo.Context) error {
+	var r = c.Request()
+	var ctx = r.Context()
+
+	u, link, err := s.user.SharedUser(ctx, c.Param("token"))
+	if err != nil && !errors.Is(err, user.ErrShareLinkNotFound) {
+		return c.Error(http.StatusInternalServerError, err)
+	}
+
+	if errors.Is(err, user.ErrShareLinkNotFound) {
+		s.flashDanger(c, "This share link has expired or been revoked")
+		return c.Redirect(http.StatusFound, "rootPath()")
+	}
+
+	view := &skillz.UserShareLinkView{
+		IPAddress: clientIP(r),
+		UserAgent: r.UserAgent(),
+	}
+	if len(view.UserAgent) > 512 {
+		view.UserAgent = view.UserAgent[:512]
+	}
+
+	if viewer, ok := c.Data()[keyAuthenticatedUser].(*skillz.User); ok {
+		view.ViewerID = null.StringFrom(viewer.ID)
+	}
+
+	if !view.ViewerID.Valid || view.ViewerID.String != u.ID {
+		err = s.user.RecordShareLinkView(ctx, link, view)
+		if err != nil {
+			s.logger.WithError(err).WithField("userID", u.ID).Error("failed to record share link view")
+		}
+	}
+
+	return s.renderUserPage(c, u, link)
+
+}
+
+func (s *Service) postShareLinkHandler(c buffalo.Context) error {
+
+	var ctx = c.Request().Context()
+
+	authenticatedUser := c.Data()[keyAuthenticatedUser].(*skillz.User)
+	if authenticatedUser == nil {
+		return c.Redirect(http.StatusFound, "rootPath()")
+	}
+
+	var link = new(skillz.UserShareLink)
+	err := c.Bind(link)
+	if err != nil {
+		s.flashDanger(c, "failed to process form. Please try again")
+		return c.Redirect(http.StatusFound, "usersSettingsPath()")
+	}
+
+	days, err := strconv.Atoi(c.Request().Form.Get("expires_in"))
+	if err != nil {
+		s.flashDanger(c, "Invalid value for Expires In. Please try again")
+		return c.Redirect(http.StatusFound, "usersSettingsPath()")
+	}
+
+	err = s.user.CreateShareLink(ctx, authenticatedUser, link, time.Duration(days)*time.Hour*24)
+	if err != nil {
+		if errors.Is(err, user.ErrInvalidShareLink) {
+			s.flashDanger(c, err.Error())
+			return c.Redirect(http.StatusFound, "usersSettingsPath()")
+		}
+
+		s.logger.WithError(err).WithField("userID", authenticatedUser.ID).Error("failed to create share link")
+		s.flashDanger(c, "Failed to create share link. Please try again")
+		return c.Redirect(http.StatusFound, "usersSettingsPath()")
+	}
+
+	s.flashSuccess(c, "Share link created successfully")
+	return c.Redirect(http.StatusFound, "usersSettingsPath()")
+
+}
+
+func (s *Service) deleteShareLinkHandler(c buffalo.Context) error {
+
+	var ctx = c.Request().Context()
+
+	authenticatedUser := c.Data()[keyAuthenticatedUser].(*skillz.User)
+	if authenticatedUser == nil {
+		return c.Redirect(http.StatusFound, "rootPath()")
+	}
+
+	err := s.user.RevokeShareLink(ctx, authenticatedUser, c.Param("token"))
+	if err != nil {
+		s.logger.WithError(err).WithField("userID", authenticatedUser.ID).Error("failed to revoke share link")
+		s.flashDanger(c, "Failed to revoke share link. Please try again")
+		return c.Redirect(http.StatusFound, "usersSettingsPath()")
+	}
+
+	s.flashSuccess(c, "Share link revoked successfully")
+	return c.Redirect(http.StatusFound, "usersSettingsPath()")
+
+}
+
+// clientIP returns the address of the client, preferring the address
+// forwarded by the proxy that the application is deployed behind
+func clientIP(r *http.Request) string {
+
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		return strings.TrimSpace(strings.Split(forwarded, ",")[0])
+	}
+
+	if realIP := r.Header.Get("X-Real-IP"); realIP != "" {
+		return realIP
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+
+}
@@ -65,50 +65,9 @@ func (s *Service) userHandler(c buffalo.Context) error {
 		return c.Redirect(http.StatusFound, "rootPath()")
 	}
 
-	settings := u.Settings
-	if settings != nil {
-		if settings.Visibility == skillz.VisibilityPrivate {
-			sessionUser := c.Data()[keyAuthenticatedUser]
-			if sessionUser == nil {
-				s.flashDanger(c, "User Not Found")
-				return c.Redirect(http.StatusFound, "rootPath()")
-			}
-
-			authenticatedUser := sessionUser.(*skillz.User)
-			if authenticatedUser.ID != userID {
-				s.flashDanger(c, "User Not Found")
-				return c.Redirect(http.StatusFound, "rootPath()")
-			}
-		}
-
-		if settings.Visibility == skillz.VisibilityToken {
-			token := c.Param("token")
-			if token != "" {
-
-				if token != "" && token != settings.VisibilityToken {
-					s.flashDanger(c, "User Not Found")
-					return c.Redirect(http.StatusFound, "rootPath()")
-				}
-			} else {
-				sessionUser := c.Data()[keyAuthenticatedUser]
-				if sessionUser == nil {
-					s.flashDanger(c, "User Not Found")
-					return c.Redirect(http.StatusFound, "rootPath()")
-				}
-
-				authenticatedUser, ok := sessionUser.(*skillz.User)
-				if !ok {
-					s.flashDanger(c, "User Not Found")
-					return c.Redirect(http.StatusFound, "rootPath()")
-				}
-
-				if authenticatedUser.ID != userID {
-					s.flashDanger(c, "User Not Found")
-					return c.Redirect(http.StatusFound, "rootPath()")
-				}
-			}
-
-		}
+	if !s.canViewUser(c, u) {
+		s.flashDanger(c, "User Not Found")
+		return c.Redirect(http.StatusFound, "rootPath()")
 	}
 
 	if u.IsNew {
@@ -120,11 +79,21 @@ func (s *Service) userHandler(c buffalo.Context) error {
 	if err != nil && !errors.Is(err, user.ErrUserNotFound) {
 		return c.Error(http.StatusInternalServerError, err)
 	}
+
+	return s.renderUserPage(c, u, nil)
+}
+
+// renderUserPage renders the skillboard of the loaded user. The share link is
+// provided when the skillboard is being viewed through one of the user's share links
+func (s *Service) renderUserPage(c buffalo.Context, u *skillz.User, link *skillz.UserShareLink) error {
+	var ctx = c.Request().Context()
+
 	if u.Character != nil {
 		c.Set(s.userPageMeta(ctx, u))
 	}
 
 	c.Set("user", u)
+	c.Set("shareLink", link)
 
 	jsonSkillGrouped, err := json.Marshal(u.SkillsGrouped)
 	if err != nil {
@@ -155,6 +124,20 @@ func (s *Service) userSettingsHandler(c buffalo.Context) error {
 		return o
 	})
 	c.Set("visibilities", skillz.AllVisibilities)
+
+	links, err := s.user.ShareLinks(ctx, user)
+	if err != nil {
+		return c.Error(http.StatusInternalServerError, err)
+	}
+
+	accountUsers, err := s.user.LinkedUsers(ctx, user)
+	if err != nil {
+		return c.Error(http.StatusInternalServerError, err)
+	}
+
+	c.Set("accountUsers", accountUsers)
+	c.Set("shareLinks", links)
+	c.Set("shareLinkExpiries", shareLinkExpiries)
 	return c.Render(http.StatusOK, s.renderer.HTML("user/settings.plush.html"))
 }
 
@@ -186,6 +169,32 @@ func (s *Service) postUserSettingsHandler(c buffalo.Context) error {
 		return err
 	}
 
+	if c.Request().Form.Has("apply_to_linked") {
+		linked, err := s.user.LinkedUsers(ctx, user)
+		if err != nil {
+			return err
+		}
+
+		for _, u := range linked {
+			if u.ID == user.ID {
+				continue
+			}
+
+			// Each character keeps its own visibility token so that sharing one
+			// character's tokenized page does not expose the rest of the account
+			linkedSettings := *settings
+			linkedSettings.VisibilityToken = ""
+			if u.Settings != nil {
+				linkedSettings.VisibilityToken = u.Settings.VisibilityToken
+			}
+
+			err = s.user.CreateUserSettings(ctx, u.ID, &linkedSettings)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
 	user.Settings = settings
 	c.Set(userSettingsPageTitle(user.Character.Name))
 
@@ -223,3 +232,31 @@ func (s *Service) deleteUserSettingsHandler(c buffalo.Context) error {
 	return c.Redirect(http.StatusFound, "rootPath()")
 
 }
+
+// canViewUser reports whether the visitor is allowed to view the provided user based on the user's
+// visibility settings. The user's visibility token is read from the token parameter
+func (s *Service) canViewUser(c buffalo.Context, u *skillz.User) bool {
+
+	sessionUser, _ := c.Data()[keyAuthenticatedUser].(*skillz.User)
+
+	return user.CanView(u, sessionUser, c.Param("token"))
+
+}
+
+// viewableUser loads the user of the request along with their character. Users that do not exist
+// and users that the visitor is not allowed to view are reported as not found
+func (s *Service) viewableUser(c buffalo.Context) (*skillz.User, error) {
+	var ctx = c.Request().Context()
+
+	u, err := s.user.User(ctx, c.Param("userID"), user.UserCharacterRel)
+	if err != nil && !errors.Is(err, user.ErrUserNotFound) {
+		return nil, c.Error(http.StatusInternalServerError, err)
+	}
+
+	if errors.Is(err, user.ErrUserNotFound) || !s.canViewUser(c, u) || u.Character == nil {
+		return nil, c.Error(http.StatusNotFound, user.ErrUserNotFound)
+	}
+
+	return u, nil
+
+}
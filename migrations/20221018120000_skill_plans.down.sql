@@ -0,0 +1 @@
+DROP TABLE `skill_plans`;
\ No newline at end of file
@@ -0,0 +1,10 @@
+CREATE TABLE `skill_plans` (
+    `id` INT UNSIGNED NOT NULL AUTO_INCREMENT,
+    `user_id` VARCHAR(128) NOT NULL COLLATE 'utf8mb4_unicode_ci',
+    `name` VARCHAR(255) NOT NULL COLLATE 'utf8mb4_unicode_ci',
+    `created_at` DATETIME NOT NULL,
+    `updated_at` DATETIME NOT NULL,
+    PRIMARY KEY (`id`) USING BTREE,
+    INDEX `skill_plans_user_id_idx` (`user_id`),
+    CONSTRAINT `skill_plans_user_id_foreign` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON UPDATE CASCADE ON DELETE CASCADE
+) COLLATE = 'utf8mb4_unicode_ci' ENGINE = InnoDB;
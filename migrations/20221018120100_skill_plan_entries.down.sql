@@ -0,0 +1 @@
+DROP TABLE `skill_plan_entries`;
\ No newline at end of file
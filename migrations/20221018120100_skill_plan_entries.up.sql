@@ -0,0 +1,11 @@
+CREATE TABLE `skill_plan_entries` (
+    `plan_id` INT UNSIGNED NOT NULL,
+    `position` SMALLINT UNSIGNED NOT NULL,
+    `skill_id` INT UNSIGNED NOT NULL,
+    `level` TINYINT UNSIGNED NOT NULL,
+    `created_at` DATETIME NOT NULL,
+    PRIMARY KEY (`plan_id`, `position`) USING BTREE,
+    INDEX `skill_plan_entries_skill_id_idx` (`skill_id`),
+    CONSTRAINT `skill_plan_entries_plan_id_foreign` FOREIGN KEY (`plan_id`) REFERENCES `skill_plans` (`id`) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT `skill_plan_entries_skill_id_foreign` FOREIGN KEY (`skill_id`) REFERENCES `types` (`id`) ON UPDATE CASCADE ON DELETE CASCADE
+) COLLATE = 'utf8mb4_unicode_ci' ENGINE = InnoDB;
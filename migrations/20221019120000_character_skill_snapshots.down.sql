@@ -0,0 +1 @@
+DROP TABLE `character_skill_snapshots`;
\ No newline at end of file
@@ -0,0 +1,10 @@
+CREATE TABLE `character_skill_snapshots` (
+    `id` BIGINT(20) UNSIGNED NOT NULL AUTO_INCREMENT,
+    `character_id` BIGINT(20) UNSIGNED NOT NULL,
+    `total_sp` INT UNSIGNED NOT NULL,
+    `unallocated_sp` INT UNSIGNED NULL DEFAULT NULL,
+    `created_at` DATETIME NOT NULL,
+    PRIMARY KEY (`id`) USING BTREE,
+    INDEX `character_skill_snapshots_character_id_created_at_idx` (`character_id`, `created_at`),
+    CONSTRAINT `character_skill_snapshots_character_id_foreign` FOREIGN KEY (`character_id`) REFERENCES `users` (`character_id`) ON UPDATE CASCADE ON DELETE CASCADE
+) COLLATE = 'utf8mb4_unicode_ci' ENGINE = InnoDB;
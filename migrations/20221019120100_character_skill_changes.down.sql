@@ -0,0 +1 @@
+DROP TABLE `character_skill_changes`;
\ No newline at end of file
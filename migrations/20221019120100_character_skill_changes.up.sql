@@ -0,0 +1,13 @@
+CREATE TABLE `character_skill_changes` (
+    `snapshot_id` BIGINT(20) UNSIGNED NOT NULL,
+    `character_id` BIGINT(20) UNSIGNED NOT NULL,
+    `skill_id` INT UNSIGNED NOT NULL,
+    `previous_skill_level` TINYINT UNSIGNED NOT NULL,
+    `trained_skill_level` TINYINT UNSIGNED NOT NULL,
+    `previous_skillpoints` INT UNSIGNED NOT NULL,
+    `skillpoints_in_skill` INT UNSIGNED NOT NULL,
+    `created_at` DATETIME NOT NULL,
+    PRIMARY KEY (`snapshot_id`, `skill_id`) USING BTREE,
+    INDEX `character_skill_changes_character_id_created_at_idx` (`character_id`, `created_at`),
+    CONSTRAINT `character_skill_changes_snapshot_id_foreign` FOREIGN KEY (`snapshot_id`) REFERENCES `character_skill_snapshots` (`id`) ON UPDATE CASCADE ON DELETE CASCADE
+) COLLATE = 'utf8mb4_unicode_ci' ENGINE = InnoDB;
@@ -0,0 +1 @@
+DROP TABLE `character_skill_completions`;
\ No newline at end of file
@@ -0,0 +1,10 @@
+CREATE TABLE `character_skill_completions` (
+    `character_id` BIGINT(20) UNSIGNED NOT NULL,
+    `skill_id` INT UNSIGNED NOT NULL,
+    `level` TINYINT UNSIGNED NOT NULL,
+    `completed_at` DATETIME NOT NULL,
+    `created_at` DATETIME NOT NULL,
+    PRIMARY KEY (`character_id`, `skill_id`, `level`) USING BTREE,
+    INDEX `character_skill_completions_character_id_completed_at_idx` (`character_id`, `completed_at`),
+    CONSTRAINT `character_skill_completions_character_id_foreign` FOREIGN KEY (`character_id`) REFERENCES `users` (`character_id`) ON UPDATE CASCADE ON DELETE CASCADE
+) COLLATE = 'utf8mb4_unicode_ci' ENGINE = InnoDB;
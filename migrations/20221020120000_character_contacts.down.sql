@@ -0,0 +1 @@
+DROP TABLE `character_contacts`;
\ No newline at end of file
@@ -0,0 +1,9 @@
+CREATE TABLE `character_contacts` (
+    `character_id` bigint(20) UNSIGNED NOT NULL,
+    `contact_id` int(10) UNSIGNED NOT NULL,
+    `contact_type` enum('character', 'corporation', 'alliance', 'faction') NOT NULL,
+    `standing` decimal(4, 2) NOT NULL,
+    `created_at` datetime NOT NULL,
+    PRIMARY KEY (`character_id`, `contact_id`),
+    CONSTRAINT `character_contacts_character_id_foreign` FOREIGN KEY (`character_id`) REFERENCES `users` (`character_id`) ON UPDATE CASCADE ON DELETE CASCADE
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_unicode_ci;
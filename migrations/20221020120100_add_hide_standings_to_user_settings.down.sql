@@ -0,0 +1,2 @@
+ALTER TABLE
+    `user_settings` DROP COLUMN `hide_standings`;
\ No newline at end of file
@@ -0,0 +1,6 @@
+ALTER TABLE
+    `user_settings`
+ADD
+    COLUMN `hide_standings` tinyint(1) UNSIGNED NOT NULL DEFAULT '1'
+AFTER
+    `hide_implants`;
\ No newline at end of file
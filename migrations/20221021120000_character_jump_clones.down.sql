@@ -0,0 +1 @@
+DROP TABLE `character_jump_clones`;
\ No newline at end of file
@@ -0,0 +1,10 @@
+CREATE TABLE `character_jump_clones` (
+    `character_id` bigint(20) UNSIGNED NOT NULL,
+    `jump_clone_id` int(10) UNSIGNED NOT NULL,
+    `name` varchar(255) NULL DEFAULT NULL,
+    `location_id` bigint(20) UNSIGNED NOT NULL,
+    `location_type` enum('station', 'structure') NOT NULL,
+    `created_at` datetime NOT NULL,
+    PRIMARY KEY (`character_id`, `jump_clone_id`),
+    CONSTRAINT `character_jump_clones_character_id_foreign` FOREIGN KEY (`character_id`) REFERENCES `users` (`character_id`) ON UPDATE CASCADE ON DELETE CASCADE
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_unicode_ci;
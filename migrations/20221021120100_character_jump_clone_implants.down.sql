@@ -0,0 +1 @@
+DROP TABLE `character_jump_clone_implants`;
\ No newline at end of file
@@ -0,0 +1,11 @@
+CREATE TABLE `character_jump_clone_implants` (
+    `character_id` bigint(20) UNSIGNED NOT NULL,
+    `jump_clone_id` int(10) UNSIGNED NOT NULL,
+    `implant_id` int(10) UNSIGNED NOT NULL,
+    `slot` int(10) UNSIGNED NOT NULL,
+    `created_at` datetime NOT NULL,
+    PRIMARY KEY (`character_id`, `jump_clone_id`, `implant_id`),
+    KEY `character_jump_clone_implants_implant_id` (`implant_id`),
+    CONSTRAINT `character_jump_clone_implants_jump_clone_foreign` FOREIGN KEY (`character_id`, `jump_clone_id`) REFERENCES `character_jump_clones` (`character_id`, `jump_clone_id`) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT `character_jump_clone_implants_implant_id_foreign` FOREIGN KEY (`implant_id`) REFERENCES `types` (`id`) ON UPDATE CASCADE ON DELETE CASCADE
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_unicode_ci;
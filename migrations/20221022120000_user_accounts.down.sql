@@ -0,0 +1 @@
+DROP TABLE `user_accounts`;
\ No newline at end of file
@@ -0,0 +1,9 @@
+CREATE TABLE `user_accounts` (
+    `user_id` VARCHAR(128) NOT NULL COLLATE 'utf8mb4_unicode_ci',
+    `account_id` VARCHAR(128) NOT NULL COLLATE 'utf8mb4_unicode_ci',
+    `owner_hash` VARCHAR(255) NOT NULL COLLATE 'utf8mb4_unicode_ci',
+    `created_at` DATETIME NOT NULL,
+    PRIMARY KEY (`user_id`),
+    INDEX `user_accounts_account_id_idx` (`account_id`),
+    CONSTRAINT `user_accounts_user_id_foreign` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON UPDATE CASCADE ON DELETE CASCADE
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_unicode_ci;
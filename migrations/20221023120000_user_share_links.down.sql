@@ -0,0 +1 @@
+DROP TABLE `user_share_links`;
\ No newline at end of file
@@ -0,0 +1,14 @@
+CREATE TABLE `user_share_links` (
+    `token` VARCHAR(64) NOT NULL COLLATE 'utf8mb4_unicode_ci',
+    `user_id` VARCHAR(128) NOT NULL COLLATE 'utf8mb4_unicode_ci',
+    `label` VARCHAR(255) NOT NULL COLLATE 'utf8mb4_unicode_ci',
+    `share_skills` tinyint(1) UNSIGNED NOT NULL DEFAULT '0',
+    `share_queue` tinyint(1) UNSIGNED NOT NULL DEFAULT '0',
+    `share_implants` tinyint(1) UNSIGNED NOT NULL DEFAULT '0',
+    `share_flyable` tinyint(1) UNSIGNED NOT NULL DEFAULT '0',
+    `expires_at` DATETIME NOT NULL,
+    `created_at` DATETIME NOT NULL,
+    PRIMARY KEY (`token`),
+    INDEX `user_share_links_user_id_idx` (`user_id`),
+    CONSTRAINT `user_share_links_user_id_foreign` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON UPDATE CASCADE ON DELETE CASCADE
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_unicode_ci;
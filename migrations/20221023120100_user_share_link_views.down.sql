@@ -0,0 +1 @@
+DROP TABLE `user_share_link_views`;
\ No newline at end of file
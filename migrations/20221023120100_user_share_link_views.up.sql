@@ -0,0 +1,11 @@
+CREATE TABLE `user_share_link_views` (
+    `id` BIGINT(20) UNSIGNED NOT NULL AUTO_INCREMENT,
+    `token` VARCHAR(64) NOT NULL COLLATE 'utf8mb4_unicode_ci',
+    `viewer_id` VARCHAR(128) NULL DEFAULT NULL COLLATE 'utf8mb4_unicode_ci',
+    `ip_address` VARCHAR(64) NOT NULL COLLATE 'utf8mb4_unicode_ci',
+    `user_agent` VARCHAR(512) NOT NULL COLLATE 'utf8mb4_unicode_ci',
+    `viewed_at` DATETIME NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `user_share_link_views_token_idx` (`token`),
+    CONSTRAINT `user_share_link_views_token_foreign` FOREIGN KEY (`token`) REFERENCES `user_share_links` (`token`) ON UPDATE CASCADE ON DELETE CASCADE
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_unicode_ci;
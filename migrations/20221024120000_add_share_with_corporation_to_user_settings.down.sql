@@ -0,0 +1,2 @@
+ALTER TABLE
+    `user_settings` DROP COLUMN `share_with_corporation`;
\ No newline at end of file
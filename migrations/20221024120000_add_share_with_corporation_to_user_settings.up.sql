@@ -0,0 +1,6 @@
+ALTER TABLE
+    `user_settings`
+ADD
+    COLUMN `share_with_corporation` tinyint(1) UNSIGNED NOT NULL DEFAULT '0'
+AFTER
+    `hide_standings`;
\ No newline at end of file
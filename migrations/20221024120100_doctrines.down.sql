@@ -0,0 +1 @@
+DROP TABLE `doctrines`;
\ No newline at end of file
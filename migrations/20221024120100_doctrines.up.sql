@@ -0,0 +1,12 @@
+CREATE TABLE `doctrines` (
+    `id` INT UNSIGNED NOT NULL AUTO_INCREMENT,
+    `corporation_id` INT UNSIGNED NOT NULL,
+    `alliance_id` INT UNSIGNED NULL DEFAULT NULL,
+    `name` VARCHAR(255) NOT NULL COLLATE 'utf8mb4_unicode_ci',
+    `created_by` VARCHAR(128) NOT NULL COLLATE 'utf8mb4_unicode_ci',
+    `created_at` DATETIME NOT NULL,
+    `updated_at` DATETIME NOT NULL,
+    PRIMARY KEY (`id`) USING BTREE,
+    INDEX `doctrines_corporation_id_idx` (`corporation_id`),
+    INDEX `doctrines_alliance_id_idx` (`alliance_id`)
+) COLLATE = 'utf8mb4_unicode_ci' ENGINE = InnoDB;
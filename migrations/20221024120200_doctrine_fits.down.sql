@@ -0,0 +1 @@
+DROP TABLE `doctrine_fits`;
\ No newline at end of file
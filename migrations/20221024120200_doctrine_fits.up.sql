@@ -0,0 +1,11 @@
+CREATE TABLE `doctrine_fits` (
+    `id` INT UNSIGNED NOT NULL AUTO_INCREMENT,
+    `doctrine_id` INT UNSIGNED NOT NULL,
+    `name` VARCHAR(255) NOT NULL COLLATE 'utf8mb4_unicode_ci',
+    `ship_type_id` INT UNSIGNED NOT NULL,
+    `created_at` DATETIME NOT NULL,
+    PRIMARY KEY (`id`) USING BTREE,
+    INDEX `doctrine_fits_doctrine_id_idx` (`doctrine_id`),
+    CONSTRAINT `doctrine_fits_doctrine_id_foreign` FOREIGN KEY (`doctrine_id`) REFERENCES `doctrines` (`id`) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT `doctrine_fits_ship_type_id_foreign` FOREIGN KEY (`ship_type_id`) REFERENCES `types` (`id`) ON UPDATE CASCADE ON DELETE CASCADE
+) COLLATE = 'utf8mb4_unicode_ci' ENGINE = InnoDB;
@@ -0,0 +1 @@
+DROP TABLE `doctrine_fit_skills`;
\ No newline at end of file
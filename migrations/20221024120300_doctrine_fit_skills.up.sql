@@ -0,0 +1,8 @@
+CREATE TABLE `doctrine_fit_skills` (
+    `fit_id` INT UNSIGNED NOT NULL,
+    `skill_id` INT UNSIGNED NOT NULL,
+    `level` TINYINT UNSIGNED NOT NULL,
+    PRIMARY KEY (`fit_id`, `skill_id`) USING BTREE,
+    CONSTRAINT `doctrine_fit_skills_fit_id_foreign` FOREIGN KEY (`fit_id`) REFERENCES `doctrine_fits` (`id`) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT `doctrine_fit_skills_skill_id_foreign` FOREIGN KEY (`skill_id`) REFERENCES `types` (`id`) ON UPDATE CASCADE ON DELETE CASCADE
+) COLLATE = 'utf8mb4_unicode_ci' ENGINE = InnoDB;
@@ -41,4 +41,36 @@ function levelVTotalSP(group) {
 // potentialSP is the product of the rank of the skill multiplied by 256000
 function potentialSP(rank) {
     return rank * 256000
-}
\ No newline at end of file
+}
+
+// formatDuration converts a Go time.Duration (nanoseconds) into a 1d 4h 23m string
+function formatDuration(ns) {
+    let seconds = Math.round(ns / 1e9)
+    if (seconds <= 0) {
+        return "N/A"
+    }
+
+    const days = Math.floor(seconds / 86400)
+    seconds -= days * 86400
+    const hours = Math.floor(seconds / 3600)
+    seconds -= hours * 3600
+    const minutes = Math.floor(seconds / 60)
+    seconds -= minutes * 60
+
+    const parts = []
+    if (days > 0) parts.push(`${days}d`)
+    if (hours > 0) parts.push(`${hours}h`)
+    if (minutes > 0) parts.push(`${minutes}m`)
+    if (seconds > 0) parts.push(`${seconds}s`)
+
+    return parts.join(" ")
+}
+
+// trainingTime returns the time to the next level and to level V for skills that are not yet at level V
+function trainingTime(skill) {
+    if (!skill.training || skill.training.time_to_level_v <= 0) {
+        return ""
+    }
+
+    return `<br>Next Level: ${formatDuration(skill.training.time_to_next_level)} | Level V: ${formatDuration(skill.training.time_to_level_v)}`
+}
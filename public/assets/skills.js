@@ -109,6 +109,7 @@ function renderSkillGroupDetails() {
                     ${typeName}<br>
                     <span class="text-muted">
                         Rank: ${rank} | Total SP: ${numeral(sp).format("0,0")} of ${numeral(potentialSP(rank)).format("0,0")}
+                        ${trainingTime(skill)}
                     </span>
                 </div>
             `
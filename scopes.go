@@ -24,15 +24,21 @@ type ScopeProcessors []Processor
 type Scope string
 
 const (
+	ReadContactsV1   Scope = "esi-characters.read_contacts.v1"
+	ReadClonesV1     Scope = "esi-clones.read_clones.v1"
 	ReadImplantsV1   Scope = "esi-clones.read_implants.v1"
 	ReadSkillQueueV1 Scope = "esi-skills.read_skillqueue.v1"
 	ReadSkillsV1     Scope = "esi-skills.read_skills.v1"
+	ReadStructuresV1 Scope = "esi-universe.read_structures.v1"
 )
 
 var AllScopes = []Scope{
+	ReadContactsV1,
+	ReadClonesV1,
 	ReadImplantsV1,
 	ReadSkillQueueV1,
 	ReadSkillsV1,
+	ReadStructuresV1,
 }
 
 func (s Scope) String() string {
@@ -41,6 +47,17 @@ func (s Scope) String() string {
 
 type UserScopes []Scope
 
+// Has reports whether the scope has been granted
+func (s UserScopes) Has(scope Scope) bool {
+	for _, granted := range s {
+		if granted == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (s *UserScopes) Scan(value interface{}) error {
 
 	switch data := value.(type) {
@@ -12,6 +12,9 @@ type CharacterSkillRepository interface {
 	memberSkillsRepository
 	memberSkillQueueRepository
 	memberFlyableShipRepository
+	memberSkillPlanRepository
+	memberSkillSnapshotRepository
+	memberSkillCompletionRepository
 }
 
 type memberAttributesRepository interface {
@@ -42,6 +45,34 @@ type memberFlyableShipRepository interface {
 	DeleteCharacterFlyableShips(ctx context.Context, characterID uint64) error
 }
 
+type memberSkillPlanRepository interface {
+	SkillPlan(ctx context.Context, id uint) (*SkillPlan, error)
+	SkillPlansByUserID(ctx context.Context, userID string) ([]*SkillPlan, error)
+	// CreateSkillPlan atomically creates the plan along with its entries, which are assigned the id of the plan
+	CreateSkillPlan(ctx context.Context, plan *SkillPlan) error
+	UpdateSkillPlan(ctx context.Context, plan *SkillPlan) error
+	DeleteSkillPlan(ctx context.Context, id uint) error
+
+	SkillPlanEntries(ctx context.Context, planID uint) ([]*SkillPlanEntry, error)
+	CreateSkillPlanEntries(ctx context.Context, entries []*SkillPlanEntry) error
+	DeleteSkillPlanEntries(ctx context.Context, planID uint) error
+	// ReplaceSkillPlanEntries atomically replaces the entries of the plan with the provided entries
+	ReplaceSkillPlanEntries(ctx context.Context, planID uint, entries []*SkillPlanEntry) error
+}
+
+type memberSkillSnapshotRepository interface {
+	CharacterSkillSnapshots(ctx context.Context, characterID uint64, from, to time.Time) ([]*CharacterSkillSnapshot, error)
+	CreateCharacterSkillSnapshot(ctx context.Context, snapshot *CharacterSkillSnapshot) error
+
+	CharacterSkillChanges(ctx context.Context, characterID uint64, from, to time.Time) ([]*CharacterSkillChange, error)
+	CreateCharacterSkillChanges(ctx context.Context, changes []*CharacterSkillChange) error
+}
+
+type memberSkillCompletionRepository interface {
+	CharacterSkillCompletions(ctx context.Context, characterID uint64, limit uint64) ([]*CharacterSkillCompletion, error)
+	CreateCharacterSkillCompletions(ctx context.Context, completions []*CharacterSkillCompletion) error
+}
+
 type CharacterAttributes struct {
 	CharacterID              uint64    `db:"character_id" json:"character_id"`
 	Charisma                 uint      `db:"charisma" json:"charisma"`
@@ -56,9 +87,50 @@ type CharacterAttributes struct {
 	UpdatedAt                time.Time `db:"updated_at" json:"-" deep:"-"`
 }
 
+const (
+	SkillRankAttributeID               uint = 275
+	SkillPrimaryAttributeAttributeID   uint = 180
+	SkillSecondaryAttributeAttributeID uint = 181
+
+	CharismaAttributeID     uint = 164
+	IntelligenceAttributeID uint = 165
+	MemoryAttributeID       uint = 166
+	PerceptionAttributeID   uint = 167
+	WillpowerAttributeID    uint = 168
+
+	CharismaBonusAttributeID     uint = 175
+	IntelligenceBonusAttributeID uint = 176
+	MemoryBonusAttributeID       uint = 177
+	PerceptionBonusAttributeID   uint = 178
+	WillpowerBonusAttributeID    uint = 179
+)
+
+// AttributeValue returns the value of the attribute identified by the dogma attribute id
+// that skills reference as their primary and secondary attributes
+func (a *CharacterAttributes) AttributeValue(attributeID uint) uint {
+	switch attributeID {
+	case CharismaAttributeID:
+		return a.Charisma
+	case IntelligenceAttributeID:
+		return a.Intelligence
+	case MemoryAttributeID:
+		return a.Memory
+	case PerceptionAttributeID:
+		return a.Perception
+	case WillpowerAttributeID:
+		return a.Willpower
+	}
+	return 0
+}
+
 type CharacterSkillQueueSummary struct {
-	Summary []*QueueGroupSummary   `json:"summary"`
-	Queue   []*CharacterSkillQueue `json:"queue"`
+	Summary  []*QueueGroupSummary   `json:"summary"`
+	Queue    []*CharacterSkillQueue `json:"queue"`
+	Duration time.Duration          `json:"duration"`
+
+	// ImplantTimeSaved is how much sooner the queue finishes because of the
+	// attribute bonuses of the character's active implants
+	ImplantTimeSaved time.Duration `json:"implant_time_saved"`
 }
 
 type QueueGroupSummary struct {
@@ -80,6 +152,14 @@ type CharacterSkillQueue struct {
 	FinishDate      null.Time `db:"finish_date,omitempty" json:"finish_date,omitempty"`
 	CreatedAt       time.Time `db:"created_at" json:"-" deep:"-"`
 
+	// Duration is the time required to train this position. It is derived from the ESI
+	// dates when they are available and calculated from the character's attributes when they are not
+	Duration time.Duration `json:"duration"`
+
+	// MissingPrerequisites are the prerequisites of this position that are neither
+	// trained nor queued in an earlier position
+	MissingPrerequisites []*SkillRequirement `json:"missing_prerequisites,omitempty"`
+
 	Type *Type `json:"info"`
 }
 
@@ -117,8 +197,101 @@ type SkillGroup struct {
 
 type SkillType struct {
 	*Type
-	Skill *CharacterSkill     `json:"skill"`
-	Rank  *TypeDogmaAttribute `json:"rank"`
+	Skill    *CharacterSkill     `json:"skill"`
+	Rank     *TypeDogmaAttribute `json:"rank"`
+	Training *SkillTrainingTime  `json:"training,omitempty"`
+}
+
+// SkillTrainingTime describes how quickly a character trains a skill
+// with their current attributes
+type SkillTrainingTime struct {
+	SkillID         uint          `json:"skill_id"`
+	SPPerMinute     float64       `json:"sp_per_minute"`
+	TimeToNextLevel time.Duration `json:"time_to_next_level"`
+	TimeToLevelV    time.Duration `json:"time_to_level_v"`
+}
+
+// RemapRecommendation is the attribute remap that trains a skill queue or skill plan
+// in the least amount of time
+type RemapRecommendation struct {
+	Attributes      *CharacterAttributes `json:"attributes"`
+	CurrentDuration time.Duration        `json:"current_duration"`
+	OptimalDuration time.Duration        `json:"optimal_duration"`
+	TimeSaved       time.Duration        `json:"time_saved"`
+
+	// RemapAvailable is true when the character has a bonus remap or their yearly
+	// remap has cooled down. When it is false, AvailableAt is when the cooldown ends
+	RemapAvailable bool      `json:"remap_available"`
+	BonusRemaps    uint      `json:"bonus_remaps"`
+	AvailableAt    null.Time `json:"available_at,omitempty"`
+}
+
+// SkillRequirement is a skill and the level it must be trained to
+type SkillRequirement struct {
+	SkillID uint `json:"skill_id"`
+	Level   uint `json:"level"`
+
+	Type *Type `json:"info,omitempty"`
+}
+
+// CharacterSkillSnapshot is the total skillpoints of a character at the time
+// their skills were processed. Changes are the skills that changed since the previous snapshot
+type CharacterSkillSnapshot struct {
+	ID            uint64    `db:"id" json:"id"`
+	CharacterID   uint64    `db:"character_id" json:"character_id"`
+	TotalSP       uint      `db:"total_sp" json:"total_sp"`
+	UnallocatedSP null.Uint `db:"unallocated_sp,omitempty" json:"unallocated_sp,omitempty"`
+	CreatedAt     time.Time `db:"created_at" json:"created_at"`
+
+	Changes []*CharacterSkillChange `json:"changes,omitempty"`
+}
+
+// CharacterSkillChange is a skill whose level or skillpoints changed between two snapshots
+type CharacterSkillChange struct {
+	SnapshotID          uint64    `db:"snapshot_id" json:"snapshot_id"`
+	CharacterID         uint64    `db:"character_id" json:"character_id"`
+	SkillID             uint      `db:"skill_id" json:"skill_id"`
+	PreviousSkillLevel  uint      `db:"previous_skill_level" json:"previous_skill_level"`
+	TrainedSkillLevel   uint      `db:"trained_skill_level" json:"trained_skill_level"`
+	PreviousSkillpoints uint      `db:"previous_skillpoints" json:"previous_skillpoints"`
+	SkillpointsInSkill  uint      `db:"skillpoints_in_skill" json:"skillpoints_in_skill"`
+	CreatedAt           time.Time `db:"created_at" json:"created_at"`
+
+	Type *Type `json:"info,omitempty"`
+}
+
+// CharacterSkillCompletion is a skill level that a character finished training
+type CharacterSkillCompletion struct {
+	CharacterID uint64    `db:"character_id" json:"character_id"`
+	SkillID     uint      `db:"skill_id" json:"skill_id"`
+	Level       uint      `db:"level" json:"level"`
+	CompletedAt time.Time `db:"completed_at" json:"completed_at"`
+	CreatedAt   time.Time `db:"created_at" json:"-"`
+
+	Type *Type `json:"info,omitempty"`
+}
+
+// CharacterSkillTimeline is a character's skillpoint curve and the skill levels they trained between two dates
+type CharacterSkillTimeline struct {
+	From      time.Time                 `json:"from"`
+	To        time.Time                 `json:"to"`
+	Snapshots []*CharacterSkillSnapshot `json:"snapshots"`
+	LevelUps  []*CharacterSkillChange   `json:"level_ups"`
+}
+
+// FlightRequirements is what a character is missing to be able to fly a ship
+type FlightRequirements struct {
+	ShipID  uint  `json:"ship_id"`
+	Ship    *Type `json:"ship,omitempty"`
+	Flyable bool  `json:"flyable"`
+
+	// Requirements are the skills the ship directly requires. Missing is every skill level,
+	// including nested prerequisites, that the character must train, in the order to train them
+	Requirements []*SkillRequirement `json:"requirements"`
+	Missing      []*SkillPlanEntry   `json:"missing"`
+
+	Skillpoints uint          `json:"skillpoints"`
+	Duration    time.Duration `json:"duration"`
 }
 
 // CharacterFlyableShip is a model of the database table
@@ -138,3 +311,34 @@ type ShipType struct {
 	*Type
 	Flyable bool
 }
+
+// SkillPlan is a named, ordered list of skill levels a user intends to train
+type SkillPlan struct {
+	ID        uint      `db:"id" json:"id"`
+	UserID    string    `db:"user_id" json:"user_id"`
+	Name      string    `db:"name" json:"name"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+
+	// Skillpoints and Duration are the totals of the plan's entries, calculated
+	// against the character's current skills and attributes
+	Skillpoints uint          `json:"skillpoints"`
+	Duration    time.Duration `json:"duration"`
+
+	Entries []*SkillPlanEntry `json:"entries"`
+}
+
+type SkillPlanEntry struct {
+	PlanID    uint      `db:"plan_id" json:"plan_id"`
+	Position  uint      `db:"position" json:"position"`
+	SkillID   uint      `db:"skill_id" json:"skill_id"`
+	Level     uint      `db:"level" json:"level"`
+	CreatedAt time.Time `db:"created_at" json:"-"`
+
+	// Skillpoints and Duration are what remains to be trained for this entry once all
+	// of the entries before it in the plan have been trained
+	Skillpoints uint          `json:"skillpoints"`
+	Duration    time.Duration `json:"duration"`
+
+	Type *Type `json:"info,omitempty"`
+}
@@ -67,6 +67,10 @@ type UniverseRepository interface {
 	TypeDogmaAttributesBulk(ctx context.Context, typeIDs []uint) ([]*TypeDogmaAttribute, error)
 	CreateTypeDogmaAttributes(ctx context.Context, attributes []*TypeDogmaAttribute) error
 	DeleteTypeDogmaAttributes(ctx context.Context, typeID uint) error
+
+	ShipFlightRequirements(ctx context.Context, shipID uint) ([]*ShipFlightRequirement, error)
+	CreateShipFlightRequirements(ctx context.Context, requirements []*ShipFlightRequirement) error
+	DeleteShipFlightRequirements(ctx context.Context, shipID uint) error
 }
 
 type Bloodline struct {
@@ -223,3 +227,12 @@ type TypeDogmaAttribute struct {
 	Value       float64   `db:"value" json:"value"`
 	CreatedAt   time.Time `db:"created_at" json:"-"`
 }
+
+// ShipFlightRequirement is a skill and the minimum level of it that is required to fly a ship
+type ShipFlightRequirement struct {
+	GroupID           uint      `db:"group_id" json:"group_id"`
+	ShipID            uint      `db:"ship_id" json:"ship_id"`
+	SkillID           uint      `db:"skill_id" json:"skill_id"`
+	MinimumSkillLevel uint      `db:"minimum_skill_level" json:"minimum_skill_level"`
+	CreatedAt         time.Time `db:"created_at" json:"-"`
+}
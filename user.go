@@ -20,7 +20,66 @@ type UserRepository interface {
 
 	UsersSortedByProcessedAtLimit(ctx context.Context) ([]*User, error)
 
+	UserAccount(ctx context.Context, userID string) (*UserAccount, error)
+	UserAccountsByAccountID(ctx context.Context, accountID string) ([]*UserAccount, error)
+	CreateUserAccount(ctx context.Context, account *UserAccount) error
+	DeleteUserAccount(ctx context.Context, userID string) error
+
+	UserShareLink(ctx context.Context, token string) (*UserShareLink, error)
+	UserShareLinks(ctx context.Context, userID string) ([]*UserShareLink, error)
+	CreateUserShareLink(ctx context.Context, link *UserShareLink) error
+	DeleteUserShareLink(ctx context.Context, userID, token string) error
+	UserShareLinkViews(ctx context.Context, token string) ([]*UserShareLinkView, error)
+	CreateUserShareLinkView(ctx context.Context, view *UserShareLinkView) error
+
 	NewUsersBySP(ctx context.Context) ([]*User, error)
+
+	// UsersSharingWithCorporation returns the users that have opted in to sharing their skills with
+	// their corporation whose character is a member of the corporation or, when valid, the alliance
+	UsersSharingWithCorporation(ctx context.Context, corporationID uint, allianceID null.Uint) ([]*User, error)
+}
+
+// UserAccount links a user to the account of a player that has logged in with more than one
+// of their characters. OwnerHash is the owner hash of the user's character when it was linked. The
+// link is no longer valid once the character's owner hash changes, i.e. the character was transferred
+type UserAccount struct {
+	UserID    string    `db:"user_id" json:"user_id"`
+	AccountID string    `db:"account_id" json:"account_id"`
+	OwnerHash string    `db:"owner_hash" json:"-"`
+	CreatedAt time.Time `db:"created_at" json:"-"`
+}
+
+// UserShareLink grants anybody holding its token a view of the shared sections of a user's
+// skillboard until the link expires, regardless of the user's visibility settings
+type UserShareLink struct {
+	Token         string    `db:"token" json:"token"`
+	UserID        string    `db:"user_id" json:"user_id"`
+	Label         string    `db:"label" form:"label" json:"label"`
+	ShareSkills   bool      `db:"share_skills" form:"share_skills" json:"share_skills"`
+	ShareQueue    bool      `db:"share_queue" form:"share_queue" json:"share_queue"`
+	ShareImplants bool      `db:"share_implants" form:"share_implants" json:"share_implants"`
+	ShareFlyable  bool      `db:"share_flyable" form:"share_flyable" json:"share_flyable"`
+	ExpiresAt     time.Time `db:"expires_at" json:"expires_at"`
+	CreatedAt     time.Time `db:"created_at" json:"created_at"`
+
+	Views []*UserShareLinkView `json:"views,omitempty"`
+}
+
+func (l *UserShareLink) Expired() bool {
+	return !l.ExpiresAt.After(time.Now())
+}
+
+// UserShareLinkView records a visit to a share link. ViewerID is the user that
+// viewed the link when the visitor was logged in at the time
+type UserShareLinkView struct {
+	ID        uint64      `db:"id" json:"id"`
+	Token     string      `db:"token" json:"token"`
+	ViewerID  null.String `db:"viewer_id" json:"viewer_id"`
+	IPAddress string      `db:"ip_address" json:"ip_address"`
+	UserAgent string      `db:"user_agent" json:"user_agent"`
+	ViewedAt  time.Time   `db:"viewed_at" json:"viewed_at"`
+
+	Viewer *User `json:"viewer,omitempty"`
 }
 
 type RecentUsers struct {
@@ -57,6 +116,14 @@ type User struct {
 	Flyable       []*ShipGroup                `json:"flyable,omitempty"`
 	Meta          *CharacterSkillMeta         `json:"meta,omitempty"`
 	Implants      []*CharacterImplant         `json:"implants,omitempty"`
+	Contacts      []*CharacterContact         `json:"contacts,omitempty"`
+	JumpClones    []*CharacterJumpClone       `json:"jump_clones,omitempty"`
+
+	// EffectiveAttributes are the character's attributes including the bonuses of their active implants
+	EffectiveAttributes *CharacterAttributes `json:"effective_attributes,omitempty"`
+
+	// Timeline is the character's skillpoint curve and level ups over the last 30 days
+	Timeline *CharacterSkillTimeline `json:"timeline,omitempty"`
 }
 
 func (i *User) ApplyToken(t *oauth2.Token) {
@@ -107,8 +174,12 @@ type UserSettings struct {
 	HideAttributes  bool       `db:"hide_attributes" form:"hide_attributes" json:"hide_attributes"`
 	HideFlyable     bool       `db:"hide_flyable" form:"hide_flyable" json:"hide_flyable"`
 	HideImplants    bool       `db:"hide_implants" form:"hide_settings" json:"hide_settings"`
+	HideStandings   bool       `db:"hide_standings" form:"hide_standings" json:"hide_standings"`
 	CreatedAt       time.Time  `db:"created_at" json:"-" form:"-"`
 	UpdatedAt       time.Time  `db:"updated_at" json:"-" form:"-"`
+
+	// ShareWithCorporation opts the user in to the doctrine reports of their corporation and alliance
+	ShareWithCorporation bool `db:"share_with_corporation" form:"share_with_corporation" json:"share_with_corporation"`
 }
 
 type UserSearchResult struct {